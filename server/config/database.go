@@ -1,34 +1,50 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
 
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
+	"estore-server/migrations"
 	"estore-server/models"
+	"estore-server/service/impl"
 )
 
 // DatabaseConfig holds database configuration parameters
 type DatabaseConfig struct {
+	Driver   string
 	Host     string
 	Port     string
 	Username string
 	Password string
 	Database string
+	SSLMode  string
 }
 
-// getDatabaseConfig returns database configuration from environment variables or defaults
+// getDatabaseConfig returns database configuration from environment variables or defaults.
+// Ports and users default to each server's usual values.
 func getDatabaseConfig() DatabaseConfig {
+	driver := getEnvOrDefault("DB_DRIVER", "mysql")
+	port, username := "3306", "root"
+	if driver == "postgres" {
+		port, username = "5432", "postgres"
+	}
+
 	return DatabaseConfig{
+		Driver:   driver,
 		Host:     getEnvOrDefault("DB_HOST", "localhost"),
-		Port:     getEnvOrDefault("DB_PORT", "3306"),
-		Username: getEnvOrDefault("DB_USER", "root"),
+		Port:     getEnvOrDefault("DB_PORT", port),
+		Username: getEnvOrDefault("DB_USER", username),
 		Password: os.Getenv("DB_PASSWORD"),
 		Database: os.Getenv("DB_NAME"),
+		SSLMode:  getEnvOrDefault("DB_SSLMODE", "disable"),
 	}
 }
 
@@ -40,18 +56,40 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// ConnectDatabase opens the database selected by DB_DRIVER: "mysql" (default), "postgres"
+// or "sqlite". For SQLite, DB_NAME is the database file (default estore.db), or ":memory:"
+// for a throwaway in-memory database.
 func ConnectDatabase() *gorm.DB {
 	config := getDatabaseConfig()
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		config.Username,
-		config.Password,
-		config.Host,
-		config.Port,
-		config.Database,
-	)
+	var dialector gorm.Dialector
+	switch config.Driver {
+	case "mysql":
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			config.Username,
+			config.Password,
+			config.Host,
+			config.Port,
+			config.Database,
+		)
+		dialector = mysql.Open(dsn)
+	case "postgres":
+		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+			config.Host,
+			config.Port,
+			config.Username,
+			config.Password,
+			config.Database,
+			config.SSLMode,
+		)
+		dialector = postgres.Open(dsn)
+	case "sqlite":
+		dialector = sqlite.Open(sqliteDSN(config.Database))
+	default:
+		log.Fatalf("Unsupported DB_DRIVER %q", config.Driver)
+	}
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+	db, err := gorm.Open(dialector, &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
 
@@ -59,15 +97,62 @@ func ConnectDatabase() *gorm.DB {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
+	if config.Driver == "sqlite" {
+		sqlDB, err := db.DB()
+		if err != nil {
+			log.Fatal("Failed to connect to database:", err)
+		}
+		// Every connection to ":memory:" would get its own empty database, and SQLite
+		// allows a single writer anyway
+		sqlDB.SetMaxOpenConns(1)
+	}
+
+	// Record when roles are assigned instead of using a bare join table
+	if err := db.SetupJoinTable(&models.User{}, "Roles", &models.UserRole{}); err != nil {
+		log.Fatal("Failed to set up user roles:", err)
+	}
+
 	return db
 }
 
-func MigrateDatabase(db *gorm.DB) {
-	err := db.AutoMigrate(
-		&models.User{},
-		&models.UserAuth{},
-	)
+// sqliteDSN turns DB_NAME into a SQLite DSN with foreign keys enforced, so deletes cascade
+// like they do on the other databases
+func sqliteDSN(name string) string {
+	if name == "" {
+		name = "estore.db"
+	}
+	if name == ":memory:" {
+		return "file::memory:?_foreign_keys=on"
+	}
+	return "file:" + name + "?_foreign_keys=on&_busy_timeout=5000"
+}
+
+// EnsureSchema refuses to start the server unless every migration has been applied. With
+// DB_AUTO_MIGRATE=true it applies pending migrations first, which an in-memory SQLite
+// database needs on every start.
+func EnsureSchema(db *gorm.DB) {
+	if getEnvOrDefault("DB_AUTO_MIGRATE", "false") == "true" {
+		applied, err := migrations.Up(db)
+		for _, m := range applied {
+			log.Printf("Applied migration %d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal("Failed to migrate database:", err)
+		}
+	}
+
+	err := migrations.Check(db)
+	if errors.Is(err, migrations.ErrPending) {
+		log.Fatalf("Refusing to start: %v; run \"go run . migrate up\" first", err)
+	}
 	if err != nil {
-		log.Fatal("Failed to migrate database:", err)
+		log.Fatal("Refusing to start:", err)
+	}
+}
+
+// SeedDatabase creates the reference data the server needs, such as the built-in roles
+func SeedDatabase(db *gorm.DB) {
+	if err := impl.NewRoleServiceImpl(db).SeedDefaultRoles(); err != nil {
+		log.Fatal("Failed to seed roles:", err)
 	}
 }
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"estore-server/jobs"
+	"estore-server/mail"
+	"estore-server/search"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/storage"
+)
+
+// pruneSchedule is how often finished jobs and dispatched webhook events are pruned
+const pruneSchedule = "@daily"
+
+// defaultJobWorkers is how many jobs one server runs at a time unless JOB_WORKERS says otherwise
+const defaultJobWorkers = 4
+
+// StartJobs starts the background job runner with JOB_WORKERS workers and registers the
+// app's jobs and recurring schedules. Servers sharing a database share the queue, and
+// each job runs on only one of them.
+func StartJobs(db *gorm.DB, store storage.BlobStore, index search.SearchIndex, mailer mail.Mailer) *jobs.Runner {
+	workers := defaultJobWorkers
+	if raw := getEnvOrDefault("JOB_WORKERS", ""); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			log.Fatalf("Invalid JOB_WORKERS %q", raw)
+		}
+		workers = n
+	}
+
+	runner := jobs.NewRunner(db, workers)
+	registerTrashPurge(runner, db, store, index)
+	registerWebhookJobs(runner, db)
+	registerPasswordResetJob(runner, db, mailer)
+	registerJobPrune(runner, db)
+	runner.Start(context.Background())
+	return runner
+}
+
+// registerJobPrune schedules the job that deletes succeeded jobs once they are older than
+// JOB_RETENTION_DAYS (default 7), so the jobs table does not grow without bound
+func registerJobPrune(runner *jobs.Runner, db *gorm.DB) {
+	retention := retentionFromEnv("JOB_RETENTION_DAYS", service.DefaultJobRetention)
+	jobService := impl.NewJobServiceImpl(db)
+	runner.Register("jobs.prune", func(ctx context.Context, payload json.RawMessage) error {
+		pruned, err := jobService.PruneJobs(ctx, service.SystemActor("job:jobs.prune"), time.Now().Add(-retention))
+		if err != nil {
+			return err
+		}
+		if pruned > 0 {
+			log.Printf("jobs: pruned %d succeeded jobs", pruned)
+		}
+		return nil
+	})
+	if err := runner.Schedule("jobs.prune", pruneSchedule, "jobs.prune", nil); err != nil {
+		log.Fatal("Failed to schedule job pruning:", err)
+	}
+}
+
+// retentionFromEnv reads a retention period in days from the environment variable key
+func retentionFromEnv(key string, fallback time.Duration) time.Duration {
+	raw := getEnvOrDefault(key, "")
+	if raw == "" {
+		return fallback
+	}
+	days, err := strconv.Atoi(raw)
+	if err != nil || days < 0 {
+		log.Fatalf("Invalid %s %q", key, raw)
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
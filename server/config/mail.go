@@ -0,0 +1,52 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"gorm.io/gorm"
+
+	"estore-server/jobs"
+	"estore-server/mail"
+	"estore-server/service"
+	"estore-server/service/impl"
+)
+
+// ConnectMailer builds the mailer selected by MAIL_DRIVER. The default "log" driver
+// writes messages to MAIL_LOG_FILE (or the server log) for local development.
+func ConnectMailer() mail.Mailer {
+	driver := getEnvOrDefault("MAIL_DRIVER", "log")
+
+	switch driver {
+	case "log":
+		return mail.NewLogMailer(getEnvOrDefault("MAIL_LOG_FILE", ""))
+	case "smtp":
+		host := getEnvOrDefault("SMTP_HOST", "")
+		if host == "" {
+			log.Fatal("SMTP_HOST environment variable is required for the smtp mail driver")
+		}
+		return mail.NewSMTPMailer(
+			host,
+			getEnvOrDefault("SMTP_PORT", "587"),
+			getEnvOrDefault("SMTP_USERNAME", ""),
+			getEnvOrDefault("SMTP_PASSWORD", ""),
+			getEnvOrDefault("MAIL_FROM", "no-reply@estore.local"),
+		)
+	default:
+		log.Fatalf("Unsupported MAIL_DRIVER %q", driver)
+		return nil
+	}
+}
+
+// registerPasswordResetJob registers the job that mails password reset tokens
+func registerPasswordResetJob(runner *jobs.Runner, db *gorm.DB, mailer mail.Mailer) {
+	resets := impl.NewPasswordResetServiceImpl(db, mailer, nil)
+	runner.Register(service.JobPasswordResetEmail, func(ctx context.Context, payload json.RawMessage) error {
+		var job service.PasswordResetJob
+		if err := json.Unmarshal(payload, &job); err != nil {
+			return err
+		}
+		return resets.SendResets(ctx, job.Email)
+	})
+}
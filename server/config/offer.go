@@ -0,0 +1,24 @@
+package config
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"estore-server/service"
+)
+
+// OfferTTL returns how long an offer stays open without a response, from OFFER_TTL_HOURS
+// (default 48)
+func OfferTTL() time.Duration {
+	raw := getEnvOrDefault("OFFER_TTL_HOURS", "")
+	if raw == "" {
+		return service.DefaultOfferTTL
+	}
+
+	hours, err := strconv.Atoi(raw)
+	if err != nil || hours <= 0 {
+		log.Fatalf("Invalid OFFER_TTL_HOURS %q", raw)
+	}
+	return time.Duration(hours) * time.Hour
+}
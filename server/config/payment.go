@@ -0,0 +1,34 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+
+	"estore-server/payment"
+)
+
+// ConnectPaymentProvider builds the payment provider selected by PAYMENT_DRIVER. Only the
+// "fake" provider for demos ships today. It signs callbacks with PAYMENT_WEBHOOK_SECRET and
+// reaches this server at PAYMENT_BASE_URL (default http://localhost:$PORT).
+func ConnectPaymentProvider() payment.PaymentProvider {
+	driver := getEnvOrDefault("PAYMENT_DRIVER", "fake")
+
+	switch driver {
+	case "fake":
+		baseURL := getEnvOrDefault("PAYMENT_BASE_URL", "http://localhost:"+getEnvOrDefault("PORT", "8080"))
+		secret := getEnvOrDefault("PAYMENT_WEBHOOK_SECRET", "")
+		if secret == "" {
+			// The fake signs and verifies in this process, so a per-run secret is enough
+			buf := make([]byte, 32)
+			if _, err := rand.Read(buf); err != nil {
+				log.Fatal("Failed to generate payment webhook secret:", err)
+			}
+			secret = hex.EncodeToString(buf)
+		}
+		return payment.NewFakeProvider(secret, baseURL+"/api/payments/fake", baseURL+"/api/payments/webhook")
+	default:
+		log.Fatalf("Unsupported PAYMENT_DRIVER %q", driver)
+		return nil
+	}
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"estore-server/search"
+	"estore-server/service"
+	"estore-server/service/impl"
+)
+
+// searchPollInterval is how often a server checks whether a search index rebuild was requested
+const searchPollInterval = 15 * time.Second
+
+// defaultSearchRefresh is how often each server rebuilds its index unless SEARCH_REFRESH_INTERVAL says otherwise
+const defaultSearchRefresh = 5 * time.Minute
+
+// ConnectSearchIndex creates the embedded product search index, fills it from the database
+// and keeps it in step with the other servers. Each server holds its own copy and updates
+// it for the writes it handles itself; writes made through other servers show up when it
+// rebuilds every SEARCH_REFRESH_INTERVAL ("0" turns this off), and "search rebuild" makes
+// every server rebuild within searchPollInterval.
+func ConnectSearchIndex(db *gorm.DB) search.SearchIndex {
+	refresh := defaultSearchRefresh
+	if raw := getEnvOrDefault("SEARCH_REFRESH_INTERVAL", ""); raw != "" {
+		interval, err := time.ParseDuration(raw)
+		if err != nil || interval < 0 {
+			log.Fatalf("Invalid SEARCH_REFRESH_INTERVAL %q", raw)
+		}
+		refresh = interval
+	}
+
+	index := search.NewMemoryIndex()
+	products := impl.NewProductServiceImpl(db, nil, index, nil)
+
+	// Read the generation first, so a rebuild requested while building still gets applied
+	generation, err := products.SearchGeneration(context.Background())
+	if err != nil {
+		log.Fatal("Failed to read search index state:", err)
+	}
+	count, err := products.RebuildSearchIndex(service.SystemActor("startup"))
+	if err != nil {
+		log.Fatal("Failed to build search index:", err)
+	}
+	go products.WatchSearchIndex(context.Background(), generation, searchPollInterval, refresh)
+
+	log.Printf("Search index built with %d products", count)
+	return index
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"log"
+
+	"estore-server/storage"
+)
+
+// ConnectBlobStore builds the blob store selected by STORAGE_DRIVER.
+// Only the local filesystem driver ships today; S3-compatible stores plug in here.
+func ConnectBlobStore() storage.BlobStore {
+	driver := getEnvOrDefault("STORAGE_DRIVER", "local")
+
+	switch driver {
+	case "local":
+		store, err := storage.NewLocalBlobStore(
+			getEnvOrDefault("STORAGE_LOCAL_DIR", "uploads"),
+			getEnvOrDefault("STORAGE_BASE_URL", "/api/images"),
+		)
+		if err != nil {
+			log.Fatal("Failed to initialise local blob store:", err)
+		}
+		return store
+	default:
+		log.Fatalf("Unsupported STORAGE_DRIVER %q", driver)
+		return nil
+	}
+}
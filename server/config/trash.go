@@ -0,0 +1,40 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"estore-server/jobs"
+	"estore-server/search"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/storage"
+)
+
+// trashPurgeSchedule is how often the purge looks for expired trash
+const trashPurgeSchedule = "@hourly"
+
+// registerTrashPurge schedules the job that permanently removes soft-deleted products and
+// users once they have been in the trash longer than TRASH_RETENTION_DAYS (default 30).
+// It runs once when first scheduled and then hourly.
+func registerTrashPurge(runner *jobs.Runner, db *gorm.DB, store storage.BlobStore, index search.SearchIndex) {
+	retention := retentionFromEnv("TRASH_RETENTION_DAYS", service.DefaultTrashRetention)
+	trash := impl.NewTrashServiceImpl(db, store, index)
+	runner.Register("trash.purge", func(ctx context.Context, payload json.RawMessage) error {
+		result, err := trash.Purge(ctx, service.SystemActor("job:trash.purge"), time.Now().Add(-retention))
+		if err != nil {
+			return err
+		}
+		if result.Users > 0 || result.Products > 0 {
+			log.Printf("trash: purged %d users and %d products", result.Users, result.Products)
+		}
+		return nil
+	})
+	if err := runner.Schedule("trash.purge", trashPurgeSchedule, "trash.purge", nil); err != nil {
+		log.Fatal("Failed to schedule trash purge:", err)
+	}
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"estore-server/jobs"
+	"estore-server/service"
+	"estore-server/service/impl"
+)
+
+// registerWebhookJobs registers the jobs that fan outbox events out to webhook endpoints
+// and deliver them, and schedules pruning of events dispatched longer ago than
+// WEBHOOK_RETENTION_DAYS (default 30) together with their delivery logs
+func registerWebhookJobs(runner *jobs.Runner, db *gorm.DB) {
+	webhooks := impl.NewWebhookServiceImpl(db)
+	runner.Register(service.JobWebhookFanOut, func(ctx context.Context, payload json.RawMessage) error {
+		var job service.WebhookJob
+		if err := json.Unmarshal(payload, &job); err != nil {
+			return err
+		}
+		return webhooks.FanOut(ctx, job.EventID)
+	})
+	runner.Register(service.JobWebhookDeliver, func(ctx context.Context, payload json.RawMessage) error {
+		var job service.WebhookJob
+		if err := json.Unmarshal(payload, &job); err != nil {
+			return err
+		}
+		return webhooks.Deliver(ctx, job.DeliveryID)
+	})
+
+	retention := retentionFromEnv("WEBHOOK_RETENTION_DAYS", service.DefaultWebhookRetention)
+	runner.Register("webhook.prune", func(ctx context.Context, payload json.RawMessage) error {
+		pruned, err := webhooks.PruneEvents(ctx, service.SystemActor("job:webhook.prune"), time.Now().Add(-retention))
+		if err != nil {
+			return err
+		}
+		if pruned > 0 {
+			log.Printf("webhooks: pruned %d dispatched events", pruned)
+		}
+		return nil
+	})
+	if err := runner.Schedule("webhook.prune", pruneSchedule, "webhook.prune", nil); err != nil {
+		log.Fatal("Failed to schedule webhook pruning:", err)
+	}
+}
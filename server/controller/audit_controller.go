@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"estore-server/dto"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// AuditController serves the audit log to admins
+type AuditController struct {
+	AuditService service.AuditService
+}
+
+func NewAuditController(db *gorm.DB) *AuditController {
+	return &AuditController{
+		AuditService: impl.NewAuditServiceImpl(db),
+	}
+}
+
+// ListAuditLogs returns one page of audit entries, newest first, filtered by actor,
+// action, target and time range
+func (ac *AuditController) ListAuditLogs(c *gin.Context) {
+	var query dto.AuditLogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid query parameters"))
+		return
+	}
+
+	page, err := ac.AuditService.ListLogs(service.AuditFilter{
+		ActorID:    query.ActorID,
+		Action:     query.Action,
+		TargetType: query.TargetType,
+		TargetID:   query.TargetID,
+		Since:      query.Since,
+		Until:      query.Until,
+		Cursor:     query.Cursor,
+		Limit:      query.Limit,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	response := dto.NewPageResponse(page.Logs, page.Total, page.NextCursor)
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, response, "Audit logs retrieved successfully"))
+}
+
+// auditActor identifies the current request's user, address and request ID, which services
+// record in the audit log alongside each change they make
+func auditActor(c *gin.Context) service.Actor {
+	actor := service.Actor{IP: c.ClientIP(), RequestID: utils.GetRequestID(c)}
+	if user, err := utils.GetUserFromCtx(c); err == nil {
+		actor.UserID = &user.ID
+	}
+	return actor
+}
@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"estore-server/dto"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CartController coordinates shopping cart handlers for the current user
+type CartController struct {
+	CartService service.CartService
+}
+
+func NewCartController(db *gorm.DB) *CartController {
+	return &CartController{
+		CartService: impl.NewCartServiceImpl(db),
+	}
+}
+
+// GetCart lists the current user's cart priced against live products
+func (cc *CartController) GetCart(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	items, err := cc.CartService.ListItems(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, dto.NewCartResponse(items), "Cart retrieved successfully"))
+}
+
+// AddItem adds a product to the current user's cart
+func (cc *CartController) AddItem(c *gin.Context) {
+	var req dto.AddCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	item, err := cc.CartService.AddItem(auditActor(c), user.ID, req.ProductID, req.Quantity)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Product not found"))
+		case errors.Is(err, service.ErrCartOwnProduct):
+			c.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, err.Error()))
+		case errors.Is(err, service.ErrInsufficientStock):
+			c.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, err.Error()))
+		case errors.Is(err, service.ErrCartInvalidQuantity):
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewSuccessResponse(http.StatusCreated, item, "Item added to cart"))
+}
+
+// UpdateItem changes the quantity of an item in the current user's cart
+func (cc *CartController) UpdateItem(c *gin.Context) {
+	itemID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid cart item ID"))
+		return
+	}
+
+	var req dto.UpdateCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	item, err := cc.CartService.UpdateItemQuantity(auditActor(c), user.ID, itemID, req.Quantity)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Cart item not found"))
+		case errors.Is(err, service.ErrInsufficientStock):
+			c.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, err.Error()))
+		case errors.Is(err, service.ErrCartInvalidQuantity):
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, item, "Cart item updated successfully"))
+}
+
+// RemoveItem deletes an item from the current user's cart
+func (cc *CartController) RemoveItem(c *gin.Context) {
+	itemID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid cart item ID"))
+		return
+	}
+
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	if err := cc.CartService.RemoveItem(auditActor(c), user.ID, itemID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Cart item not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Cart item removed successfully"))
+}
+
+// ClearCart empties the current user's cart
+func (cc *CartController) ClearCart(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	if err := cc.CartService.ClearCart(auditActor(c), user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Cart cleared successfully"))
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"estore-server/dto"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CategoryController coordinates category browsing and admin management handlers
+type CategoryController struct {
+	CategoryService service.CategoryService
+}
+
+func NewCategoryController(db *gorm.DB) *CategoryController {
+	return &CategoryController{
+		CategoryService: impl.NewCategoryServiceImpl(db),
+	}
+}
+
+// GetCategoryTree returns all categories nested under their roots
+func (cc *CategoryController) GetCategoryTree(c *gin.Context) {
+	categories, err := cc.CategoryService.ListCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, dto.NewCategoryTree(categories), "Categories retrieved successfully"))
+}
+
+// CreateCategory adds a category to the tree (staff with category:manage)
+func (cc *CategoryController) CreateCategory(c *gin.Context) {
+	var req dto.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	category, err := cc.CategoryService.CreateCategory(auditActor(c), req.Name, req.Slug, req.ParentID, req.SortOrder)
+	if err != nil {
+		writeCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewSuccessResponse(http.StatusCreated, dto.NewCategoryResponse(category), "Category created successfully"))
+}
+
+// UpdateCategory renames, re-slugs, reorders or moves a category (staff with category:manage)
+func (cc *CategoryController) UpdateCategory(c *gin.Context) {
+	categoryID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid category ID"))
+		return
+	}
+
+	var req dto.UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	category, err := cc.CategoryService.UpdateCategory(auditActor(c), categoryID, req.Name, req.Slug, req.ParentID, req.SortOrder)
+	if err != nil {
+		writeCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, dto.NewCategoryResponse(category), "Category updated successfully"))
+}
+
+// DeleteCategory removes a category (staff with category:manage); products in it must be moved
+// elsewhere with the reassign_to query parameter
+func (cc *CategoryController) DeleteCategory(c *gin.Context) {
+	categoryID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid category ID"))
+		return
+	}
+
+	var reassignTo *uint
+	if param := c.Query("reassign_to"); param != "" {
+		targetID, err := utils.ParseUintParam(param)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid reassignment category ID"))
+			return
+		}
+		reassignTo = &targetID
+	}
+
+	if err := cc.CategoryService.DeleteCategory(auditActor(c), categoryID, reassignTo); err != nil {
+		writeCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Category deleted successfully"))
+}
+
+// writeCategoryError maps category service errors to HTTP responses
+func writeCategoryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Category not found"))
+	case errors.Is(err, service.ErrCategorySlugTaken), errors.Is(err, service.ErrCategoryInUse):
+		c.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, err.Error()))
+	case errors.Is(err, service.ErrCategoryInvalidSlug),
+		errors.Is(err, service.ErrCategoryParentNotFound),
+		errors.Is(err, service.ErrCategoryCycle),
+		errors.Is(err, service.ErrCategoryInvalidTarget):
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+}
@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"estore-server/dto"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// FavoriteController coordinates the current user's watchlist handlers
+type FavoriteController struct {
+	FavoriteService service.FavoriteService
+}
+
+func NewFavoriteController(db *gorm.DB) *FavoriteController {
+	return &FavoriteController{
+		FavoriteService: impl.NewFavoriteServiceImpl(db),
+	}
+}
+
+// ListFavorites returns the products on the current user's watchlist, most recently added first
+func (fc *FavoriteController) ListFavorites(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	products, err := fc.FavoriteService.ListFavorites(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	response := make([]dto.ProductResponse, 0, len(products))
+	for i := range products {
+		product := dto.NewProductResponse(&products[i])
+		product.IsFavorited = true
+		response = append(response, product)
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, response, "Favorites retrieved successfully"))
+}
+
+// AddFavorite puts a product on the current user's watchlist
+func (fc *FavoriteController) AddFavorite(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid product ID"))
+		return
+	}
+
+	if err := fc.FavoriteService.AddFavorite(auditActor(c), user.ID, productID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Product not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Product added to favorites"))
+}
+
+// RemoveFavorite takes a product off the current user's watchlist
+func (fc *FavoriteController) RemoveFavorite(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid product ID"))
+		return
+	}
+
+	if err := fc.FavoriteService.RemoveFavorite(auditActor(c), user.ID, productID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Product is not in favorites"))
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Product removed from favorites"))
+}
+
+// markFavorited sets IsFavorited on the products the current user has favorited
+func markFavorited(c *gin.Context, favorites service.FavoriteService, products []dto.ProductResponse) error {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil || len(products) == 0 {
+		return nil
+	}
+
+	productIDs := make([]uint, 0, len(products))
+	for _, product := range products {
+		productIDs = append(productIDs, product.ID)
+	}
+
+	favorited, err := favorites.FavoritedProductIDs(user.ID, productIDs)
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		products[i].IsFavorited = favorited[products[i].ID]
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"estore-server/dto"
+	"estore-server/models"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// JobController lets admins inspect background jobs
+type JobController struct {
+	JobService service.JobService
+}
+
+func NewJobController(db *gorm.DB) *JobController {
+	return &JobController{
+		JobService: impl.NewJobServiceImpl(db),
+	}
+}
+
+// ListJobs returns one page of jobs, newest first, filtered by status and type
+func (jc *JobController) ListJobs(c *gin.Context) {
+	var query dto.JobListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid query parameters"))
+		return
+	}
+	status := models.JobStatus(query.Status)
+	if status != "" && !status.IsValid() {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid job status"))
+		return
+	}
+
+	page, err := jc.JobService.ListJobs(service.JobFilter{
+		Status: status,
+		Type:   query.Type,
+		Cursor: query.Cursor,
+		Limit:  query.Limit,
+	})
+	if err != nil {
+		writeJobError(c, err)
+		return
+	}
+
+	response := dto.NewPageResponse(page.Jobs, page.Total, page.NextCursor)
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, response, "Jobs retrieved successfully"))
+}
+
+// GetJobStats returns how many jobs are in each status
+func (jc *JobController) GetJobStats(c *gin.Context) {
+	counts, err := jc.JobService.CountByStatus()
+	if err != nil {
+		writeJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, counts, "Job stats retrieved successfully"))
+}
+
+// ListSchedules returns the recurring job schedules with their next run times
+func (jc *JobController) ListSchedules(c *gin.Context) {
+	schedules, err := jc.JobService.ListSchedules()
+	if err != nil {
+		writeJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, schedules, "Job schedules retrieved successfully"))
+}
+
+// GetJob returns a single job with its last error
+func (jc *JobController) GetJob(c *gin.Context) {
+	jobID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid job ID"))
+		return
+	}
+
+	job, err := jc.JobService.GetJob(jobID)
+	if err != nil {
+		writeJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, job, "Job retrieved successfully"))
+}
+
+// RetryJob queues a dead job to run again
+func (jc *JobController) RetryJob(c *gin.Context) {
+	jobID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid job ID"))
+		return
+	}
+
+	job, err := jc.JobService.RetryJob(auditActor(c), jobID)
+	if err != nil {
+		writeJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, job, "Job queued for retry"))
+}
+
+// writeJobError maps job service errors to HTTP responses
+func writeJobError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Job not found"))
+	case errors.Is(err, service.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	case errors.Is(err, service.ErrJobNotDead):
+		c.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+}
@@ -0,0 +1,300 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"estore-server/dto"
+	"estore-server/middleware"
+	"estore-server/realtime"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/utils"
+
+	ginjwt "github.com/appleboy/gin-jwt/v3"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"gorm.io/gorm"
+)
+
+// WebSocket keep-alive: the server pings every pingPeriod and drops connections that
+// have not answered within pongWait
+const (
+	writeWait  = 10 * time.Second
+	pongWait   = 60 * time.Second
+	pingPeriod = pongWait * 9 / 10
+)
+
+var upgrader = websocket.Upgrader{
+	// Native clients send no Origin; browsers must come from an origin the API allows
+	CheckOrigin: func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || middleware.IsAllowedOrigin(origin)
+	},
+}
+
+// MessageController coordinates buyer–seller messaging handlers
+type MessageController struct {
+	MessageService service.MessageService
+	Broker         realtime.Broker
+	Guard          *middleware.StreamGuard
+}
+
+func NewMessageController(db *gorm.DB, broker realtime.Broker) *MessageController {
+	return &MessageController{
+		MessageService: impl.NewMessageServiceImpl(db, broker),
+		Broker:         broker,
+		Guard:          middleware.NewStreamGuard(db),
+	}
+}
+
+// ListConversations returns the current user's inbox, most recently active first
+func (mc *MessageController) ListConversations(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	summaries, err := mc.MessageService.ListConversations(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	response := make([]dto.ConversationResponse, 0, len(summaries))
+	for i := range summaries {
+		summary := &summaries[i]
+		response = append(response, dto.NewConversationResponse(&summary.Conversation, summary.LastMessage, summary.UnreadCount))
+	}
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, response, "Conversations retrieved successfully"))
+}
+
+// StartConversation opens a conversation with a product's seller, or returns the existing one
+func (mc *MessageController) StartConversation(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	var req dto.StartConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	started, err := mc.MessageService.StartConversation(auditActor(c), user.ID, req.ProductID)
+	if err != nil {
+		writeMessageError(c, err)
+		return
+	}
+
+	conversation, err := mc.MessageService.GetConversation(user.ID, started.ID)
+	if err != nil {
+		writeMessageError(c, err)
+		return
+	}
+
+	response := dto.NewConversationResponse(conversation, nil, 0)
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, response, "Conversation opened successfully"))
+}
+
+// UnreadCount returns how many received messages the current user has not read yet
+func (mc *MessageController) UnreadCount(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	count, err := mc.MessageService.UnreadCount(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, gin.H{"unread_count": count}, "Unread count retrieved successfully"))
+}
+
+// ListMessages returns one page of a conversation's history, newest first
+func (mc *MessageController) ListMessages(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	conversationID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid conversation ID"))
+		return
+	}
+
+	var query dto.MessageListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid query parameters"))
+		return
+	}
+
+	page, err := mc.MessageService.ListMessages(user.ID, conversationID, query.Cursor, query.Limit)
+	if err != nil {
+		writeMessageError(c, err)
+		return
+	}
+
+	// The total is left out: counting a long history on every page is not worth it
+	response := dto.NewPageResponse(page.Messages, 0, page.NextCursor)
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, response, "Messages retrieved successfully"))
+}
+
+// SendMessage posts a message to a conversation and delivers it live to both participants
+func (mc *MessageController) SendMessage(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	conversationID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid conversation ID"))
+		return
+	}
+
+	var req dto.SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	message, err := mc.MessageService.SendMessage(auditActor(c), user.ID, conversationID, req.Body)
+	if err != nil {
+		writeMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewSuccessResponse(http.StatusCreated, message, "Message sent successfully"))
+}
+
+// MarkRead marks received messages as read and sends the sender a read receipt
+func (mc *MessageController) MarkRead(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	conversationID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid conversation ID"))
+		return
+	}
+
+	var req dto.MarkReadRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+			return
+		}
+	}
+
+	receipt, err := mc.MessageService.MarkRead(auditActor(c), user.ID, conversationID, req.UpToID)
+	if err != nil {
+		writeMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, receipt, "Messages marked as read"))
+}
+
+// Stream upgrades the request to a WebSocket and pushes the current user's message events
+// (message.new and message.read) as JSON until the client disconnects. Messages are sent
+// through the REST endpoints; anything the client sends is ignored. The server closes the
+// socket with a policy violation when the access token expires, and within a ping period of
+// the session being revoked or the user suspended; clients reconnect with a fresh token.
+func (mc *MessageController) Stream(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade has already replied with an HTTP error
+		return
+	}
+	defer conn.Close()
+
+	sub := mc.Broker.Subscribe(user.ID)
+	defer sub.Close()
+
+	// Reading is needed to process pongs and to notice when the client goes away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadLimit(512)
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(pongWait))
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+	expiry := time.NewTimer(time.Until(mc.Guard.TokenExpiry(c)))
+	defer expiry.Stop()
+
+	// endSession tells the client why the server is hanging up
+	endSession := func(err error) {
+		message := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, err.Error())
+		conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(writeWait))
+	}
+
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := mc.Guard.Check(c); err != nil {
+				endSession(err)
+				return
+			}
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return
+			}
+		case <-expiry.C:
+			endSession(ginjwt.ErrExpiredToken)
+			return
+		case <-closed:
+			return
+		}
+	}
+}
+
+// writeMessageError maps message service errors to HTTP responses
+func writeMessageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Conversation or product not found"))
+	case errors.Is(err, service.ErrMessageOwnProduct), errors.Is(err, service.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	case errors.Is(err, service.ErrNotParticipant):
+		c.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+}
@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"estore-server/dto"
+	"estore-server/middleware"
+	"estore-server/realtime"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/utils"
+
+	ginjwt "github.com/appleboy/gin-jwt/v3"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// sseKeepAlive is how often an idle notification stream sends a comment line, so proxies
+// do not close it and the server notices clients that went away
+const sseKeepAlive = 30 * time.Second
+
+// sseSessionEnded is the event that tells a client its notification stream was closed
+// because its credentials are no longer valid
+const sseSessionEnded = "session.ended"
+
+// NotificationController coordinates in-app notification handlers
+type NotificationController struct {
+	NotificationService service.NotificationService
+	Broker              realtime.Broker
+	Guard               *middleware.StreamGuard
+}
+
+func NewNotificationController(db *gorm.DB, broker realtime.Broker) *NotificationController {
+	return &NotificationController{
+		NotificationService: impl.NewNotificationServiceImpl(db, broker),
+		Broker:              broker,
+		Guard:               middleware.NewStreamGuard(db),
+	}
+}
+
+// ListNotifications returns one page of the current user's notifications, newest first
+func (nc *NotificationController) ListNotifications(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	var query dto.NotificationListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid query parameters"))
+		return
+	}
+
+	page, err := nc.NotificationService.ListNotifications(user.ID, query.Unread, query.Cursor, query.Limit)
+	if err != nil {
+		writeNotificationError(c, err)
+		return
+	}
+
+	response := dto.NewPageResponse(page.Notifications, page.Total, page.NextCursor)
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, response, "Notifications retrieved successfully"))
+}
+
+// UnreadCount returns how many notifications the current user has not read
+func (nc *NotificationController) UnreadCount(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	count, err := nc.NotificationService.UnreadCount(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, gin.H{"unread_count": count}, "Unread count retrieved successfully"))
+}
+
+// MarkRead marks one of the current user's notifications as read
+func (nc *NotificationController) MarkRead(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	notificationID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid notification ID"))
+		return
+	}
+
+	notification, err := nc.NotificationService.MarkRead(user.ID, notificationID)
+	if err != nil {
+		writeNotificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, notification, "Notification marked as read"))
+}
+
+// MarkAllRead marks every notification of the current user as read
+func (nc *NotificationController) MarkAllRead(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	count, err := nc.NotificationService.MarkAllRead(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, gin.H{"count": count}, "Notifications marked as read"))
+}
+
+// Stream pushes the current user's new notifications as server-sent events until the
+// client disconnects. Each event is named notification.new and carries the notification
+// as JSON; clients that reconnect fetch what they missed from ListNotifications. When the
+// access token expires, or within a keep-alive period of the session being revoked or the
+// user suspended, a session.ended event carrying the reason closes the stream.
+func (nc *NotificationController) Stream(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	sub := nc.Broker.Subscribe(user.ID)
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Stop nginx and similar proxies from buffering the stream
+	c.Header("X-Accel-Buffering", "no")
+
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+	expiry := time.NewTimer(time.Until(nc.Guard.TokenExpiry(c)))
+	defer expiry.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return false
+			}
+			// The broker also carries message events; those go to the messaging WebSocket
+			if event.Type == service.EventNotificationNew {
+				c.SSEvent(event.Type, event.Data)
+			}
+			return true
+		case <-ticker.C:
+			if err := nc.Guard.Check(c); err != nil {
+				c.SSEvent(sseSessionEnded, err.Error())
+				return false
+			}
+			_, err := fmt.Fprint(w, ": keep-alive\n\n")
+			return err == nil
+		case <-expiry.C:
+			c.SSEvent(sseSessionEnded, ginjwt.ErrExpiredToken.Error())
+			return false
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// writeNotificationError maps notification service errors to HTTP responses
+func writeNotificationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Notification not found"))
+	case errors.Is(err, service.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+}
@@ -0,0 +1,179 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"estore-server/dto"
+	"estore-server/models"
+	"estore-server/realtime"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// OfferController coordinates price offer handlers
+type OfferController struct {
+	OfferService service.OfferService
+}
+
+func NewOfferController(db *gorm.DB, ttl time.Duration, broker realtime.Broker) *OfferController {
+	return &OfferController{
+		OfferService: impl.NewOfferServiceImpl(db, ttl, impl.NewNotificationServiceImpl(db, broker)),
+	}
+}
+
+// MakeOffer proposes a price for a product on behalf of the current user
+func (oc *OfferController) MakeOffer(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid product ID"))
+		return
+	}
+
+	var req dto.OfferPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	offer, err := oc.OfferService.MakeOffer(auditActor(c), user.ID, productID, req.Price)
+	if err != nil {
+		writeOfferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewSuccessResponse(http.StatusCreated, offer, "Offer made successfully"))
+}
+
+// ListSentOffers returns the offers the current user made as a buyer
+func (oc *OfferController) ListSentOffers(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	status, ok := parseOfferStatusQuery(c)
+	if !ok {
+		return
+	}
+
+	offers, err := oc.OfferService.ListBuyerOffers(user.ID, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, offers, "Offers retrieved successfully"))
+}
+
+// ListReceivedOffers returns the offers made on the current user's products
+func (oc *OfferController) ListReceivedOffers(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	status, ok := parseOfferStatusQuery(c)
+	if !ok {
+		return
+	}
+
+	offers, err := oc.OfferService.ListSellerOffers(user.ID, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, offers, "Offers retrieved successfully"))
+}
+
+// AcceptOffer agrees to the price of an offer waiting on the current user
+func (oc *OfferController) AcceptOffer(c *gin.Context) {
+	oc.respond(c, "Offer accepted successfully", oc.OfferService.AcceptOffer)
+}
+
+// RejectOffer declines an offer waiting on the current user
+func (oc *OfferController) RejectOffer(c *gin.Context) {
+	oc.respond(c, "Offer rejected successfully", oc.OfferService.RejectOffer)
+}
+
+// WithdrawOffer takes back the current user's open offer
+func (oc *OfferController) WithdrawOffer(c *gin.Context) {
+	oc.respond(c, "Offer withdrawn successfully", oc.OfferService.WithdrawOffer)
+}
+
+// CounterOffer answers an offer waiting on the current user with a different price
+func (oc *OfferController) CounterOffer(c *gin.Context) {
+	var req dto.OfferPriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	counter := func(actor service.Actor, userID, offerID uint) (*models.Offer, error) {
+		return oc.OfferService.CounterOffer(actor, userID, offerID, req.Price)
+	}
+	oc.respond(c, "Offer countered successfully", counter)
+}
+
+// respond runs one negotiation step on the offer in the path
+func (oc *OfferController) respond(c *gin.Context, message string, step func(actor service.Actor, userID, offerID uint) (*models.Offer, error)) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	offerID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid offer ID"))
+		return
+	}
+
+	offer, err := step(auditActor(c), user.ID, offerID)
+	if err != nil {
+		writeOfferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, offer, message))
+}
+
+// parseOfferStatusQuery reads the optional status filter, writing a 400 if it is unknown
+func parseOfferStatusQuery(c *gin.Context) (models.OfferStatus, bool) {
+	status := models.OfferStatus(c.Query("status"))
+	if status != "" && !status.IsValid() {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid offer status"))
+		return "", false
+	}
+	return status, true
+}
+
+// writeOfferError maps offer service errors to HTTP responses
+func writeOfferError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Offer or product not found"))
+	case errors.Is(err, service.ErrOfferInvalidPrice):
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	case errors.Is(err, service.ErrOfferOwnProduct), errors.Is(err, service.ErrOfferNotAllowed):
+		c.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, err.Error()))
+	case errors.Is(err, service.ErrOfferExists), errors.Is(err, service.ErrOfferClosed), errors.Is(err, service.ErrOfferExpired):
+		c.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+}
@@ -0,0 +1,265 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"estore-server/dto"
+	"estore-server/models"
+	"estore-server/realtime"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// OrderController coordinates order placement, listing and lifecycle handlers
+type OrderController struct {
+	OrderService service.OrderService
+}
+
+func NewOrderController(db *gorm.DB, broker realtime.Broker) *OrderController {
+	return &OrderController{
+		OrderService: impl.NewOrderServiceImpl(db, impl.NewNotificationServiceImpl(db, broker)),
+	}
+}
+
+// CreateOrder places an order for a single product
+func (oc *OrderController) CreateOrder(c *gin.Context) {
+	var req dto.CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	order, err := oc.OrderService.CreateOrder(auditActor(c), user.ID, req.ProductID, req.Quantity)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Product not found"))
+			return
+		}
+		writeOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewSuccessResponse(http.StatusCreated, order, "Order created successfully"))
+}
+
+// Checkout places orders for everything in the current user's cart
+func (oc *OrderController) Checkout(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	orders, err := oc.OrderService.CheckoutCart(auditActor(c), user.ID)
+	if err != nil {
+		writeOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewSuccessResponse(http.StatusCreated, orders, "Orders created successfully"))
+}
+
+// GetOrder returns a single order to its buyer, its seller or staff with order:read
+func (oc *OrderController) GetOrder(c *gin.Context) {
+	orderID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid order ID"))
+		return
+	}
+
+	requester, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	order, err := oc.OrderService.GetOrder(orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Order not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	if !canViewOrder(c, requester, order) {
+		c.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, "Unauthorized to view this order"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, order, "Order retrieved successfully"))
+}
+
+// ListPurchases lists orders the current user placed as a buyer
+func (oc *OrderController) ListPurchases(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	status, ok := parseOrderStatusQuery(c)
+	if !ok {
+		return
+	}
+
+	orders, err := oc.OrderService.ListBuyerOrders(user.ID, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, orders, "Orders retrieved successfully"))
+}
+
+// ListSales lists orders placed for the current user's products
+func (oc *OrderController) ListSales(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	status, ok := parseOrderStatusQuery(c)
+	if !ok {
+		return
+	}
+
+	orders, err := oc.OrderService.ListSellerOrders(user.ID, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, orders, "Orders retrieved successfully"))
+}
+
+// ListAllOrders lists every order in the system (admin only)
+func (oc *OrderController) ListAllOrders(c *gin.Context) {
+	// Checked here as well as on the route, so the listing never depends on route wiring alone
+	if !utils.HasPermission(c, models.PermOrderRead) {
+		c.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, "Unauthorized to list all orders"))
+		return
+	}
+
+	status, ok := parseOrderStatusQuery(c)
+	if !ok {
+		return
+	}
+
+	orders, err := oc.OrderService.ListAllOrders(status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, orders, "Orders retrieved successfully"))
+}
+
+// UpdateOrderStatus moves an order along its lifecycle.
+// Buyers complete, sellers ship, either side may cancel; staff with order:update may do anything.
+func (oc *OrderController) UpdateOrderStatus(c *gin.Context) {
+	orderID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid order ID"))
+		return
+	}
+
+	var req dto.UpdateOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	target := models.OrderStatus(req.Status)
+	if !target.IsValid() {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid order status"))
+		return
+	}
+
+	requester, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	order, err := oc.OrderService.GetOrder(orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Order not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	if !canTransitionOrder(c, requester, order, target) {
+		c.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, "Unauthorized to change this order's status"))
+		return
+	}
+
+	updatedOrder, err := oc.OrderService.TransitionOrder(auditActor(c), orderID, target)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Order not found"))
+			return
+		}
+		writeOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, updatedOrder, "Order status updated successfully"))
+}
+
+// canChangeOrderStatus reports whether a party to the order may move it to target. Nobody
+// marks their own order paid; that is left to the payment provider's verified callback.
+func canChangeOrderStatus(order *models.Order, userID uint, target models.OrderStatus) bool {
+	switch target {
+	case models.OrderStatusCompleted:
+		return order.BuyerID == userID
+	case models.OrderStatusShipped:
+		return order.SellerID == userID
+	case models.OrderStatusCancelled:
+		return order.BuyerID == userID || order.SellerID == userID
+	}
+	return false
+}
+
+// parseOrderStatusQuery reads the optional status filter, writing a 400 if it is unknown
+func parseOrderStatusQuery(c *gin.Context) (models.OrderStatus, bool) {
+	status := models.OrderStatus(c.Query("status"))
+	if status != "" && !status.IsValid() {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid order status"))
+		return "", false
+	}
+	return status, true
+}
+
+// writeOrderError maps order service errors to HTTP responses
+func writeOrderError(c *gin.Context, err error) {
+	var transitionErr *service.OrderTransitionError
+	switch {
+	case errors.As(err, &transitionErr):
+		c.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, err.Error()))
+	case errors.Is(err, service.ErrOrderOwnProduct):
+		c.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, err.Error()))
+	case errors.Is(err, service.ErrOrderEmpty), errors.Is(err, service.ErrOrderInvalidQuantity):
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	case errors.Is(err, service.ErrOrderProductUnavailable), errors.Is(err, service.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+}
@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"estore-server/dto"
+	"estore-server/models"
+	"estore-server/payment"
+	"estore-server/realtime"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// PaymentController coordinates payment handlers and the provider webhook
+type PaymentController struct {
+	PaymentService service.PaymentService
+	Provider       payment.PaymentProvider
+}
+
+func NewPaymentController(db *gorm.DB, provider payment.PaymentProvider, broker realtime.Broker) *PaymentController {
+	return &PaymentController{
+		PaymentService: impl.NewPaymentServiceImpl(db, provider, impl.NewNotificationServiceImpl(db, broker)),
+		Provider:       provider,
+	}
+}
+
+// CreatePayment starts paying for one of the current user's pending orders
+func (pc *PaymentController) CreatePayment(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	orderID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid order ID"))
+		return
+	}
+
+	record, err := pc.PaymentService.CreatePayment(auditActor(c), user.ID, orderID)
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewSuccessResponse(http.StatusCreated, record, "Payment created successfully"))
+}
+
+// GetPayment returns a payment to its payer or to staff who may read orders
+func (pc *PaymentController) GetPayment(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	paymentID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid payment ID"))
+		return
+	}
+
+	record, err := pc.PaymentService.GetPayment(paymentID)
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+	if record.PayerID != user.ID && !utils.HasPermission(c, models.PermOrderRead) {
+		c.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, "Unauthorized to view this payment"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, record, "Payment retrieved successfully"))
+}
+
+// Webhook receives the payment provider's signed callbacks. The raw body is needed to
+// check the signature, so it is read before any decoding.
+func (pc *PaymentController) Webhook(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	record, err := pc.PaymentService.HandleCallback(auditActor(c), c.Request.Header, body)
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, record, "Payment callback processed successfully"))
+}
+
+// FakeCheckout plays the payer finishing checkout on the fake provider's payment page
+func (pc *PaymentController) FakeCheckout(c *gin.Context) {
+	fake, ok := pc.Provider.(*payment.FakeProvider)
+	if !ok {
+		c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Payment intent not found"))
+		return
+	}
+
+	var req dto.FakeCheckoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+			return
+		}
+	}
+	status := payment.CallbackSucceeded
+	if req.Status != "" {
+		status = payment.CallbackStatus(req.Status)
+	}
+
+	if err := fake.Complete(c.Request.Context(), c.Param("intent"), status); err != nil {
+		switch {
+		case errors.Is(err, payment.ErrUnknownIntent):
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Payment intent not found"))
+		case errors.Is(err, payment.ErrIntentCompleted):
+			c.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, err.Error()))
+		default:
+			c.JSON(http.StatusBadGateway, dto.NewErrorResponse(http.StatusBadGateway, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Checkout completed successfully"))
+}
+
+// writePaymentError maps payment service errors to HTTP responses
+func writePaymentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Payment or order not found"))
+	case errors.Is(err, service.ErrPaymentNotAllowed):
+		c.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, err.Error()))
+	case errors.Is(err, payment.ErrInvalidSignature):
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, err.Error()))
+	case errors.Is(err, service.ErrOrderNotPayable), errors.Is(err, service.ErrDuplicateCallback):
+		c.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, err.Error()))
+	case errors.Is(err, service.ErrCallbackMismatch):
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"estore-server/models"
+	"estore-server/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Policy checks combine ownership with role permissions: owners may act on their own
+// data, and staff may act on anyone's when one of their roles grants the permission.
+
+// canModifyProduct reports whether the requester may change the product in the way
+// guarded by permission (models.PermProductUpdate or models.PermProductDelete)
+func canModifyProduct(c *gin.Context, requester *models.User, product *models.Product, permission string) bool {
+	return product.UserID == requester.ID || utils.HasPermission(c, permission)
+}
+
+// canViewOrder reports whether the requester is a party to the order or may read all orders
+func canViewOrder(c *gin.Context, requester *models.User, order *models.Order) bool {
+	return order.BuyerID == requester.ID || order.SellerID == requester.ID || utils.HasPermission(c, models.PermOrderRead)
+}
+
+// canTransitionOrder reports whether the requester may move the order to target
+func canTransitionOrder(c *gin.Context, requester *models.User, order *models.Order, target models.OrderStatus) bool {
+	return canChangeOrderStatus(order, requester.ID, target) || utils.HasPermission(c, models.PermOrderUpdate)
+}
+
+// canManageUser reports whether the requester may change the target user's account
+func canManageUser(c *gin.Context, requester *models.User, targetUserID uint) bool {
+	return requester.ID == targetUserID || utils.HasPermission(c, models.PermUserUpdate)
+}
+
+// canSuspendUser reports whether the requester may suspend the target. Nobody can suspend
+// themselves, and suspending staff (anyone holding a role beyond the default) takes the
+// permission to change roles
+func canSuspendUser(c *gin.Context, requester *models.User, targetUserID uint, targetRoles []models.Role) bool {
+	if requester.ID == targetUserID {
+		return false
+	}
+	for _, role := range targetRoles {
+		if role.Name != models.RoleUser {
+			return utils.HasPermission(c, models.PermRoleAssign)
+		}
+	}
+	return true
+}
@@ -2,11 +2,19 @@ package controller
 
 import (
 	"errors"
+	"io"
+	"mime"
 	"net/http"
+	"path"
+	"strings"
 
 	"estore-server/dto"
+	"estore-server/models"
+	"estore-server/realtime"
+	"estore-server/search"
 	"estore-server/service"
 	"estore-server/service/impl"
+	"estore-server/storage"
 	"estore-server/utils"
 
 	"github.com/gin-gonic/gin"
@@ -15,28 +23,81 @@ import (
 
 // ProductController coordinates product-related handlers
 type ProductController struct {
-	ProductService service.ProductService
+	ProductService      service.ProductService
+	CategoryService     service.CategoryService
+	ProductImageService service.ProductImageService
+	FavoriteService     service.FavoriteService
+	Store               storage.BlobStore
 }
 
-func NewProductController(db *gorm.DB) *ProductController {
+func NewProductController(db *gorm.DB, store storage.BlobStore, index search.SearchIndex, broker realtime.Broker) *ProductController {
 	return &ProductController{
-		ProductService: impl.NewProductServiceImpl(db),
+		ProductService:      impl.NewProductServiceImpl(db, store, index, impl.NewNotificationServiceImpl(db, broker)),
+		CategoryService:     impl.NewCategoryServiceImpl(db),
+		ProductImageService: impl.NewProductImageServiceImpl(db, store),
+		FavoriteService:     impl.NewFavoriteServiceImpl(db),
+		Store:               store,
 	}
 }
 
-// SearchProducts retrieves products filtered by optional keyword across name and description
+// SearchProducts retrieves one page of products filtered by optional keyword across name and description
+// Sold-out products are hidden unless include_out_of_stock=true; category (ID or slug)
+// matches the category and all of its descendants
 func (pc *ProductController) SearchProducts(c *gin.Context) {
-	keyword := c.Query("q")
-	products, err := pc.ProductService.SearchProducts(keyword)
+	var query dto.SearchProductsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid query parameters"))
+		return
+	}
+
+	if query.MinPrice != nil && query.MaxPrice != nil && *query.MinPrice > *query.MaxPrice {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid price range"))
+		return
+	}
+
+	filter := service.ProductFilter{
+		Keyword:           query.Keyword,
+		IncludeOutOfStock: query.IncludeOutOfStock,
+		SellerID:          query.SellerID,
+		MinPrice:          query.MinPrice,
+		MaxPrice:          query.MaxPrice,
+		Sort:              service.ProductSort(query.Sort),
+		Cursor:            query.Cursor,
+		Limit:             query.Limit,
+	}
+
+	if query.Category != "" {
+		categoryIDs, err := pc.resolveCategoryFilter(query.Category)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Category not found"))
+				return
+			}
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+			return
+		}
+		filter.CategoryIDs = categoryIDs
+	}
+
+	page, err := pc.ProductService.SearchProducts(filter)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) || errors.Is(err, service.ErrInvalidSort) {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
 		return
 	}
 
-	response := make([]dto.ProductResponse, 0, len(products))
-	for i := range products {
-		response = append(response, dto.NewProductResponse(&products[i]))
+	products := make([]dto.ProductResponse, 0, len(page.Products))
+	for i := range page.Products {
+		products = append(products, dto.NewProductResponse(&page.Products[i]))
 	}
+	if err := markFavorited(c, pc.FavoriteService, products); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+	response := dto.NewPageResponse(products, page.Total, page.NextCursor)
 	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, response, "Products retrieved successfully"))
 }
 
@@ -59,7 +120,13 @@ func (pc *ProductController) GetProductByID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, dto.NewProductResponse(product), "Product retrieved successfully"))
+	response := []dto.ProductResponse{dto.NewProductResponse(product)}
+	if err := markFavorited(c, pc.FavoriteService, response); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, response[0], "Product retrieved successfully"))
 }
 
 // CreateProduct lets an authenticated user add a product under their account
@@ -76,8 +143,17 @@ func (pc *ProductController) CreateProduct(c *gin.Context) {
 		return
 	}
 
-	product, err := pc.ProductService.CreateProduct(user.ID, req.Name, req.Description, req.Price)
+	stock := 1
+	if req.Stock != nil {
+		stock = *req.Stock
+	}
+
+	product, err := pc.ProductService.CreateProduct(auditActor(c), user.ID, req.Name, req.Description, req.Price, stock, req.CategoryID)
 	if err != nil {
+		if errors.Is(err, service.ErrCategoryNotFound) {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
 		return
 	}
@@ -85,7 +161,7 @@ func (pc *ProductController) CreateProduct(c *gin.Context) {
 	c.JSON(http.StatusCreated, dto.NewSuccessResponse(http.StatusCreated, dto.NewProductResponse(product), "Product created successfully"))
 }
 
-// UpdateProduct lets owners update their items while also granting admins override access
+// UpdateProduct lets owners update their items while also granting staff with product:update override access
 func (pc *ProductController) UpdateProduct(c *gin.Context) {
 	productID, err := utils.ParseUintParam(c.Param("id"))
 	if err != nil {
@@ -109,7 +185,7 @@ func (pc *ProductController) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	if !requester.IsAdmin && product.UserID != requester.ID {
+	if !canModifyProduct(c, requester, product, models.PermProductUpdate) {
 		c.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, "Unauthorized to modify this product"))
 		return
 	}
@@ -120,12 +196,28 @@ func (pc *ProductController) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	updatedProduct, err := pc.ProductService.UpdateProduct(productID, req.Name, req.Description, req.Price)
+	update := service.ProductUpdate{
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       req.Price,
+		Stock:       req.Stock,
+		CategoryID:  req.CategoryID,
+	}
+	if req.CategoryID != nil && *req.CategoryID == 0 {
+		update.CategoryID = nil
+		update.ClearCategory = true
+	}
+
+	updatedProduct, err := pc.ProductService.UpdateProduct(auditActor(c), productID, update)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Product not found"))
 			return
 		}
+		if errors.Is(err, service.ErrCategoryNotFound) {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
 		return
 	}
@@ -133,7 +225,7 @@ func (pc *ProductController) UpdateProduct(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, dto.NewProductResponse(updatedProduct), "Product updated successfully"))
 }
 
-// DeleteProduct allows owners or admins to remove products
+// DeleteProduct allows owners or staff with product:delete to remove products
 func (pc *ProductController) DeleteProduct(c *gin.Context) {
 	productID, err := utils.ParseUintParam(c.Param("id"))
 	if err != nil {
@@ -157,15 +249,189 @@ func (pc *ProductController) DeleteProduct(c *gin.Context) {
 		return
 	}
 
-	if !requester.IsAdmin && product.UserID != requester.ID {
+	if !canModifyProduct(c, requester, product, models.PermProductDelete) {
 		c.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, "Unauthorized to delete this product"))
 		return
 	}
 
-	if err := pc.ProductService.DeleteProduct(productID); err != nil {
+	if err := pc.ProductService.DeleteProduct(auditActor(c), productID); err != nil {
 		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
 		return
 	}
 
 	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Product deleted successfully"))
 }
+
+// resolveCategoryFilter turns a category ID or slug into that category's subtree of IDs
+func (pc *ProductController) resolveCategoryFilter(param string) ([]uint, error) {
+	categoryID, err := utils.ParseUintParam(param)
+	if err != nil {
+		category, err := pc.CategoryService.GetCategoryBySlug(param)
+		if err != nil {
+			return nil, err
+		}
+		categoryID = category.ID
+	}
+
+	return pc.CategoryService.DescendantIDs(categoryID)
+}
+
+// UploadImages appends multipart "images" files to a product's gallery (owner or staff)
+func (pc *ProductController) UploadImages(c *gin.Context) {
+	productID, ok := pc.authorizeProductOwner(c, "Unauthorized to modify this product")
+	if !ok {
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["images"]) == 0 {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload: expected multipart images"))
+		return
+	}
+
+	uploads := make([]service.ImageUpload, 0, len(form.File["images"]))
+	for _, header := range form.File["images"] {
+		if header.Size > service.MaxProductImageSize {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, service.ErrImageTooLarge.Error()))
+			return
+		}
+
+		file, err := header.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid uploaded file"))
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(file, service.MaxProductImageSize+1))
+		file.Close()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid uploaded file"))
+			return
+		}
+
+		uploads = append(uploads, service.ImageUpload{Filename: header.Filename, Data: data})
+	}
+
+	images, err := pc.ProductImageService.AddImages(auditActor(c), productID, uploads)
+	if err != nil {
+		writeProductImageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewSuccessResponse(http.StatusCreated, dto.NewProductImageResponses(images), "Images uploaded successfully"))
+}
+
+// ReorderImages rearranges a product's gallery (owner or staff)
+func (pc *ProductController) ReorderImages(c *gin.Context) {
+	productID, ok := pc.authorizeProductOwner(c, "Unauthorized to modify this product")
+	if !ok {
+		return
+	}
+
+	var req dto.ReorderProductImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	images, err := pc.ProductImageService.ReorderImages(auditActor(c), productID, req.ImageIDs)
+	if err != nil {
+		writeProductImageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, dto.NewProductImageResponses(images), "Images reordered successfully"))
+}
+
+// DeleteImage removes a picture from a product's gallery (owner or staff)
+func (pc *ProductController) DeleteImage(c *gin.Context) {
+	productID, ok := pc.authorizeProductOwner(c, "Unauthorized to modify this product")
+	if !ok {
+		return
+	}
+
+	imageID, err := utils.ParseUintParam(c.Param("imageId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid image ID"))
+		return
+	}
+
+	if err := pc.ProductImageService.DeleteImage(auditActor(c), productID, imageID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Image not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Image deleted successfully"))
+}
+
+// ServeImage streams a stored image from the blob store
+func (pc *ProductController) ServeImage(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	reader, err := pc.Store.Open(c.Request.Context(), key)
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Image not found"))
+		return
+	}
+	defer reader.Close()
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	// Keys are random and never reused, so clients may cache aggressively
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.DataFromReader(http.StatusOK, -1, contentType, reader, nil)
+}
+
+// authorizeProductOwner resolves the :id product and checks that the requester owns it or may update any product.
+// It writes the error response itself and reports whether the handler may continue.
+func (pc *ProductController) authorizeProductOwner(c *gin.Context, forbiddenMsg string) (uint, bool) {
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid product ID"))
+		return 0, false
+	}
+
+	requester, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return 0, false
+	}
+
+	product, err := pc.ProductService.GetProduct(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Product not found"))
+			return 0, false
+		}
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return 0, false
+	}
+
+	if !canModifyProduct(c, requester, product, models.PermProductUpdate) {
+		c.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, forbiddenMsg))
+		return 0, false
+	}
+
+	return productID, true
+}
+
+// writeProductImageError maps product image service errors to HTTP responses
+func writeProductImageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Product not found"))
+	case errors.Is(err, service.ErrImageTooLarge),
+		errors.Is(err, service.ErrImageUnsupportedType),
+		errors.Is(err, service.ErrTooManyImages),
+		errors.Is(err, service.ErrImageOrderMismatch):
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+}
@@ -0,0 +1,178 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"estore-server/dto"
+	"estore-server/realtime"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ReviewController coordinates seller review handlers
+type ReviewController struct {
+	ReviewService service.ReviewService
+}
+
+func NewReviewController(db *gorm.DB, broker realtime.Broker) *ReviewController {
+	return &ReviewController{
+		ReviewService: impl.NewReviewServiceImpl(db, impl.NewNotificationServiceImpl(db, broker)),
+	}
+}
+
+// ListReviews returns one page of a seller's reviews, newest first
+func (rc *ReviewController) ListReviews(c *gin.Context) {
+	sellerID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid user ID"))
+		return
+	}
+
+	var query dto.ReviewListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid query parameters"))
+		return
+	}
+
+	page, err := rc.ReviewService.ListReviews(sellerID, query.Cursor, query.Limit)
+	if err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	response := dto.NewPageResponse(dto.NewReviewResponses(page.Reviews), page.Total, page.NextCursor)
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, response, "Reviews retrieved successfully"))
+}
+
+// CreateReview rates a seller on behalf of the current user
+func (rc *ReviewController) CreateReview(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	sellerID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid user ID"))
+		return
+	}
+
+	var req dto.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	review, err := rc.ReviewService.CreateReview(auditActor(c), user.ID, sellerID, req.Rating, req.Comment)
+	if err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	response := dto.NewReviewResponse(review)
+	c.JSON(http.StatusCreated, dto.NewSuccessResponse(http.StatusCreated, response, "Review created successfully"))
+}
+
+// UpdateReview edits the current user's review
+func (rc *ReviewController) UpdateReview(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	reviewID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid review ID"))
+		return
+	}
+
+	var req dto.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	review, err := rc.ReviewService.UpdateReview(auditActor(c), user.ID, reviewID, req.Rating, req.Comment)
+	if err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	response := dto.NewReviewResponse(review)
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, response, "Review updated successfully"))
+}
+
+// DeleteReview removes the current user's review
+func (rc *ReviewController) DeleteReview(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	reviewID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid review ID"))
+		return
+	}
+
+	if err := rc.ReviewService.DeleteReview(auditActor(c), user.ID, reviewID); err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Review deleted successfully"))
+}
+
+// ReplyToReview posts the current user's reply to a review of them as a seller
+func (rc *ReviewController) ReplyToReview(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	reviewID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid review ID"))
+		return
+	}
+
+	var req dto.ReviewReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	review, err := rc.ReviewService.ReplyToReview(auditActor(c), user.ID, reviewID, req.Reply)
+	if err != nil {
+		writeReviewError(c, err)
+		return
+	}
+
+	response := dto.NewReviewResponse(review)
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, response, "Reply posted successfully"))
+}
+
+// writeReviewError maps review service errors to HTTP responses
+func writeReviewError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Review or seller not found"))
+	case errors.Is(err, service.ErrSelfReview), errors.Is(err, service.ErrInvalidRating), errors.Is(err, service.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	case errors.Is(err, service.ErrReviewNotAllowed):
+		c.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, err.Error()))
+	case errors.Is(err, service.ErrReviewExists), errors.Is(err, service.ErrReplyExists):
+		c.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"estore-server/dto"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RoleController coordinates role listing and assignment handlers for admins
+type RoleController struct {
+	RoleService service.RoleService
+}
+
+func NewRoleController(db *gorm.DB) *RoleController {
+	return &RoleController{
+		RoleService: impl.NewRoleServiceImpl(db),
+	}
+}
+
+// ListRoles returns every role with the permissions it grants
+func (rc *RoleController) ListRoles(c *gin.Context) {
+	roles, err := rc.RoleService.ListRoles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, dto.NewRoleDTOs(roles), "Roles retrieved successfully"))
+}
+
+// GetUserRoles returns the roles held by a user
+func (rc *RoleController) GetUserRoles(c *gin.Context) {
+	userID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid user ID"))
+		return
+	}
+
+	roles, err := rc.RoleService.GetUserRoles(userID)
+	if err != nil {
+		writeRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, dto.NewRoleDTOs(roles), "User roles retrieved successfully"))
+}
+
+// AssignRole grants a role to a user
+func (rc *RoleController) AssignRole(c *gin.Context) {
+	userID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid user ID"))
+		return
+	}
+
+	var req dto.AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	if err := rc.RoleService.AssignRole(auditActor(c), userID, req.Role); err != nil {
+		writeRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Role assigned successfully"))
+}
+
+// RemoveRole takes a role away from a user; the user is logged out of every session
+func (rc *RoleController) RemoveRole(c *gin.Context) {
+	userID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid user ID"))
+		return
+	}
+
+	if err := rc.RoleService.RemoveRole(auditActor(c), userID, c.Param("role")); err != nil {
+		writeRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Role removed successfully"))
+}
+
+// writeRoleError maps role service errors to HTTP responses
+func writeRoleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "User not found"))
+	case errors.Is(err, service.ErrRoleNotFound):
+		c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, err.Error()))
+	case errors.Is(err, service.ErrLastAdmin):
+		c.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+}
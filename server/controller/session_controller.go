@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"estore-server/dto"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SessionController lets users see and revoke their logged-in devices
+type SessionController struct {
+	SessionService service.SessionService
+}
+
+func NewSessionController(db *gorm.DB) *SessionController {
+	return &SessionController{
+		SessionService: impl.NewSessionServiceImpl(db),
+	}
+}
+
+// ListSessions returns the current user's active sessions, flagging the one making the request
+func (sc *SessionController) ListSessions(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	sessions, err := sc.SessionService.ListSessions(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	data := dto.NewSessionResponses(sessions, utils.GetSessionIDFromCtx(c))
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, data, "Sessions retrieved successfully"))
+}
+
+// RevokeSession logs out one of the current user's sessions
+func (sc *SessionController) RevokeSession(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	if err := sc.SessionService.RevokeSession(auditActor(c), user.ID, c.Param("id")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Session not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Session revoked successfully"))
+}
+
+// RevokeOtherSessions logs out every session of the current user except the one making the request
+func (sc *SessionController) RevokeOtherSessions(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	revoked, err := sc.SessionService.RevokeOtherSessions(auditActor(c), user.ID, utils.GetSessionIDFromCtx(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, gin.H{"revoked": revoked}, "Other sessions revoked successfully"))
+}
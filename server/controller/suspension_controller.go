@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"estore-server/dto"
+	"estore-server/realtime"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SuspensionController coordinates user suspension handlers for moderators and admins
+type SuspensionController struct {
+	SuspensionService service.SuspensionService
+	RoleService       service.RoleService
+}
+
+func NewSuspensionController(db *gorm.DB, broker realtime.Broker) *SuspensionController {
+	return &SuspensionController{
+		SuspensionService: impl.NewSuspensionServiceImpl(db, impl.NewNotificationServiceImpl(db, broker)),
+		RoleService:       impl.NewRoleServiceImpl(db),
+	}
+}
+
+// SuspendUser suspends a user, locking them out of login and of every token they hold
+func (sc *SuspensionController) SuspendUser(c *gin.Context) {
+	requester, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	userID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid user ID"))
+		return
+	}
+
+	var req dto.SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	roles, err := sc.RoleService.GetUserRoles(userID)
+	if err != nil {
+		writeSuspensionError(c, err)
+		return
+	}
+	if !canSuspendUser(c, requester, userID, roles) {
+		c.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, "You cannot suspend this user"))
+		return
+	}
+
+	suspension, err := sc.SuspensionService.SuspendUser(auditActor(c), userID, requester.ID, req.Reason, req.ExpiresAt)
+	if err != nil {
+		writeSuspensionError(c, err)
+		return
+	}
+
+	response := dto.NewSuspensionDTO(suspension)
+	c.JSON(http.StatusCreated, dto.NewSuccessResponse(http.StatusCreated, response, "User suspended successfully"))
+}
+
+// LiftSuspension ends a user's suspension before it expires
+func (sc *SuspensionController) LiftSuspension(c *gin.Context) {
+	requester, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	userID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid user ID"))
+		return
+	}
+
+	suspension, err := sc.SuspensionService.LiftSuspension(auditActor(c), userID, requester.ID)
+	if err != nil {
+		writeSuspensionError(c, err)
+		return
+	}
+
+	response := dto.NewSuspensionDTO(suspension)
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, response, "Suspension lifted successfully"))
+}
+
+// ListSuspensions returns a user's suspension history, newest first
+func (sc *SuspensionController) ListSuspensions(c *gin.Context) {
+	userID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid user ID"))
+		return
+	}
+
+	suspensions, err := sc.SuspensionService.ListSuspensions(userID)
+	if err != nil {
+		writeSuspensionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, dto.NewSuspensionDTOs(suspensions), "Suspensions retrieved successfully"))
+}
+
+// writeSuspensionError maps suspension service errors to HTTP responses
+func writeSuspensionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "User not found"))
+	case errors.Is(err, service.ErrSuspensionExpiry):
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	case errors.Is(err, service.ErrAlreadySuspended), errors.Is(err, service.ErrNotSuspended):
+		c.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+}
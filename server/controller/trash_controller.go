@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"estore-server/dto"
+	"estore-server/search"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/storage"
+	"estore-server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TrashController lets admins review and restore soft-deleted products and users
+type TrashController struct {
+	TrashService service.TrashService
+}
+
+func NewTrashController(db *gorm.DB, store storage.BlobStore, index search.SearchIndex) *TrashController {
+	return &TrashController{
+		TrashService: impl.NewTrashServiceImpl(db, store, index),
+	}
+}
+
+// ListDeletedProducts returns every product in the trash, most recently deleted first
+func (tc *TrashController) ListDeletedProducts(c *gin.Context) {
+	products, err := tc.TrashService.ListDeletedProducts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, dto.NewTrashedProductResponses(products), "Deleted products retrieved successfully"))
+}
+
+// ListDeletedUsers returns every user in the trash, most recently deleted first
+func (tc *TrashController) ListDeletedUsers(c *gin.Context) {
+	users, err := tc.TrashService.ListDeletedUsers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, dto.NewTrashedUserDTOs(users), "Deleted users retrieved successfully"))
+}
+
+// RestoreProduct takes a product out of the trash
+func (tc *TrashController) RestoreProduct(c *gin.Context) {
+	productID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid product ID"))
+		return
+	}
+
+	product, err := tc.TrashService.RestoreProduct(auditActor(c), productID)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Deleted product not found"))
+		case errors.Is(err, service.ErrOwnerDeleted):
+			c.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		}
+		return
+	}
+
+	response := dto.NewProductResponse(product)
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, response, "Product restored successfully"))
+}
+
+// RestoreUser takes a user, and the listings deleted along with the account, out of the trash
+func (tc *TrashController) RestoreUser(c *gin.Context) {
+	userID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid user ID"))
+		return
+	}
+
+	user, err := tc.TrashService.RestoreUser(auditActor(c), userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Deleted user not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		}
+		return
+	}
+
+	response := dto.NewUserDTO(user)
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, response, "User restored successfully"))
+}
@@ -5,6 +5,8 @@ import (
 	"net/http"
 
 	"estore-server/dto"
+	"estore-server/mail"
+	"estore-server/realtime"
 	"estore-server/service"
 	"estore-server/service/impl"
 	"estore-server/utils"
@@ -14,13 +16,19 @@ import (
 )
 
 type UserController struct {
-	UserService service.UserService
-	AuthService service.AuthService
+	UserService              service.UserService
+	AuthService              service.AuthService
+	PasswordResetService     service.PasswordResetService
+	EmailVerificationService service.EmailVerificationService
 }
 
-func NewUserController(db *gorm.DB) *UserController {
+func NewUserController(db *gorm.DB, mailer mail.Mailer, broker realtime.Broker) *UserController {
+	notifications := impl.NewNotificationServiceImpl(db, broker)
 	return &UserController{
-		UserService: impl.NewUserServiceImpl(db),
+		UserService:              impl.NewUserServiceImpl(db, notifications),
+		AuthService:              impl.NewAuthServiceImpl(db),
+		PasswordResetService:     impl.NewPasswordResetServiceImpl(db, mailer, notifications),
+		EmailVerificationService: impl.NewEmailVerificationServiceImpl(db, mailer),
 	}
 }
 
@@ -32,13 +40,59 @@ func (uc *UserController) Register(c *gin.Context) {
 	}
 
 	// For registration, we'll only allow regular users (not admins)
-	err := uc.AuthService.RegisterUser(req.Username, req.Email, req.Password)
+	user, err := uc.AuthService.RegisterUser(auditActor(c), req.Username, req.Email, req.Password)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusCreated, dto.NewSuccessResponse(http.StatusCreated, nil, "User registered successfully"))
+	// The account exists either way; a failed email can be resent after logging in
+	if err := uc.EmailVerificationService.SendVerification(user.ID); err != nil {
+		c.JSON(http.StatusCreated, dto.NewSuccessResponse(http.StatusCreated, nil, "User registered successfully, but the verification email could not be sent"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewSuccessResponse(http.StatusCreated, nil, "User registered successfully, please check your email to verify your address"))
+}
+
+// ResendVerification emails a new verification token to the current user's address
+func (uc *UserController) ResendVerification(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	if err := uc.EmailVerificationService.SendVerification(user.ID); err != nil {
+		if errors.Is(err, service.ErrEmailAlreadyVerified) {
+			c.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Verification email sent"))
+}
+
+// VerifyEmail confirms an address with a token from the verification email
+func (uc *UserController) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request"))
+		return
+	}
+
+	if err := uc.EmailVerificationService.VerifyEmail(auditActor(c), req.Token); err != nil {
+		if errors.Is(err, service.ErrVerificationTokenInvalid) {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Email verified successfully"))
 }
 
 func (uc *UserController) GetMe(c *gin.Context) {
@@ -94,14 +148,13 @@ func (uc *UserController) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	user, err := uc.UserService.UpdateUser(requester.ID, req.Username, req.Email, req.Phone, req.Address)
+	user, err := uc.UserService.UpdateUser(auditActor(c), requester.ID, req.Username, req.Email, req.Phone, req.Address)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
 		return
 	}
 
 	responseData := dto.NewUserDTO(user)
-
 	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, responseData, "User updated successfully"))
 }
 
@@ -113,17 +166,14 @@ func (uc *UserController) UpdateUserPassword(c *gin.Context) {
 		return
 	}
 
-	// Check if requesting user is an admin
 	requester, err := utils.GetUserFromCtx(c)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
 		return
 	}
-	requestingUserID := requester.ID
-	isAdmin := requester.IsAdmin
 
-	// Only allow admin or the user themselves to update the password
-	if !isAdmin && requestingUserID != targetUserID {
+	// Only allow the user themselves or staff with user:update to update the password
+	if !canManageUser(c, requester, targetUserID) {
 		c.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, "Cannot update another user's password"))
 		return
 	}
@@ -134,7 +184,7 @@ func (uc *UserController) UpdateUserPassword(c *gin.Context) {
 		return
 	}
 
-	if err := uc.UserService.UpdateUserPassword(targetUserID, req.OldPassword, req.NewPassword); err != nil {
+	if err := uc.UserService.UpdateUserPassword(auditActor(c), targetUserID, req.OldPassword, req.NewPassword); err != nil {
 		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
 		return
 	}
@@ -142,6 +192,43 @@ func (uc *UserController) UpdateUserPassword(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Password updated successfully"))
 }
 
+// ForgotPassword emails a reset token. The response is the same whether or not the
+// address belongs to an account, so it cannot be used to probe for users.
+func (uc *UserController) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request"))
+		return
+	}
+
+	if err := uc.PasswordResetService.RequestReset(req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "If the email is registered, a reset link has been sent"))
+}
+
+// ResetPassword sets a new password using a token from ForgotPassword
+func (uc *UserController) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request"))
+		return
+	}
+
+	if err := uc.PasswordResetService.ResetPassword(auditActor(c), req.Token, req.NewPassword); err != nil {
+		if errors.Is(err, service.ErrResetTokenInvalid) {
+			c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Password reset successfully"))
+}
+
 func (uc *UserController) GetAllUsers(c *gin.Context) {
 	users, err := uc.UserService.GetAllUsers()
 	if err != nil {
@@ -165,9 +252,12 @@ func (uc *UserController) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	err = uc.UserService.DeleteUser(userID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	if err := uc.UserService.DeleteUser(auditActor(c), userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "User not found"))
+		} else {
+			c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		}
 		return
 	}
 
@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"estore-server/dto"
+	"estore-server/models"
+	"estore-server/realtime"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// WalletController coordinates wallet and ledger handlers
+type WalletController struct {
+	WalletService service.WalletService
+}
+
+func NewWalletController(db *gorm.DB, broker realtime.Broker) *WalletController {
+	return &WalletController{
+		WalletService: impl.NewWalletServiceImpl(db, impl.NewNotificationServiceImpl(db, broker)),
+	}
+}
+
+// GetWallet returns the current user's wallet and balance
+func (wc *WalletController) GetWallet(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	wallet, err := wc.WalletService.GetWallet(user.ID)
+	if err != nil {
+		writeWalletError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, wallet, "Wallet retrieved successfully"))
+}
+
+// GetStatement returns one page of the current user's wallet entries, newest first
+func (wc *WalletController) GetStatement(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	var query dto.WalletStatementQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid query parameters"))
+		return
+	}
+
+	page, err := wc.WalletService.Statement(user.ID, query.Cursor, query.Limit)
+	if err != nil {
+		writeWalletError(c, err)
+		return
+	}
+
+	response := dto.NewPageResponse(page.Lines, page.Total, page.NextCursor)
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, response, "Statement retrieved successfully"))
+}
+
+// Transfer sends money from the current user's wallet to another user's
+func (wc *WalletController) Transfer(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	var req dto.WalletTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	transaction, err := wc.WalletService.Transfer(auditActor(c), user.ID, req.ToUserID, req.Amount, req.Memo)
+	if err != nil {
+		writeWalletError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewSuccessResponse(http.StatusCreated, transaction, "Transfer completed successfully"))
+}
+
+// Withdraw pays money out of the current user's wallet
+func (wc *WalletController) Withdraw(c *gin.Context) {
+	user, err := utils.GetUserFromCtx(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+		return
+	}
+
+	var req dto.WalletAmountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	transaction, err := wc.WalletService.Withdraw(auditActor(c), user.ID, req.Amount, req.Memo)
+	if err != nil {
+		writeWalletError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewSuccessResponse(http.StatusCreated, transaction, "Withdrawal completed successfully"))
+}
+
+// AdminDeposit credits a user's wallet, e.g. for a top-up received outside the app or a refund
+func (wc *WalletController) AdminDeposit(c *gin.Context) {
+	wc.adminMove(c, wc.WalletService.Deposit, "Deposit completed successfully")
+}
+
+// AdminWithdraw debits a user's wallet, e.g. for a payout made outside the app
+func (wc *WalletController) AdminWithdraw(c *gin.Context) {
+	wc.adminMove(c, wc.WalletService.Withdraw, "Withdrawal completed successfully")
+}
+
+// adminMove runs a deposit or withdrawal an admin makes on the user in the path
+func (wc *WalletController) adminMove(c *gin.Context, move func(actor service.Actor, userID uint, amount int, memo string) (*models.LedgerTransaction, error), message string) {
+	userID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid user ID"))
+		return
+	}
+
+	var req dto.WalletAmountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	transaction, err := move(auditActor(c), userID, req.Amount, req.Memo)
+	if err != nil {
+		writeWalletError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewSuccessResponse(http.StatusCreated, transaction, message))
+}
+
+// Reconcile checks that the ledger balances and every wallet matches its entries
+func (wc *WalletController) Reconcile(c *gin.Context) {
+	report, err := wc.WalletService.Reconcile()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	message := "Ledger is balanced"
+	if !report.Balanced {
+		message = "Ledger is out of balance"
+	}
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, report, message))
+}
+
+// writeWalletError maps wallet service errors to HTTP responses
+func writeWalletError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "User not found"))
+	case errors.Is(err, service.ErrInvalidAmount), errors.Is(err, service.ErrSelfTransfer), errors.Is(err, service.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	case errors.Is(err, service.ErrInsufficientFunds):
+		c.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"estore-server/dto"
+	"estore-server/service"
+	"estore-server/service/impl"
+	"estore-server/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// WebhookController lets admins manage outgoing webhooks and their delivery log
+type WebhookController struct {
+	WebhookService service.WebhookService
+}
+
+func NewWebhookController(db *gorm.DB) *WebhookController {
+	return &WebhookController{
+		WebhookService: impl.NewWebhookServiceImpl(db),
+	}
+}
+
+// ListEndpoints returns every registered webhook endpoint
+func (wc *WebhookController) ListEndpoints(c *gin.Context) {
+	endpoints, err := wc.WebhookService.ListEndpoints()
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, endpoints, "Webhook endpoints retrieved successfully"))
+}
+
+// CreateEndpoint registers an endpoint and returns its signing secret
+func (wc *WebhookController) CreateEndpoint(c *gin.Context) {
+	var req dto.WebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	endpoint, err := wc.WebhookService.CreateEndpoint(auditActor(c), req.URL, req.Description, req.Events)
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	response := dto.WebhookEndpointCreatedResponse{WebhookEndpoint: endpoint, Secret: endpoint.Secret}
+	c.JSON(http.StatusCreated, dto.NewSuccessResponse(http.StatusCreated, response, "Webhook endpoint created successfully"))
+}
+
+// UpdateEndpoint changes an endpoint's URL, event filter or active flag
+func (wc *WebhookController) UpdateEndpoint(c *gin.Context) {
+	endpointID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid webhook ID"))
+		return
+	}
+
+	var req dto.UpdateWebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid request payload"))
+		return
+	}
+
+	endpoint, err := wc.WebhookService.UpdateEndpoint(auditActor(c), endpointID, req.URL, req.Description, req.Events, *req.Active)
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, endpoint, "Webhook endpoint updated successfully"))
+}
+
+// DeleteEndpoint removes an endpoint and its delivery log
+func (wc *WebhookController) DeleteEndpoint(c *gin.Context) {
+	endpointID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid webhook ID"))
+		return
+	}
+
+	if err := wc.WebhookService.DeleteEndpoint(auditActor(c), endpointID); err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Webhook endpoint deleted successfully"))
+}
+
+// ListDeliveries returns one page of an endpoint's delivery log, newest first
+func (wc *WebhookController) ListDeliveries(c *gin.Context) {
+	endpointID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid webhook ID"))
+		return
+	}
+
+	var query dto.WebhookDeliveryListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid query parameters"))
+		return
+	}
+
+	page, err := wc.WebhookService.ListDeliveries(endpointID, query.Cursor, query.Limit)
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	response := dto.NewPageResponse(page.Deliveries, page.Total, page.NextCursor)
+	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, response, "Webhook deliveries retrieved successfully"))
+}
+
+// Redeliver sends a logged delivery's event to its endpoint again
+func (wc *WebhookController) Redeliver(c *gin.Context) {
+	deliveryID, err := utils.ParseUintParam(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Invalid delivery ID"))
+		return
+	}
+
+	delivery, err := wc.WebhookService.Redeliver(auditActor(c), deliveryID)
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.NewSuccessResponse(http.StatusAccepted, delivery, "Webhook redelivery queued"))
+}
+
+// writeWebhookError maps webhook service errors to HTTP responses
+func writeWebhookError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Webhook endpoint or delivery not found"))
+	case errors.Is(err, service.ErrUnknownWebhookEvent), errors.Is(err, service.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+	}
+}
@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// AuditLogQuery represents the query string of GET /admin/audit-logs
+type AuditLogQuery struct {
+	ActorID    *uint      `form:"actor_id"`
+	Action     string     `form:"action"`
+	TargetType string     `form:"target_type"`
+	TargetID   string     `form:"target_id"`
+	Since      *time.Time `form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+	Until      *time.Time `form:"until" time_format:"2006-01-02T15:04:05Z07:00"`
+	Cursor     string     `form:"cursor"`
+	Limit      int        `form:"limit" binding:"omitempty,gte=1,lte=200"`
+}
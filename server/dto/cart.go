@@ -0,0 +1,78 @@
+package dto
+
+import "estore-server/models"
+
+// AddCartItemRequest represents the payload for adding a product to the cart
+type AddCartItemRequest struct {
+	ProductID uint `json:"product_id" binding:"required"`
+	Quantity  int  `json:"quantity" binding:"required,gte=1"`
+}
+
+// UpdateCartItemRequest represents the payload for changing a cart item's quantity
+type UpdateCartItemRequest struct {
+	Quantity int `json:"quantity" binding:"required,gte=1"`
+}
+
+// CartItemResponse represents a cart item priced against the live product
+type CartItemResponse struct {
+	ID           uint   `json:"id"`
+	ProductID    uint   `json:"product_id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Quantity     int    `json:"quantity"`
+	Price        int    `json:"price"`
+	PriceAtAdd   int    `json:"price_at_add"`
+	Subtotal     int    `json:"subtotal"`
+	Seller       Seller `json:"seller"`
+	Unavailable  bool   `json:"unavailable"`   // Product has been deleted since it was added
+	PriceChanged bool   `json:"price_changed"` // Product price differs from PriceAtAdd
+	OutOfStock   bool   `json:"out_of_stock"`  // Product stock is below the requested quantity
+}
+
+// CartResponse represents the whole cart; totals only count available items
+type CartResponse struct {
+	Items         []CartItemResponse `json:"items"`
+	TotalQuantity int                `json:"total_quantity"`
+	TotalPrice    int                `json:"total_price"`
+}
+
+func NewCartItemResponse(item *models.CartItem) CartItemResponse {
+	response := CartItemResponse{
+		ID:         item.ID,
+		ProductID:  item.ProductID,
+		Quantity:   item.Quantity,
+		PriceAtAdd: item.PriceAtAdd,
+	}
+
+	if item.Product == nil {
+		response.Unavailable = true
+		return response
+	}
+
+	response.Name = item.Product.Name
+	response.Description = item.Product.Description
+	response.Price = item.Product.Price
+	response.Subtotal = item.Product.Price * item.Quantity
+	response.Seller = NewSeller(&item.Product.User)
+	response.PriceChanged = item.Product.Price != item.PriceAtAdd
+	response.OutOfStock = item.Product.Stock < item.Quantity
+	return response
+}
+
+func NewCartResponse(items []models.CartItem) CartResponse {
+	response := CartResponse{
+		Items: make([]CartItemResponse, 0, len(items)),
+	}
+
+	for i := range items {
+		itemResponse := NewCartItemResponse(&items[i])
+		response.Items = append(response.Items, itemResponse)
+		if itemResponse.Unavailable {
+			continue
+		}
+		response.TotalQuantity += itemResponse.Quantity
+		response.TotalPrice += itemResponse.Subtotal
+	}
+
+	return response
+}
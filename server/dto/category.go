@@ -0,0 +1,70 @@
+package dto
+
+import "estore-server/models"
+
+// CreateCategoryRequest represents the payload for creating a category
+type CreateCategoryRequest struct {
+	Name      string `json:"name" binding:"required,max=100"`
+	Slug      string `json:"slug" binding:"required,max=100"`
+	ParentID  *uint  `json:"parent_id"`
+	SortOrder int    `json:"sort_order"`
+}
+
+// UpdateCategoryRequest represents the payload for updating a category
+// Fields mirror CreateCategoryRequest; a null parent_id moves the category to the root
+type UpdateCategoryRequest struct {
+	Name      string `json:"name" binding:"required,max=100"`
+	Slug      string `json:"slug" binding:"required,max=100"`
+	ParentID  *uint  `json:"parent_id"`
+	SortOrder int    `json:"sort_order"`
+}
+
+// CategoryResponse represents a category with its nested children
+type CategoryResponse struct {
+	ID        uint               `json:"id"`
+	Name      string             `json:"name"`
+	Slug      string             `json:"slug"`
+	ParentID  *uint              `json:"parent_id"`
+	SortOrder int                `json:"sort_order"`
+	Children  []CategoryResponse `json:"children"`
+}
+
+func NewCategoryResponse(category *models.Category) CategoryResponse {
+	return CategoryResponse{
+		ID:        category.ID,
+		Name:      category.Name,
+		Slug:      category.Slug,
+		ParentID:  category.ParentID,
+		SortOrder: category.SortOrder,
+		Children:  []CategoryResponse{},
+	}
+}
+
+// NewCategoryTree nests a flat, display-ordered category list under its root categories
+func NewCategoryTree(categories []models.Category) []CategoryResponse {
+	childrenOf := make(map[uint][]*models.Category)
+	var roots []*models.Category
+	for i := range categories {
+		category := &categories[i]
+		if category.ParentID == nil {
+			roots = append(roots, category)
+		} else {
+			childrenOf[*category.ParentID] = append(childrenOf[*category.ParentID], category)
+		}
+	}
+
+	var build func(category *models.Category) CategoryResponse
+	build = func(category *models.Category) CategoryResponse {
+		response := NewCategoryResponse(category)
+		for _, child := range childrenOf[category.ID] {
+			response.Children = append(response.Children, build(child))
+		}
+		return response
+	}
+
+	tree := make([]CategoryResponse, 0, len(roots))
+	for _, root := range roots {
+		tree = append(tree, build(root))
+	}
+	return tree
+}
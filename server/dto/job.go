@@ -0,0 +1,9 @@
+package dto
+
+// JobListQuery represents the query string of GET /admin/jobs
+type JobListQuery struct {
+	Status string `form:"status"`
+	Type   string `form:"type"`
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,gte=1,lte=200"`
+}
@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"time"
+
+	"estore-server/models"
+)
+
+// StartConversationRequest DTO for opening a conversation with a product's seller
+type StartConversationRequest struct {
+	ProductID uint `json:"product_id" binding:"required"`
+}
+
+// SendMessageRequest DTO for posting a message to a conversation
+type SendMessageRequest struct {
+	Body string `json:"body" binding:"required,max=2000"`
+}
+
+// MarkReadRequest DTO for read receipts; omit up_to_id to mark everything as read
+type MarkReadRequest struct {
+	UpToID uint `json:"up_to_id"`
+}
+
+// MessageListQuery represents the query string of GET /conversation/:id/messages
+type MessageListQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,gte=1,lte=200"`
+}
+
+// Participant represents a user taking part in a conversation
+type Participant struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+// ConversationProduct represents the product a conversation is about
+type ConversationProduct struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	Price   int    `json:"price"`
+	Deleted bool   `json:"deleted"`
+}
+
+// ConversationResponse represents a conversation in the user's inbox
+type ConversationResponse struct {
+	ID            uint                 `json:"id"`
+	Product       *ConversationProduct `json:"product"` // null once the product has been purged
+	Buyer         *Participant         `json:"buyer"`
+	Seller        *Participant         `json:"seller"`
+	LastMessageAt time.Time            `json:"last_message_at"`
+	LastMessage   *models.Message      `json:"last_message"`
+	UnreadCount   int64                `json:"unread_count"`
+}
+
+func newParticipant(user *models.User) *Participant {
+	if user == nil {
+		return nil
+	}
+	return &Participant{ID: user.ID, Username: user.Username}
+}
+
+func NewConversationResponse(conversation *models.Conversation, lastMessage *models.Message, unreadCount int64) ConversationResponse {
+	response := ConversationResponse{
+		ID:            conversation.ID,
+		Buyer:         newParticipant(conversation.Buyer),
+		Seller:        newParticipant(conversation.Seller),
+		LastMessageAt: conversation.LastMessageAt,
+		LastMessage:   lastMessage,
+		UnreadCount:   unreadCount,
+	}
+	if product := conversation.Product; product != nil {
+		response.Product = &ConversationProduct{
+			ID:      product.ID,
+			Name:    product.Name,
+			Price:   product.Price,
+			Deleted: product.DeletedAt.Valid,
+		}
+	}
+	return response
+}
@@ -0,0 +1,8 @@
+package dto
+
+// NotificationListQuery represents the query string of GET /notifications
+type NotificationListQuery struct {
+	Unread bool   `form:"unread"` // Only return unread notifications
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,gte=1,lte=100"`
+}
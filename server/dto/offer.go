@@ -0,0 +1,6 @@
+package dto
+
+// OfferPriceRequest DTO for proposing or countering a price
+type OfferPriceRequest struct {
+	Price int `json:"price" binding:"required,gte=1"`
+}
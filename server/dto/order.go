@@ -0,0 +1,12 @@
+package dto
+
+// CreateOrderRequest represents the payload for buying a single product directly
+type CreateOrderRequest struct {
+	ProductID uint `json:"product_id" binding:"required"`
+	Quantity  int  `json:"quantity" binding:"required,gte=1"`
+}
+
+// UpdateOrderStatusRequest represents the payload for moving an order to a new status
+type UpdateOrderStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+}
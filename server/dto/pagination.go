@@ -0,0 +1,18 @@
+package dto
+
+// PageResponse is the envelope for cursor-paginated lists
+type PageResponse[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int64  `json:"total"`
+	NextCursor string `json:"next_cursor"`
+	HasMore    bool   `json:"has_more"`
+}
+
+func NewPageResponse[T any](items []T, total int64, nextCursor string) PageResponse[T] {
+	return PageResponse[T]{
+		Items:      items,
+		Total:      total,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != "",
+	}
+}
@@ -0,0 +1,6 @@
+package dto
+
+// FakeCheckoutRequest DTO for finishing a fake provider checkout; status defaults to succeeded
+type FakeCheckoutRequest struct {
+	Status string `json:"status" binding:"omitempty,oneof=succeeded failed"`
+}
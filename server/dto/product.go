@@ -3,18 +3,25 @@ package dto
 import "estore-server/models"
 
 // CreateProductRequest represents the payload for creating a product
+// Stock defaults to a single item when omitted
 type CreateProductRequest struct {
 	Name        string `json:"name" binding:"required"`
 	Description string `json:"description" binding:"omitempty"`
 	Price       int    `json:"price" binding:"required,gte=0"`
+	Stock       *int   `json:"stock" binding:"omitempty,gte=1"`
+	CategoryID  *uint  `json:"category_id"`
 }
 
 // UpdateProductRequest represents the payload for updating a product
 // Fields mirror CreateProductRequest to keep validation consistent
+// Stock and category_id are left unchanged when omitted; a category_id of 0 removes the
+// product from its category
 type UpdateProductRequest struct {
 	Name        string `json:"name" binding:"required"`
 	Description string `json:"description" binding:"omitempty"`
 	Price       int    `json:"price" binding:"required,gte=0"`
+	Stock       *int   `json:"stock" binding:"omitempty,gte=0"`
+	CategoryID  *uint  `json:"category_id"`
 }
 
 // Seller represents basic seller info
@@ -24,34 +31,98 @@ type Seller struct {
 	Email    string `json:"email"`
 	Phone    string `json:"phone"`
 	Address  string `json:"address"`
+
+	Rating      float64 `json:"rating"` // Average stars, 0 without reviews
+	ReviewCount int     `json:"review_count"`
+}
+
+// SearchProductsQuery represents the query string of GET /products
+type SearchProductsQuery struct {
+	Keyword           string `form:"q"`
+	Category          string `form:"category"` // Category ID or slug; includes descendants
+	SellerID          uint   `form:"seller_id"`
+	MinPrice          *int   `form:"min_price" binding:"omitempty,gte=0"`
+	MaxPrice          *int   `form:"max_price" binding:"omitempty,gte=0"`
+	IncludeOutOfStock bool   `form:"include_out_of_stock"`
+	Sort              string `form:"sort" binding:"omitempty,oneof=relevance price_asc price_desc created_at_asc created_at_desc"`
+	Cursor            string `form:"cursor"`
+	Limit             int    `form:"limit" binding:"omitempty,gte=1,lte=100"`
+}
+
+// ReorderProductImagesRequest lists every image ID of a product in the desired gallery order
+type ReorderProductImagesRequest struct {
+	ImageIDs []uint `json:"image_ids" binding:"required"`
+}
+
+// ProductImageResponse represents one picture in a product gallery
+type ProductImageResponse struct {
+	ID           uint   `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	Position     int    `json:"position"`
 }
 
 // ProductResponse represents product data returned to clients
 type ProductResponse struct {
-	ID          uint   `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Price       int    `json:"price"`
-	Seller      Seller `json:"seller"`
+	ID          uint                   `json:"id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Price       int                    `json:"price"`
+	Stock       int                    `json:"stock"`
+	CategoryID  *uint                  `json:"category_id"`
+	Images      []ProductImageResponse `json:"images"`
+	Seller      Seller                 `json:"seller"`
+
+	FavoriteCount int  `json:"favorite_count"`
+	IsFavorited   bool `json:"is_favorited"` // Whether the current user has favorited the product
 }
 
-func NewProductResponse(product *models.Product) ProductResponse {
-	seller := Seller{}
-	if product.User.ID != 0 {
-		seller = Seller{
-			ID:       product.User.ID,
-			Username: product.User.Username,
-			Email:    product.User.Email,
-			Phone:    product.User.Phone,
-			Address:  product.User.Address,
-		}
+// NewSeller builds seller info from a preloaded user; an unloaded user yields an empty Seller
+func NewSeller(user *models.User) Seller {
+	if user.ID == 0 {
+		return Seller{}
 	}
 
+	return Seller{
+		ID:       user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Phone:    user.Phone,
+		Address:  user.Address,
+
+		Rating:      user.AverageRating(),
+		ReviewCount: user.RatingCount,
+	}
+}
+
+func NewProductImageResponse(image *models.ProductImage) ProductImageResponse {
+	return ProductImageResponse{
+		ID:           image.ID,
+		URL:          image.URL,
+		ThumbnailURL: image.ThumbnailURL,
+		Position:     image.Position,
+	}
+}
+
+func NewProductImageResponses(images []models.ProductImage) []ProductImageResponse {
+	response := make([]ProductImageResponse, 0, len(images))
+	for i := range images {
+		response = append(response, NewProductImageResponse(&images[i]))
+	}
+	return response
+}
+
+func NewProductResponse(product *models.Product) ProductResponse {
 	return ProductResponse{
 		ID:          product.ID,
 		Name:        product.Name,
 		Description: product.Description,
 		Price:       product.Price,
-		Seller:      seller,
+		Stock:       product.Stock,
+		CategoryID:  product.CategoryID,
+		Images:      NewProductImageResponses(product.Images),
+		Seller:      NewSeller(&product.User),
+
+		FavoriteCount: product.FavoriteCount,
 	}
 }
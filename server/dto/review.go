@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"time"
+
+	"estore-server/models"
+)
+
+// ReviewRequest DTO for writing or editing a review of a seller
+type ReviewRequest struct {
+	Rating  int    `json:"rating" binding:"required,gte=1,lte=5"`
+	Comment string `json:"comment" binding:"max=1000"`
+}
+
+// ReviewReplyRequest DTO for a seller's reply to a review
+type ReviewReplyRequest struct {
+	Reply string `json:"reply" binding:"required,max=1000"`
+}
+
+// ReviewListQuery represents the query string of GET /user/:id/reviews
+type ReviewListQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,gte=1,lte=100"`
+}
+
+// Reviewer represents the author of a review
+type Reviewer struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+// ReviewResponse represents a review returned to clients
+type ReviewResponse struct {
+	ID        uint       `json:"id"`
+	SellerID  uint       `json:"seller_id"`
+	Rating    int        `json:"rating"`
+	Comment   string     `json:"comment"`
+	Reply     string     `json:"reply"`
+	RepliedAt *time.Time `json:"replied_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Reviewer  *Reviewer  `json:"reviewer"` // null once the author's account is deleted
+}
+
+func NewReviewResponse(review *models.Review) ReviewResponse {
+	response := ReviewResponse{
+		ID:        review.ID,
+		SellerID:  review.SellerID,
+		Rating:    review.Rating,
+		Comment:   review.Comment,
+		Reply:     review.Reply,
+		RepliedAt: review.RepliedAt,
+		CreatedAt: review.CreatedAt,
+		UpdatedAt: review.UpdatedAt,
+	}
+	if review.Reviewer != nil {
+		response.Reviewer = &Reviewer{ID: review.Reviewer.ID, Username: review.Reviewer.Username}
+	}
+	return response
+}
+
+func NewReviewResponses(reviews []models.Review) []ReviewResponse {
+	response := make([]ReviewResponse, 0, len(reviews))
+	for i := range reviews {
+		response = append(response, NewReviewResponse(&reviews[i]))
+	}
+	return response
+}
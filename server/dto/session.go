@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"time"
+
+	"estore-server/models"
+)
+
+// SessionResponse describes one of the current user's logged-in devices
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	Device     string    `json:"device"` // User-Agent the session logged in with
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+func NewSessionResponses(sessions []models.Session, currentID string) []SessionResponse {
+	responses := make([]SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		responses = append(responses, SessionResponse{
+			ID:         session.ID,
+			Device:     session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID == currentID,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"net/http"
+	"time"
+
+	"estore-server/models"
+)
+
+// ErrorCodeAccountSuspended identifies suspension errors to clients independently of the message
+const ErrorCodeAccountSuspended = "account_suspended"
+
+// SuspendUserRequest DTO for suspending a user; omit expires_at to suspend until lifted
+type SuspendUserRequest struct {
+	Reason    string     `json:"reason" binding:"required,max=500"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// SuspensionDTO DTO for a suspension in a user's moderation history
+type SuspensionDTO struct {
+	ID         uint       `json:"id"`
+	UserID     uint       `json:"user_id"`
+	Reason     string     `json:"reason"`
+	IssuedByID uint       `json:"issued_by_id"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LiftedAt   *time.Time `json:"lifted_at"`
+	LiftedByID *uint      `json:"lifted_by_id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Active     bool       `json:"active"`
+}
+
+// AccountSuspendedError is the data of the 403 response a suspended user receives
+type AccountSuspendedError struct {
+	Error       string     `json:"error"`
+	Reason      string     `json:"reason"`
+	SuspendedAt time.Time  `json:"suspended_at"`
+	ExpiresAt   *time.Time `json:"expires_at"` // null if the suspension has no end date
+}
+
+func NewSuspensionDTO(suspension *models.Suspension) SuspensionDTO {
+	return SuspensionDTO{
+		ID:         suspension.ID,
+		UserID:     suspension.UserID,
+		Reason:     suspension.Reason,
+		IssuedByID: suspension.IssuedByID,
+		ExpiresAt:  suspension.ExpiresAt,
+		LiftedAt:   suspension.LiftedAt,
+		LiftedByID: suspension.LiftedByID,
+		CreatedAt:  suspension.CreatedAt,
+		Active:     suspension.IsActive(time.Now()),
+	}
+}
+
+func NewSuspensionDTOs(suspensions []models.Suspension) []SuspensionDTO {
+	dtos := make([]SuspensionDTO, 0, len(suspensions))
+	for i := range suspensions {
+		dtos = append(dtos, NewSuspensionDTO(&suspensions[i]))
+	}
+	return dtos
+}
+
+// NewAccountSuspendedResponse creates the error response telling a user why and until when
+// their account is suspended
+func NewAccountSuspendedResponse(suspension *models.Suspension) Response {
+	response := NewErrorResponse(http.StatusForbidden, "Account suspended")
+	response.Data = AccountSuspendedError{
+		Error:       ErrorCodeAccountSuspended,
+		Reason:      suspension.Reason,
+		SuspendedAt: suspension.CreatedAt,
+		ExpiresAt:   suspension.ExpiresAt,
+	}
+	return response
+}
@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"time"
+
+	"estore-server/models"
+)
+
+// TrashedProductResponse represents a soft-deleted product in the admin trash view
+type TrashedProductResponse struct {
+	ProductResponse
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// TrashedUserDTO DTO for a soft-deleted user in the admin trash view
+type TrashedUserDTO struct {
+	UserDTO
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func NewTrashedProductResponses(products []models.Product) []TrashedProductResponse {
+	response := make([]TrashedProductResponse, 0, len(products))
+	for i := range products {
+		response = append(response, TrashedProductResponse{
+			ProductResponse: NewProductResponse(&products[i]),
+			DeletedAt:       products[i].DeletedAt.Time,
+		})
+	}
+	return response
+}
+
+func NewTrashedUserDTOs(users []models.User) []TrashedUserDTO {
+	dtos := make([]TrashedUserDTO, 0, len(users))
+	for i := range users {
+		dtos = append(dtos, TrashedUserDTO{
+			UserDTO:   *NewUserDTO(&users[i]),
+			DeletedAt: users[i].DeletedAt.Time,
+		})
+	}
+	return dtos
+}
@@ -5,14 +5,14 @@ import "estore-server/models"
 // RegisterRequest DTO for user registration
 type RegisterRequest struct {
 	Username string `json:"username" binding:"required"`
-	Email    string `json:"email" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
 }
 
 // UpdateUserRequest DTO for updating user information
 type UpdateUserRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=100"`
-	Email    string `json:"email"`
+	Email    string `json:"email" binding:"omitempty,email"`
 	Phone    string `json:"phone"`
 	Address  string `json:"address"`
 }
@@ -23,21 +23,44 @@ type UpdatePasswordRequest struct {
 	NewPassword string `json:"new_password" binding:"required,min=6"`
 }
 
+// ForgotPasswordRequest DTO for requesting a password reset email
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest DTO for setting a new password with an emailed reset token
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+// VerifyEmailRequest DTO for confirming an email address with an emailed token
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
 // UserDTO DTO for user information
 type UserDTO struct {
-	ID       uint   `json:"id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Phone    string `json:"phone"`
-	Address  string `json:"address"`
-	IsAdmin  bool   `json:"is_admin"`
+	ID       uint     `json:"id"`
+	Username string   `json:"username"`
+	Email    string   `json:"email"`
+	Phone    string   `json:"phone"`
+	Address  string   `json:"address"`
+	IsAdmin  bool     `json:"is_admin"`
+	Roles    []string `json:"roles"`
+
+	EmailVerified bool `json:"email_verified"`
+
+	Rating      float64 `json:"rating"` // Average stars received as a seller, 0 without reviews
+	ReviewCount int     `json:"review_count"`
 }
 
 type PartialUserDTO struct {
-	ID       uint   `json:"id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	IsAdmin  bool   `json:"is_admin"`
+	ID       uint     `json:"id"`
+	Username string   `json:"username"`
+	Email    string   `json:"email"`
+	IsAdmin  bool     `json:"is_admin"`
+	Roles    []string `json:"roles"`
 }
 
 func NewUserDTO(user *models.User) *UserDTO {
@@ -47,7 +70,13 @@ func NewUserDTO(user *models.User) *UserDTO {
 		Email:    user.Email,
 		Phone:    user.Phone,
 		Address:  user.Address,
-		IsAdmin:  user.IsAdmin,
+		IsAdmin:  user.HasRole(models.RoleAdmin),
+		Roles:    user.RoleNames(),
+
+		EmailVerified: user.IsEmailVerified(),
+
+		Rating:      user.AverageRating(),
+		ReviewCount: user.RatingCount,
 	}
 }
 
@@ -56,6 +85,31 @@ func NewParitialUserDTO(user *models.User) *PartialUserDTO {
 		ID:       user.ID,
 		Username: user.Username,
 		Email:    user.Email,
-		IsAdmin:  user.IsAdmin,
+		IsAdmin:  user.HasRole(models.RoleAdmin),
+		Roles:    user.RoleNames(),
+	}
+}
+
+// AssignRoleRequest DTO for granting a role to a user
+type AssignRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// RoleDTO DTO for a role and the permissions it grants
+type RoleDTO struct {
+	ID          uint     `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+func NewRoleDTOs(roles []models.Role) []RoleDTO {
+	dtos := make([]RoleDTO, 0, len(roles))
+	for _, role := range roles {
+		permissions := make([]string, 0, len(role.Permissions))
+		for _, permission := range role.Permissions {
+			permissions = append(permissions, permission.Name)
+		}
+		dtos = append(dtos, RoleDTO{ID: role.ID, Name: role.Name, Permissions: permissions})
 	}
+	return dtos
 }
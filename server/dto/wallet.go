@@ -0,0 +1,20 @@
+package dto
+
+// WalletAmountRequest DTO for depositing to or withdrawing from a wallet
+type WalletAmountRequest struct {
+	Amount int    `json:"amount" binding:"required,gte=1"`
+	Memo   string `json:"memo" binding:"max=255"`
+}
+
+// WalletTransferRequest DTO for sending money to another user's wallet
+type WalletTransferRequest struct {
+	ToUserID uint   `json:"to_user_id" binding:"required"`
+	Amount   int    `json:"amount" binding:"required,gte=1"`
+	Memo     string `json:"memo" binding:"max=255"`
+}
+
+// WalletStatementQuery represents the query string of GET /wallet/statement
+type WalletStatementQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,gte=1,lte=100"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "estore-server/models"
+
+// WebhookEndpointRequest DTO for registering a webhook endpoint
+type WebhookEndpointRequest struct {
+	URL         string   `json:"url" binding:"required,url,max=500"`
+	Description string   `json:"description" binding:"max=255"`
+	Events      []string `json:"events" binding:"required,min=1,dive,required"` // Event types, or "*" for all
+}
+
+// UpdateWebhookEndpointRequest DTO for changing a webhook endpoint
+type UpdateWebhookEndpointRequest struct {
+	WebhookEndpointRequest
+	Active *bool `json:"active" binding:"required"`
+}
+
+// WebhookEndpointCreatedResponse is returned once when an endpoint is registered; it is
+// the only time the signing secret is shown
+type WebhookEndpointCreatedResponse struct {
+	*models.WebhookEndpoint
+	Secret string `json:"secret"`
+}
+
+// WebhookDeliveryListQuery represents the query string of GET /admin/webhook/:id/deliveries
+type WebhookDeliveryListQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,gte=1,lte=200"`
+}
@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"estore-server/models"
+
+	"gorm.io/gorm"
+)
+
+// DefaultMaxAttempts is how often a job runs before it is dead-lettered
+const DefaultMaxAttempts = 5
+
+// Handler runs one job. Returning an error schedules a retry with exponential backoff
+// until the job runs out of attempts. ctx is cancelled when the runner loses the job's lease,
+// for example because it could not renew it, or when the runner stops.
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// EnqueueOptions adjusts a job when it is enqueued; zero fields take the defaults
+type EnqueueOptions struct {
+	RunAt       time.Time // Defaults to now
+	MaxAttempts int       // Defaults to DefaultMaxAttempts
+}
+
+// Enqueue stores a job for the runner to pick up. Pass a transaction as db to enqueue the
+// job only if the surrounding change commits.
+func Enqueue(ctx context.Context, db *gorm.DB, jobType string, payload any, opts EnqueueOptions) (*models.Job, error) {
+	data, err := marshalPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &models.Job{
+		Type:        jobType,
+		Payload:     data,
+		Status:      models.JobStatusPending,
+		RunAt:       opts.RunAt,
+		MaxAttempts: opts.MaxAttempts,
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now()
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = DefaultMaxAttempts
+	}
+	if err := gorm.G[models.Job](db).Create(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// marshalPayload renders a payload as JSON, keeping nil as SQL NULL
+func marshalPayload(payload any) (json.RawMessage, error) {
+	if payload == nil {
+		return nil, nil
+	}
+	return json.Marshal(payload)
+}
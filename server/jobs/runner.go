@@ -0,0 +1,361 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"estore-server/models"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// pollInterval is how often the runner looks for due jobs and schedules when idle
+	pollInterval = 2 * time.Second
+
+	// defaultLease is how long a claim lasts without being renewed. A running job renews
+	// its lease every third of that, so only a job whose runner died or lost the database
+	// for that long is treated as abandoned by other runners.
+	defaultLease = 5 * time.Minute
+
+	// Retries wait baseBackoff, then twice as long after each failure, up to maxBackoff
+	baseBackoff = 10 * time.Second
+	maxBackoff  = time.Hour
+
+	// claimBatch bounds how many due jobs one claim attempt considers
+	claimBatch = 10
+)
+
+// Runner executes jobs from the jobs table with a fixed pool of workers. Any number of
+// runners may share a database: a job is claimed with a conditional update, so only one
+// of them runs it.
+type Runner struct {
+	db    *gorm.DB
+	id    string
+	lease time.Duration
+	slots chan struct{}
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+// NewRunner returns a runner that runs up to workers jobs at a time
+func NewRunner(db *gorm.DB, workers int) *Runner {
+	host, _ := os.Hostname()
+	suffix, _ := randomSuffix()
+	return &Runner{
+		db:       db,
+		id:       fmt.Sprintf("%s-%d-%s", host, os.Getpid(), suffix),
+		lease:    defaultLease,
+		slots:    make(chan struct{}, max(workers, 1)),
+		handlers: make(map[string]Handler),
+	}
+}
+
+// Register sets the handler for jobs of jobType. The runner only claims job types it has
+// a handler for, so runners with different handler sets can share a database.
+func (r *Runner) Register(jobType string, handler Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[jobType] = handler
+}
+
+// Schedule makes sure a recurring schedule named name enqueues a jobType job whenever the
+// cron spec comes due. The first run is due immediately. Calling it again with a changed
+// spec or payload updates the schedule.
+func (r *Runner) Schedule(name, spec, jobType string, payload any) error {
+	if _, err := cron.ParseStandard(spec); err != nil {
+		return fmt.Errorf("invalid schedule %q: %w", spec, err)
+	}
+	data, err := marshalPayload(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	existing, err := gorm.G[models.JobSchedule](r.db).Where("name = ?", name).First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Another runner may be creating the same schedule; the unique name keeps one
+		schedule := models.JobSchedule{Name: name, Spec: spec, Type: jobType, Payload: data, NextRunAt: time.Now()}
+		return gorm.G[models.JobSchedule](r.db, clause.OnConflict{DoNothing: true}).Create(ctx, &schedule)
+	}
+	if err != nil {
+		return err
+	}
+	if existing.Spec == spec && existing.Type == jobType && string(existing.Payload) == string(data) {
+		return nil
+	}
+	_, err = gorm.G[models.JobSchedule](r.db).Where("id = ?", existing.ID).Set(clause.Assignments(map[string]any{
+		"spec":        spec,
+		"type":        jobType,
+		"payload":     data,
+		"next_run_at": time.Now(),
+	})).Update(ctx)
+	return err
+}
+
+// Start runs the runner in the background until ctx is cancelled
+func (r *Runner) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			r.fireSchedules(ctx)
+			r.dispatch(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// fireSchedules enqueues a job for every schedule that has come due. Moving NextRunAt
+// on is conditional on the value just read, so each occurrence enqueues one job however
+// many runners see it.
+func (r *Runner) fireSchedules(ctx context.Context) {
+	now := time.Now()
+	due, err := gorm.G[models.JobSchedule](r.db).Where("next_run_at <= ?", now).Find(ctx)
+	if err != nil {
+		log.Printf("jobs: failed to load schedules: %v", err)
+		return
+	}
+
+	for _, schedule := range due {
+		spec, err := cron.ParseStandard(schedule.Spec)
+		if err != nil {
+			log.Printf("jobs: schedule %s has an invalid spec %q: %v", schedule.Name, schedule.Spec, err)
+			continue
+		}
+
+		err = r.db.Transaction(func(tx *gorm.DB) error {
+			rows, err := gorm.G[models.JobSchedule](tx).Where("id = ? AND next_run_at = ?", schedule.ID, schedule.NextRunAt).
+				Updates(ctx, models.JobSchedule{NextRunAt: spec.Next(now), LastRunAt: &now})
+			if err != nil || rows == 0 {
+				return err
+			}
+
+			job := models.Job{
+				Type:        schedule.Type,
+				Payload:     schedule.Payload,
+				Status:      models.JobStatusPending,
+				RunAt:       now,
+				MaxAttempts: DefaultMaxAttempts,
+				ScheduleID:  &schedule.ID,
+			}
+			return gorm.G[models.Job](tx).Create(ctx, &job)
+		})
+		if err != nil {
+			log.Printf("jobs: failed to fire schedule %s: %v", schedule.Name, err)
+		}
+	}
+}
+
+// dispatch claims due jobs and hands them to workers for as long as workers are free
+func (r *Runner) dispatch(ctx context.Context) {
+	for {
+		select {
+		case r.slots <- struct{}{}:
+		default:
+			return
+		}
+
+		job, err := r.claim(ctx)
+		if err != nil || job == nil {
+			<-r.slots
+			if err != nil {
+				log.Printf("jobs: failed to claim a job: %v", err)
+			}
+			return
+		}
+
+		go func() {
+			defer func() { <-r.slots }()
+			r.run(ctx, job)
+		}()
+	}
+}
+
+// claim takes the next due job, or one whose previous runner's lease ran out. Attempts
+// goes up with every claim, so it doubles as a fencing token: the conditional update only
+// succeeds for the runner that saw the job's current attempt.
+func (r *Runner) claim(ctx context.Context) (*models.Job, error) {
+	types := r.jobTypes()
+	if len(types) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	candidates, err := gorm.G[models.Job](r.db).
+		Where("type IN ? AND ((status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?))",
+			types, models.JobStatusPending, now, models.JobStatusRunning, now).
+		Order("run_at").Limit(claimBatch).Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range candidates {
+		claimed := gorm.G[models.Job](r.db).Where("id = ? AND status = ? AND attempts = ?", job.ID, job.Status, job.Attempts)
+
+		// An abandoned job that has used up its attempts is dead rather than run again
+		if job.Status == models.JobStatusRunning && job.Attempts >= job.MaxAttempts {
+			if _, err := claimed.Updates(ctx, models.Job{Status: models.JobStatusDead, LastError: "lease expired", FinishedAt: &now}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		lockedUntil := now.Add(r.lease)
+		rows, err := claimed.Updates(ctx, models.Job{
+			Status:      models.JobStatusRunning,
+			Attempts:    job.Attempts + 1,
+			LockedBy:    r.id,
+			LockedUntil: &lockedUntil,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if rows == 1 {
+			job.Status = models.JobStatusRunning
+			job.Attempts++
+			job.LockedBy = r.id
+			job.LockedUntil = &lockedUntil
+			return &job, nil
+		}
+	}
+	return nil, nil
+}
+
+// run executes a claimed job, renewing its lease until it finishes, and records the
+// outcome. The update only applies while this runner still holds the claim; if the lease
+// ran out and another runner took the job over, this outcome is dropped.
+func (r *Runner) run(ctx context.Context, job *models.Job) {
+	jobCtx, cancel := context.WithCancel(ctx)
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		r.renewLease(jobCtx, cancel, job)
+	}()
+	runErr := r.call(jobCtx, job)
+	cancel()
+	<-renewed
+
+	now := time.Now()
+	outcome := map[string]any{"locked_by": "", "locked_until": nil}
+	switch {
+	case runErr == nil:
+		outcome["status"] = models.JobStatusSucceeded
+		outcome["last_error"] = ""
+		outcome["finished_at"] = now
+	case job.Attempts >= job.MaxAttempts:
+		log.Printf("jobs: %s job %d failed its last attempt: %v", job.Type, job.ID, runErr)
+		outcome["status"] = models.JobStatusDead
+		outcome["last_error"] = runErr.Error()
+		outcome["finished_at"] = now
+	default:
+		outcome["status"] = models.JobStatusPending
+		outcome["last_error"] = runErr.Error()
+		outcome["run_at"] = now.Add(backoff(job.Attempts))
+	}
+
+	rows, err := gorm.G[models.Job](r.db).Where("id = ? AND locked_by = ? AND attempts = ?", job.ID, r.id, job.Attempts).
+		Set(clause.Assignments(outcome)).Update(context.Background())
+	if err != nil {
+		log.Printf("jobs: failed to record the outcome of job %d: %v", job.ID, err)
+	} else if rows == 0 {
+		log.Printf("jobs: job %d was taken over by another runner before it finished", job.ID)
+	}
+}
+
+// renewLease extends the job's lease every third of the lease period until ctx is done. Once
+// the lease is lost, because another runner took the job over or because renewals kept
+// failing until it expired, cancel stops the handler so the job never runs twice at once.
+func (r *Runner) renewLease(ctx context.Context, cancel context.CancelFunc, job *models.Job) {
+	ticker := time.NewTicker(r.lease / 3)
+	defer ticker.Stop()
+
+	heldUntil := *job.LockedUntil
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		lockedUntil := time.Now().Add(r.lease)
+		rows, err := gorm.G[models.Job](r.db).Where("id = ? AND locked_by = ? AND attempts = ?", job.ID, r.id, job.Attempts).
+			Update(ctx, "locked_until", lockedUntil)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return
+		case err != nil:
+			log.Printf("jobs: failed to renew the lease of job %d: %v", job.ID, err)
+			if time.Now().After(heldUntil) {
+				cancel()
+				return
+			}
+		case rows == 0:
+			log.Printf("jobs: job %d was taken over by another runner; stopping it", job.ID)
+			cancel()
+			return
+		default:
+			heldUntil = lockedUntil
+		}
+	}
+}
+
+// call runs the job's handler, turning a panic into an error so one bad job cannot take
+// the runner down
+func (r *Runner) call(ctx context.Context, job *models.Job) (err error) {
+	r.mu.RLock()
+	handler, ok := r.handlers[job.Type]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no handler for job type %q", job.Type)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return handler(ctx, job.Payload)
+}
+
+func (r *Runner) jobTypes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]string, 0, len(r.handlers))
+	for jobType := range r.handlers {
+		types = append(types, jobType)
+	}
+	return types
+}
+
+// backoff returns how long to wait before retrying a job that has failed attempts times
+func backoff(attempts int) time.Duration {
+	delay := baseBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+// randomSuffix tells apart runners that share a host and PID, such as restarted containers
+func randomSuffix() (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,294 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"estore-server/models"
+	"estore-server/testdb"
+
+	"gorm.io/gorm"
+)
+
+// waitFor polls cond until it holds, failing the test after timeout
+func waitFor(t *testing.T, timeout time.Duration, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// loadJob reads the job's current row
+func loadJob(t *testing.T, db *gorm.DB, id uint) models.Job {
+	t.Helper()
+	job, err := gorm.G[models.Job](db).Where("id = ?", id).First(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+func TestRunnersSharingADatabaseRunEachJobOnce(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var mu sync.Mutex
+		runs := make(map[int]int)
+		handler := func(ctx context.Context, payload json.RawMessage) error {
+			var n int
+			if err := json.Unmarshal(payload, &n); err != nil {
+				return err
+			}
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			runs[n]++
+			mu.Unlock()
+			return nil
+		}
+
+		const total = 20
+		for n := range total {
+			if _, err := Enqueue(ctx, db, "count", n, EnqueueOptions{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for range 2 {
+			runner := NewRunner(db, 3)
+			runner.Register("count", handler)
+			runner.Start(ctx)
+		}
+
+		waitFor(t, 30*time.Second, "every job to succeed", func() bool {
+			count, err := gorm.G[models.Job](db).Where("status = ?", models.JobStatusSucceeded).Count(ctx, "id")
+			return err == nil && count == total
+		})
+		mu.Lock()
+		defer mu.Unlock()
+		for n := range total {
+			if runs[n] != 1 {
+				t.Errorf("job %d ran %d times, want once", n, runs[n])
+			}
+		}
+	})
+}
+
+func TestFailingJobBacksOffThenDies(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		runner := NewRunner(db, 1)
+		runner.Register("fail", func(ctx context.Context, payload json.RawMessage) error {
+			return errors.New("boom")
+		})
+		enqueued, err := Enqueue(ctx, db, "fail", nil, EnqueueOptions{MaxAttempts: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		job, err := runner.claim(ctx)
+		if err != nil || job == nil {
+			t.Fatalf("claim: %v, %v", job, err)
+		}
+		before := time.Now()
+		runner.run(ctx, job)
+
+		retry := loadJob(t, db, enqueued.ID)
+		if retry.Status != models.JobStatusPending || retry.Attempts != 1 || retry.LastError != "boom" {
+			t.Fatalf("after one failure the job is %s with %d attempts and error %q", retry.Status, retry.Attempts, retry.LastError)
+		}
+		if retry.RunAt.Before(before.Add(baseBackoff - time.Second)) {
+			t.Errorf("retry is due at %s, want about %s from now", retry.RunAt, baseBackoff)
+		}
+		if job, err := runner.claim(ctx); err != nil || job != nil {
+			t.Fatalf("claimed the job before its backoff ended: %v, %v", job, err)
+		}
+
+		// Skip the wait
+		if _, err := gorm.G[models.Job](db).Where("id = ?", enqueued.ID).Update(ctx, "run_at", time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		job, err = runner.claim(ctx)
+		if err != nil || job == nil {
+			t.Fatalf("claim the retry: %v, %v", job, err)
+		}
+		runner.run(ctx, job)
+
+		dead := loadJob(t, db, enqueued.ID)
+		if dead.Status != models.JobStatusDead || dead.Attempts != 2 || dead.FinishedAt == nil {
+			t.Errorf("after its last attempt the job is %s with %d attempts", dead.Status, dead.Attempts)
+		}
+	})
+}
+
+func TestBackoffDoublesUpToTheCap(t *testing.T) {
+	for attempts, want := range map[int]time.Duration{
+		1:  baseBackoff,
+		2:  2 * baseBackoff,
+		3:  4 * baseBackoff,
+		50: maxBackoff,
+	} {
+		if got := backoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
+
+func TestRunningJobKeepsItsLease(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		started := make(chan struct{})
+		stopped := make(chan error, 1)
+		first := NewRunner(db, 1)
+		first.lease = 300 * time.Millisecond
+		first.Register("slow", func(ctx context.Context, payload json.RawMessage) error {
+			close(started)
+			<-ctx.Done()
+			stopped <- ctx.Err()
+			return ctx.Err()
+		})
+		second := NewRunner(db, 1)
+		second.lease = first.lease
+		second.Register("slow", func(ctx context.Context, payload json.RawMessage) error {
+			t.Error("a job was run while another runner still held it")
+			return nil
+		})
+
+		enqueued, err := Enqueue(ctx, db, "slow", nil, EnqueueOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		job, err := first.claim(ctx)
+		if err != nil || job == nil {
+			t.Fatalf("claim: %v, %v", job, err)
+		}
+		go first.run(ctx, job)
+		<-started
+
+		// Well past the original lease, the renewed claim still keeps other runners out
+		time.Sleep(3 * first.lease)
+		if job, err := second.claim(ctx); err != nil || job != nil {
+			t.Fatalf("second runner claimed a job that is still running: %v, %v", job, err)
+		}
+
+		// Once another runner owns the job, the first one stops its handler
+		if _, err := gorm.G[models.Job](db).Where("id = ?", enqueued.ID).Update(ctx, "locked_by", "elsewhere"); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case err := <-stopped:
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("handler stopped with %v, want cancellation", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("handler kept running after losing its lease")
+		}
+	})
+}
+
+func TestAbandonedJobIsTakenOverOrDeadLettered(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		crashed := NewRunner(db, 1)
+		crashed.Register("work", func(ctx context.Context, payload json.RawMessage) error { return nil })
+		survivor := NewRunner(db, 1)
+		survivor.Register("work", func(ctx context.Context, payload json.RawMessage) error { return nil })
+
+		retried, err := Enqueue(ctx, db, "work", nil, EnqueueOptions{MaxAttempts: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		lastTry, err := Enqueue(ctx, db, "work", nil, EnqueueOptions{MaxAttempts: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The crashed runner claims both jobs and never comes back
+		for range 2 {
+			if job, err := crashed.claim(ctx); err != nil || job == nil {
+				t.Fatalf("claim: %v, %v", job, err)
+			}
+		}
+		if _, err := gorm.G[models.Job](db).Where("id IN ?", []uint{retried.ID, lastTry.ID}).
+			Update(ctx, "locked_until", time.Now().Add(-time.Second)); err != nil {
+			t.Fatal(err)
+		}
+
+		// The survivor claims whatever it can; the job out of attempts is never handed out
+		var taken []*models.Job
+		for {
+			job, err := survivor.claim(ctx)
+			if err != nil {
+				t.Fatalf("claim an abandoned job: %v", err)
+			}
+			if job == nil {
+				break
+			}
+			taken = append(taken, job)
+		}
+		if len(taken) != 1 {
+			t.Fatalf("took over %d jobs, want 1", len(taken))
+		}
+		job := taken[0]
+		if job.ID != retried.ID || job.Attempts != 2 || job.LockedBy != survivor.id {
+			t.Errorf("took over job %d at attempt %d for %s, want job %d at attempt 2", job.ID, job.Attempts, job.LockedBy, retried.ID)
+		}
+		if dead := loadJob(t, db, lastTry.ID); dead.Status != models.JobStatusDead {
+			t.Errorf("abandoned job on its last attempt is %s, want dead", dead.Status)
+		}
+
+		// The crashed runner's late outcome no longer counts
+		late := loadJob(t, db, retried.ID)
+		late.LockedBy, late.Attempts = crashed.id, 1
+		crashed.run(ctx, &late)
+		if current := loadJob(t, db, retried.ID); current.Status != models.JobStatusRunning || current.LockedBy != survivor.id {
+			t.Errorf("a superseded runner changed the job to %s held by %q", current.Status, current.LockedBy)
+		}
+	})
+}
+
+func TestScheduleFiresOncePerOccurrence(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		runners := []*Runner{NewRunner(db, 1), NewRunner(db, 1)}
+		for _, runner := range runners {
+			if err := runner.Schedule("hourly", "@hourly", "tick", nil); err != nil {
+				t.Fatalf("schedule: %v", err)
+			}
+		}
+
+		// Both runners see the first occurrence come due
+		for range 2 {
+			for _, runner := range runners {
+				runner.fireSchedules(ctx)
+			}
+		}
+
+		fired, err := gorm.G[models.Job](db).Where("type = ?", "tick").Find(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(fired) != 1 {
+			t.Fatalf("the schedule enqueued %d jobs, want 1", len(fired))
+		}
+		schedule, err := gorm.G[models.JobSchedule](db).Where("name = ?", "hourly").First(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fired[0].ScheduleID == nil || *fired[0].ScheduleID != schedule.ID {
+			t.Errorf("job belongs to schedule %v, want %d", fired[0].ScheduleID, schedule.ID)
+		}
+		if until := time.Until(schedule.NextRunAt); until <= 0 || until > time.Hour {
+			t.Errorf("next run is due in %s, want within the next hour", until)
+		}
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"gorm.io/gorm"
 
 	"estore-server/config"
+	"estore-server/realtime"
 	"estore-server/route"
 )
 
@@ -27,11 +28,49 @@ func main() {
 	// Initialize database
 	db = config.ConnectDatabase()
 
-	// Migrate the schema
-	config.MigrateDatabase(db)
+	// "migrate up|down|status" manages the schema instead of starting the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(db, os.Args[2:])
+		return
+	}
+
+	// "search rebuild" makes every running server rebuild its search index
+	if len(os.Args) > 1 && os.Args[1] == "search" {
+		runSearch(db, os.Args[2:])
+		return
+	}
+
+	// Refuse to serve against an out-of-date schema
+	config.EnsureSchema(db)
+
+	// Seed built-in roles and permissions
+	config.SeedDatabase(db)
+
+	// Initialize blob storage for uploaded files
+	store := config.ConnectBlobStore()
+
+	// Initialize outgoing email
+	mailer := config.ConnectMailer()
+
+	// Build the full-text product search index
+	searchIndex := config.ConnectSearchIndex(db)
+
+	// Collect payments through the configured provider
+	paymentProvider := config.ConnectPaymentProvider()
+
+	// Deliver live events such as new messages and notifications to connected clients
+	broker := realtime.NewMemoryBroker()
+
+	// Run deferred and recurring work, such as purging expired trash, in the background
+	config.StartJobs(db, store, searchIndex, mailer)
+
+	// Set up Gin. Access tokens in the query string are taken out before the logger runs,
+	// so they never reach the access log.
+	r := gin.New()
+	r.Use(middleware.StripQueryToken("token"), gin.Logger(), gin.Recovery())
 
-	// Set up Gin
-	r := gin.Default()
+	// Tag every request with an ID for logs and the audit trail
+	r.Use(middleware.RequestIDMiddleware())
 
 	// Add CORS middleware
 	r.Use(middleware.CORSMiddleware())
@@ -42,9 +81,22 @@ func main() {
 	authMiddleware := middleware.AuthMiddleware(db)
 
 	routes := []route.RouteModule{
-		route.NewUserRoutesModule(db),
+		route.NewUserRoutesModule(db, mailer, broker),
 		route.NewAuthRoutesModule(authMiddleware),
-		route.NewProductRoutesModule(db),
+		route.NewProductRoutesModule(db, store, searchIndex, broker),
+		route.NewCartRoutesModule(db),
+		route.NewFavoriteRoutesModule(db),
+		route.NewOrderRoutesModule(db, broker),
+		route.NewReviewRoutesModule(db, broker),
+		route.NewMessageRoutesModule(db, broker, authMiddleware),
+		route.NewOfferRoutesModule(db, config.OfferTTL(), broker),
+		route.NewPaymentRoutesModule(db, paymentProvider, broker),
+		route.NewWalletRoutesModule(db, broker),
+		route.NewNotificationRoutesModule(db, broker, authMiddleware),
+		route.NewAuditRoutesModule(db),
+		route.NewJobRoutesModule(db),
+		route.NewWebhookRoutesModule(db),
+		route.NewTrashRoutesModule(db, store, searchIndex),
 	}
 
 	// Register routes
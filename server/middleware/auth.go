@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"os"
@@ -9,6 +10,7 @@ import (
 
 	"estore-server/dto"
 	"estore-server/models"
+	"estore-server/service"
 	"estore-server/service/impl"
 
 	ginjwt "github.com/appleboy/gin-jwt/v3"
@@ -18,7 +20,17 @@ import (
 	"gorm.io/gorm"
 )
 
-const IdentityKey = "user"
+const (
+	IdentityKey  = "user"
+	SessionIDKey = "session_id"
+
+	// authErrorKey carries why identityHandler rejected a token, so the response
+	// can say so instead of gin-jwt's generic 403
+	authErrorKey = "auth_error"
+)
+
+// refreshTimeout is how long a session lives without being refreshed
+const refreshTimeout = time.Hour * 24 * 7
 
 func AuthMiddleware(db *gorm.DB) *ginjwt.GinJWTMiddleware {
 	authMiddleware, err := ginjwt.New(initParams(db))
@@ -36,20 +48,25 @@ func AuthMiddleware(db *gorm.DB) *ginjwt.GinJWTMiddleware {
 
 func initParams(db *gorm.DB) *ginjwt.GinJWTMiddleware {
 	authService := impl.NewAuthServiceImpl(db)
+	sessionService := impl.NewSessionServiceImpl(db)
+	roleService := impl.NewRoleServiceImpl(db)
+	suspensionService := impl.NewSuspensionServiceImpl(db, nil)
 
 	return &ginjwt.GinJWTMiddleware{
-		Key:             []byte(getKey()),
-		Timeout:         time.Hour,
-		MaxRefresh:      time.Hour * 24 * 7,
-		Authenticator:   authService.LoginAuthenticator,
-		Unauthorized:    unauthorized,
-		PayloadFunc:     payloadFunc,
-		LogoutResponse:  logoutResponse,
-		IdentityHandler: identityHandler,
-		Authorizer:      authorizator,
-		LoginResponse:   loginResponse,
-		IdentityKey:     IdentityKey,
-		RefreshResponse: refreshResponse,
+		Key:                 []byte(getKey()),
+		Timeout:             time.Hour,
+		MaxRefresh:          refreshTimeout,
+		RefreshTokenTimeout: refreshTimeout,
+		RefreshTokenStore:   &sessionTokenStore{sessions: sessionService},
+		Authenticator:       loginAuthenticator(authService, sessionService),
+		Unauthorized:        unauthorized,
+		PayloadFunc:         payloadFunc,
+		LogoutResponse:      logoutResponse(sessionService),
+		IdentityHandler:     identityHandler(sessionService, roleService, suspensionService),
+		Authorizer:          authorizator,
+		LoginResponse:       loginResponse,
+		IdentityKey:         IdentityKey,
+		RefreshResponse:     refreshResponse,
 
 		TimeFunc: time.Now,
 	}
@@ -64,33 +81,103 @@ func getKey() string {
 }
 
 func unauthorized(c *gin.Context, code int, message string) {
+	if err, ok := c.Get(authErrorKey); ok {
+		var suspended *service.SuspendedError
+		if errors.As(err.(error), &suspended) {
+			c.JSON(http.StatusForbidden, dto.NewAccountSuspendedResponse(suspended.Suspension))
+			return
+		}
+		code, message = http.StatusUnauthorized, err.(error).Error()
+	}
 	c.JSON(code, dto.NewErrorResponse(code, message))
 }
 
+// loginAuthenticator checks the credentials and opens a session for the device logging in
+func loginAuthenticator(authService service.AuthService, sessionService service.SessionService) func(c *gin.Context) (any, error) {
+	return func(c *gin.Context) (any, error) {
+		data, err := authService.LoginAuthenticator(c)
+		if err != nil {
+			var suspended *service.SuspendedError
+			if errors.As(err, &suspended) {
+				c.Set(authErrorKey, err)
+			}
+			return nil, err
+		}
+
+		user := data.(*models.User)
+		session, err := sessionService.CreateSession(user.ID, c.Request.UserAgent(), c.ClientIP(), time.Now().Add(refreshTimeout))
+		if err != nil {
+			return nil, err
+		}
+		session.User = user
+
+		return session, nil
+	}
+}
+
 func payloadFunc(data any) jwt.MapClaims {
-	if user, ok := data.(*models.User); ok {
+	if session, ok := data.(*models.Session); ok && session.User != nil {
 		return jwt.MapClaims{
-			"user_id":  user.ID,
-			"is_admin": user.IsAdmin,
+			"user_id": session.User.ID,
+			"roles":   session.User.RoleNames(),
+			"sid":     session.ID,
 		}
 	}
 	return jwt.MapClaims{}
 }
 
-func identityHandler(c *gin.Context) any {
-	claims := ginjwt.ExtractClaims(c)
-	return &models.User{
-		ID:      uint(claims["user_id"].(float64)),
-		IsAdmin: claims["is_admin"].(bool),
+// identityHandler resolves the token to a user, rejecting tokens whose session has been
+// revoked or whose user is suspended, and loads the permissions granted by the user's
+// current roles
+func identityHandler(sessionService service.SessionService, roleService service.RoleService, suspensionService service.SuspensionService) func(c *gin.Context) any {
+	return func(c *gin.Context) any {
+		claims := ginjwt.ExtractClaims(c)
+		userID, _ := claims["user_id"].(float64)
+		sessionID, _ := claims["sid"].(string)
+
+		if _, err := sessionService.ValidateSession(sessionID, uint(userID), c.ClientIP()); err != nil {
+			c.Set(authErrorKey, err)
+			return nil
+		}
+		c.Set(SessionIDKey, sessionID)
+
+		// Checked on every request so a suspension also locks out tokens issued before it
+		suspension, err := suspensionService.ActiveSuspension(uint(userID))
+		if err != nil {
+			c.Set(authErrorKey, err)
+			return nil
+		}
+		if suspension != nil {
+			c.Set(authErrorKey, &service.SuspendedError{Suspension: suspension})
+			return nil
+		}
+
+		// Roles are read from the database rather than the token's "roles" claim, so granting
+		// or removing a role takes effect on the user's next request
+		roles, err := roleService.GetUserRoles(uint(userID))
+		if err != nil {
+			c.Set(authErrorKey, err)
+			return nil
+		}
+		user := &models.User{ID: uint(userID), Roles: roles}
+
+		permissions, err := roleService.Permissions(user.RoleNames())
+		if err != nil {
+			c.Set(authErrorKey, err)
+			return nil
+		}
+		c.Set(PermissionsKey, permissions)
+
+		return user
 	}
 }
 
 func authorizator(c *gin.Context, data any) bool {
 	if user, ok := data.(*models.User); ok {
-		// admin routes include "/admin/"
-		path := c.Request.URL.Path
-		if strings.Contains("/admin/", path) {
-			return user.IsAdmin
+		// Admin routes are for staff, i.e. anyone holding a permission; each route
+		// then requires the specific permission it needs via RequirePermission
+		if strings.HasPrefix(c.Request.URL.Path, "/api/admin/") {
+			return len(permissionsFromCtx(c)) > 0
 		}
 
 		return user.ID != 0
@@ -98,8 +185,21 @@ func authorizator(c *gin.Context, data any) bool {
 	return false
 }
 
-func logoutResponse(c *gin.Context) {
-	c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Successfully logged out"))
+// logoutResponse ends the session of the access token used to log out. The refresh
+// token, if sent, has already been revoked by gin-jwt through the session store.
+func logoutResponse(sessionService service.SessionService) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		if user, ok := c.Get(IdentityKey); ok {
+			userID := user.(*models.User).ID
+			actor := service.Actor{UserID: &userID, IP: c.ClientIP(), RequestID: c.GetString(RequestIDKey)}
+			if err := sessionService.RevokeSession(actor, userID, c.GetString(SessionIDKey)); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, err.Error()))
+				return
+			}
+		}
+
+		c.JSON(http.StatusOK, dto.NewSuccessResponse(http.StatusOK, nil, "Successfully logged out"))
+	}
 }
 
 func loginResponse(c *gin.Context, token *core.Token) {
@@ -1,20 +1,31 @@
 package middleware
 
 import (
+	"slices"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
+// allowedOrigins are the browser origins allowed to call the API
+var allowedOrigins = []string{
+	"http://localhost:5173", // Vite development server
+}
+
+// IsAllowedOrigin reports whether browser requests from origin are allowed, e.g. when
+// accepting a WebSocket handshake, which CORS does not cover
+func IsAllowedOrigin(origin string) bool {
+	return slices.Contains(allowedOrigins, origin)
+}
+
 // CORSMiddleware handles Cross-Origin Resource Sharing
 func CORSMiddleware() gin.HandlerFunc {
 	// Configure CORS with specific settings
 	config := cors.DefaultConfig()
-	config.AllowOrigins = []string{
-		"http://localhost:5173", // Vite development server
-	}
+	config.AllowOrigins = allowedOrigins
 	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE"}
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", "X-HTTP-Method-Override"}
-	config.ExposeHeaders = []string{"Content-Length", "Access-Control-Allow-Origin", "Access-Control-Allow-Credentials", "X-Response-Time"}
+	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", "X-HTTP-Method-Override", "X-Request-ID"}
+	config.ExposeHeaders = []string{"Content-Length", "Access-Control-Allow-Origin", "Access-Control-Allow-Credentials", "X-Response-Time", "X-Request-ID"}
 	config.AllowCredentials = true
 	config.MaxAge = 86400 // 24 hours in seconds
 
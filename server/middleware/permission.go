@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"estore-server/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionsKey holds the set of permissions granted to the authenticated user
+const PermissionsKey = "permissions"
+
+// RequirePermission rejects requests from users whose roles do not grant permission.
+// It must run after the JWT middleware, e.g. on routes registered by a RouteModule.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasPermission(c, permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, "Permission denied: requires "+permission))
+			return
+		}
+		c.Next()
+	}
+}
+
+// HasPermission reports whether the authenticated user's roles grant permission
+func HasPermission(c *gin.Context, permission string) bool {
+	return permissionsFromCtx(c)[permission]
+}
+
+func permissionsFromCtx(c *gin.Context) map[string]bool {
+	permissions, _ := c.Get(PermissionsKey)
+	granted, _ := permissions.(map[string]bool)
+	return granted
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// queryTokenKey holds the access token StripQueryToken took out of the query string
+const queryTokenKey = "query_token"
+
+// StripQueryToken removes an access token passed as the param query parameter from the
+// request URL and keeps it for TokenFromQuery. It has to run before the access logger,
+// which would otherwise write the bearer token to the logs.
+func StripQueryToken(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := c.Request.URL.Query()
+		if token := query.Get(param); token != "" {
+			c.Set(queryTokenKey, token)
+			query.Del(param)
+			c.Request.URL.RawQuery = query.Encode()
+			c.Request.RequestURI = c.Request.URL.RequestURI()
+		}
+		c.Next()
+	}
+}
+
+// TokenFromQuery lets clients that cannot set headers, such as browser WebSockets, pass
+// the access token in the query string. The token StripQueryToken kept aside is moved into
+// the Authorization header so the JWT middleware validates it like any other request; use
+// it only on such routes.
+func TokenFromQuery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := c.GetString(queryTokenKey); token != "" && c.GetHeader("Authorization") == "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"estore-server/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateWindow counts one client's requests in the current window
+type rateWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+// RateLimit lets each client IP make at most limit requests per window and answers the
+// rest with 429. Counts are kept in memory, so every server instance limits on its own.
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	var mu sync.Mutex
+	clients := make(map[string]*rateWindow)
+	nextSweep := time.Now().Add(window)
+
+	return func(c *gin.Context) {
+		now := time.Now()
+		ip := c.ClientIP()
+
+		mu.Lock()
+		// Forget clients whose window has ended so the map stays small
+		if now.After(nextSweep) {
+			for key, client := range clients {
+				if now.After(client.resetAt) {
+					delete(clients, key)
+				}
+			}
+			nextSweep = now.Add(window)
+		}
+		client, ok := clients[ip]
+		if !ok || now.After(client.resetAt) {
+			client = &rateWindow{resetAt: now.Add(window)}
+			clients[ip] = client
+		}
+		client.count++
+		allowed, retryAfter := client.count <= limit, client.resetAt.Sub(now)
+		mu.Unlock()
+
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, dto.NewErrorResponse(http.StatusTooManyRequests, "Too many requests, please try again later"))
+			return
+		}
+		c.Next()
+	}
+}
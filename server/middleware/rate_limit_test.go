@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimitCountsEachClientSeparately(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/forgot", RateLimit(2, time.Hour), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(ip string) int {
+		req := httptest.NewRequest(http.MethodPost, "/forgot", nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for _, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		if got := send("192.0.2.1"); got != want {
+			t.Errorf("first client got %d, want %d", got, want)
+		}
+	}
+	if got := send("192.0.2.2"); got != http.StatusOK {
+		t.Errorf("second client got %d, want %d", got, http.StatusOK)
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"estore-server/models"
+	"estore-server/service"
+
+	"github.com/appleboy/gin-jwt/v3/core"
+	"gorm.io/gorm"
+)
+
+// sessionTokenStore lets gin-jwt keep refresh tokens on the session rows, so logging
+// out or revoking a session also kills its refresh token
+type sessionTokenStore struct {
+	sessions service.SessionService
+}
+
+var _ core.TokenStore = (*sessionTokenStore)(nil)
+
+func (s *sessionTokenStore) Set(_ context.Context, token string, userData any, expiry time.Time) error {
+	session, ok := userData.(*models.Session)
+	if !ok {
+		return errors.New("refresh token data is not a session")
+	}
+	return s.sessions.AttachRefreshToken(session.ID, token, expiry)
+}
+
+func (s *sessionTokenStore) Get(_ context.Context, token string) (any, error) {
+	session, err := s.sessions.GetByRefreshToken(token)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, core.ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.User == nil {
+		return nil, core.ErrRefreshTokenNotFound
+	}
+	return session, nil
+}
+
+// Delete runs after rotation as well as on logout; a rotated token no longer matches
+// any session, which gin-jwt treats as already deleted
+func (s *sessionTokenStore) Delete(_ context.Context, token string) error {
+	err := s.sessions.RevokeByRefreshToken(token)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return core.ErrRefreshTokenNotFound
+	}
+	return err
+}
+
+func (s *sessionTokenStore) Cleanup(_ context.Context) (int, error) {
+	return s.sessions.PurgeExpired()
+}
+
+func (s *sessionTokenStore) Count(_ context.Context) (int, error) {
+	return s.sessions.CountActive()
+}
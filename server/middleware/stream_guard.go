@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"time"
+
+	"estore-server/service"
+	"estore-server/service/impl"
+
+	ginjwt "github.com/appleboy/gin-jwt/v3"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// StreamGuard repeats the checks the JWT middleware made when a long-lived stream, such as
+// a WebSocket or server-sent events, was opened. Without it a stream would outlive the
+// access token, a logout, a revoked session or a suspension.
+type StreamGuard struct {
+	sessions    service.SessionService
+	suspensions service.SuspensionService
+}
+
+func NewStreamGuard(db *gorm.DB) *StreamGuard {
+	return &StreamGuard{
+		sessions:    impl.NewSessionServiceImpl(db),
+		suspensions: impl.NewSuspensionServiceImpl(db, nil),
+	}
+}
+
+// TokenExpiry returns when the access token the stream was opened with expires
+func (g *StreamGuard) TokenExpiry(c *gin.Context) time.Time {
+	exp, _ := ginjwt.ExtractClaims(c)["exp"].(float64)
+	return time.Unix(int64(exp), 0)
+}
+
+// Check reports why the stream must be closed, or nil while its token, session and user
+// are still in good standing
+func (g *StreamGuard) Check(c *gin.Context) error {
+	if !time.Now().Before(g.TokenExpiry(c)) {
+		return ginjwt.ErrExpiredToken
+	}
+
+	claims := ginjwt.ExtractClaims(c)
+	userID, _ := claims["user_id"].(float64)
+	if _, err := g.sessions.ValidateSession(c.GetString(SessionIDKey), uint(userID), c.ClientIP()); err != nil {
+		return err
+	}
+
+	suspension, err := g.suspensions.ActiveSuspension(uint(userID))
+	if err != nil {
+		return err
+	}
+	if suspension != nil {
+		return &service.SuspendedError{Suspension: suspension}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+
+	"estore-server/dto"
+	"estore-server/models"
+	"estore-server/service/impl"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RequireVerifiedEmail rejects users who have not verified their email address when
+// REQUIRE_EMAIL_VERIFICATION is enabled, and lets every request through otherwise
+func RequireVerifiedEmail(db *gorm.DB) gin.HandlerFunc {
+	required, _ := strconv.ParseBool(os.Getenv("REQUIRE_EMAIL_VERIFICATION"))
+	if !required {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	userService := impl.NewUserServiceImpl(db, nil)
+	return func(c *gin.Context) {
+		identity, ok := c.Get(IdentityKey)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+			return
+		}
+
+		user, err := userService.GetUser(identity.(*models.User).ID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Unauthorized"))
+			return
+		}
+		if !user.IsEmailVerified() {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, "Please verify your email address first"))
+			return
+		}
+
+		c.Next()
+	}
+}
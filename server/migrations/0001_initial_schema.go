@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"estore-server/migrations/baseline"
+
+	"gorm.io/gorm"
+)
+
+// initialSchema creates the tables the server had before versioned migrations. Databases
+// that AutoMigrate set up earlier are adopted as they are: existing tables are kept and only
+// what is missing gets added.
+var initialSchema = Migration{
+	Version: 1,
+	Name:    "initial_schema",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.SetupJoinTable(&baseline.User{}, "Roles", &baseline.UserRole{}); err != nil {
+			return err
+		}
+		return tx.Migrator().AutoMigrate(baseline.Models()...)
+	},
+	Down: func(tx *gorm.DB) error {
+		// The join tables go first, as they reference both sides
+		if err := tx.Migrator().DropTable("user_roles", "role_permissions"); err != nil {
+			return err
+		}
+		return tx.Migrator().DropTable(baseline.Models()...)
+	},
+}
@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"estore-server/migrations/baseline"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// legacyAdminRole is the role that replaced the users.is_admin flag
+const legacyAdminRole = "admin"
+
+// legacyAdminUser is the users table with the is_admin flag that roles replaced
+type legacyAdminUser struct {
+	ID      uint
+	IsAdmin bool `gorm:"not null;default:false"`
+}
+
+func (legacyAdminUser) TableName() string {
+	return "users"
+}
+
+// dropUsersIsAdmin moves admins flagged by the legacy users.is_admin column to the admin role
+// and drops the column, so it cannot drift from the roles. Databases created without the
+// column are left alone. Down brings the column back, set for every holder of the admin role.
+var dropUsersIsAdmin = Migration{
+	Version: 2,
+	Name:    "drop_users_is_admin",
+	Up: func(tx *gorm.DB) error {
+		if !tx.Migrator().HasColumn(&legacyAdminUser{}, "IsAdmin") {
+			return nil
+		}
+
+		var adminIDs []uint
+		if err := tx.Model(&legacyAdminUser{}).Where("is_admin = ?", true).Pluck("id", &adminIDs).Error; err != nil {
+			return err
+		}
+		if len(adminIDs) > 0 {
+			// Roles are seeded after migrations, so the admin role may not exist yet
+			role := baseline.Role{Name: legacyAdminRole}
+			if err := tx.Where("name = ?", role.Name).FirstOrCreate(&role).Error; err != nil {
+				return err
+			}
+			assignments := make([]baseline.UserRole, 0, len(adminIDs))
+			for _, id := range adminIDs {
+				assignments = append(assignments, baseline.UserRole{UserID: id, RoleID: role.ID})
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&assignments).Error; err != nil {
+				return err
+			}
+		}
+
+		// GORM drops SQLite columns by rebuilding the table, and dropping the old users table
+		// would cascade to every row referencing it. All three databases can drop the column
+		// in place.
+		return tx.Exec("ALTER TABLE ? DROP COLUMN ?", clause.Table{Name: "users"}, clause.Column{Name: "is_admin"}).Error
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().AddColumn(&legacyAdminUser{}, "IsAdmin"); err != nil {
+			return err
+		}
+		admins := tx.Table("user_roles").Select("user_roles.user_id").
+			Joins("JOIN roles ON roles.id = user_roles.role_id").
+			Where("roles.name = ?", legacyAdminRole)
+		return tx.Model(&legacyAdminUser{}).Where("id IN (?)", admins).Update("is_admin", true).Error
+	},
+}
@@ -0,0 +1,70 @@
+package migrations
+
+import (
+	"testing"
+
+	"estore-server/migrations/baseline"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestDropUsersIsAdminKeepsAdmins(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:?_foreign_keys=on"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	// A database from before roles: the schema of migration 1 plus the is_admin flag
+	if err := initialSchema.Up(db); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	if err := db.Migrator().AddColumn(&legacyAdminUser{}, "IsAdmin"); err != nil {
+		t.Fatalf("add is_admin: %v", err)
+	}
+	users := []baseline.User{
+		{Username: "admin", Email: "admin@example.com"},
+		{Username: "user", Email: "user@example.com"},
+	}
+	if err := db.Create(&users).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&legacyAdminUser{}).Where("id = ?", users[0].ID).Update("is_admin", true).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dropUsersIsAdmin.Up(db); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	if db.Migrator().HasColumn(&legacyAdminUser{}, "IsAdmin") {
+		t.Error("is_admin is still there")
+	}
+	var adminIDs []uint
+	if err := db.Table("user_roles").Joins("JOIN roles ON roles.id = user_roles.role_id").
+		Where("roles.name = ?", legacyAdminRole).Pluck("user_roles.user_id", &adminIDs).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(adminIDs) != 1 || adminIDs[0] != users[0].ID {
+		t.Errorf("admin role holders are %v, want [%d]", adminIDs, users[0].ID)
+	}
+
+	if err := dropUsersIsAdmin.Down(db); err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	var flagged []uint
+	if err := db.Model(&legacyAdminUser{}).Where("is_admin = ?", true).Pluck("id", &flagged).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(flagged) != 1 || flagged[0] != users[0].ID {
+		t.Errorf("users flagged as admin are %v, want [%d]", flagged, users[0].ID)
+	}
+}
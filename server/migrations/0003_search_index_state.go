@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// searchIndexState is the search_index_states table as this migration creates it
+type searchIndexState struct {
+	ID         uint      `gorm:"primaryKey"`
+	Generation int64     `gorm:"not null;default:0"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
+
+func (searchIndexState) TableName() string {
+	return "search_index_states"
+}
+
+// addSearchIndexState creates the row that tells every server when to rebuild its search
+// index, so a rebuild reaches all instances rather than only the one asked
+var addSearchIndexState = Migration{
+	Version: 3,
+	Name:    "add_search_index_state",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.Migrator().CreateTable(&searchIndexState{}); err != nil {
+			return err
+		}
+		return tx.Create(&searchIndexState{ID: 1}).Error
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&searchIndexState{})
+	},
+}
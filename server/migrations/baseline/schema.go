@@ -0,0 +1,373 @@
+// Package baseline freezes the schema as it stood when versioned migrations were
+// introduced. The initial migration creates these tables, so later changes to the models
+// must come with a migration of their own rather than edits here.
+package baseline
+
+import (
+	"encoding/json"
+	"gorm.io/gorm"
+	"time"
+)
+
+type User struct {
+	ID              uint   `gorm:"primaryKey"`
+	Username        string `gorm:"not null;unique"`
+	Email           string `gorm:"not null"`
+	Phone           string
+	Address         string
+	EmailVerifiedAt *time.Time
+	RatingCount     int            `gorm:"not null;default:0"`
+	RatingSum       int            `gorm:"not null;default:0"`
+	DeletedAt       gorm.DeletedAt `gorm:"index"`
+	UserAuth        UserAuth       `gorm:"foreignKey:ID;constraint:OnDelete:CASCADE;OnUpdate:CASCADE"`
+	Roles           []Role         `gorm:"many2many:user_roles;constraint:OnDelete:CASCADE"`
+	Products        []Product      `gorm:"foreignKey:UserID"`
+}
+
+type UserAuth struct {
+	ID       uint   `gorm:"primaryKey;autoIncrement:false"`
+	Password string `gorm:"not null;size:255"`
+}
+
+type Role struct {
+	ID          uint         `gorm:"primaryKey;autoIncrement"`
+	Name        string       `gorm:"not null;size:50;uniqueIndex"`
+	Permissions []Permission `gorm:"many2many:role_permissions"`
+}
+
+type Permission struct {
+	ID   uint   `gorm:"primaryKey;autoIncrement"`
+	Name string `gorm:"not null;size:100;uniqueIndex"`
+}
+
+type UserRole struct {
+	UserID    uint      `gorm:"primaryKey"`
+	RoleID    uint      `gorm:"primaryKey"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+type Session struct {
+	ID               string    `gorm:"primaryKey;size:32"`
+	UserID           uint      `gorm:"not null;index"`
+	RefreshTokenHash string    `gorm:"size:64;index"`
+	UserAgent        string    `gorm:"size:255"`
+	IP               string    `gorm:"size:45"`
+	CreatedAt        time.Time `gorm:"autoCreateTime"`
+	LastSeenAt       time.Time `gorm:"not null"`
+	ExpiresAt        time.Time `gorm:"not null;index"`
+	RevokedAt        *time.Time
+	User             *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+type PasswordResetToken struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	UserID    uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"not null;size:64;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+type EmailVerificationToken struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	UserID    uint      `gorm:"not null;index"`
+	Email     string    `gorm:"not null"`
+	TokenHash string    `gorm:"not null;size:64;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+type Category struct {
+	ID        uint       `gorm:"primaryKey;autoIncrement"`
+	Name      string     `gorm:"not null"`
+	Slug      string     `gorm:"not null;size:100;uniqueIndex"`
+	SortOrder int        `gorm:"not null;default:0"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+	ParentID  *uint      `gorm:"index"`
+	Children  []Category `gorm:"foreignKey:ParentID"`
+}
+
+type Product struct {
+	ID            uint `gorm:"primaryKey;autoIncrement"`
+	Name          string
+	Description   string
+	Price         int            `gorm:"not null"`
+	Stock         int            `gorm:"not null;default:1"`
+	CreatedAt     time.Time      `gorm:"autoCreateTime"`
+	FavoriteCount int            `gorm:"not null;default:0"`
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
+	CategoryID    *uint          `gorm:"index"`
+	UserID        uint           `gorm:"not null"`
+	User          User           `gorm:"foreignKey:UserID;references:ID"`
+	Images        []ProductImage `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
+}
+
+type ProductImage struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement"`
+	ProductID    uint      `gorm:"not null;index"`
+	Key          string    `gorm:"not null;size:255"`
+	ThumbnailKey string    `gorm:"not null;size:255"`
+	URL          string    `gorm:"not null;size:512"`
+	ThumbnailURL string    `gorm:"not null;size:512"`
+	ContentType  string    `gorm:"not null;size:50"`
+	Size         int64     `gorm:"not null"`
+	Position     int       `gorm:"not null;default:0"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+type CartItem struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement"`
+	UserID     uint      `gorm:"not null;uniqueIndex:idx_cart_user_product"`
+	ProductID  uint      `gorm:"not null;uniqueIndex:idx_cart_user_product"`
+	Quantity   int       `gorm:"not null;default:1"`
+	PriceAtAdd int       `gorm:"not null"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
+
+type Favorite struct {
+	UserID    uint      `gorm:"primaryKey;autoIncrement:false"`
+	ProductID uint      `gorm:"primaryKey;autoIncrement:false;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	User      *User     `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Product   *Product  `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
+}
+
+type Order struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement"`
+	BuyerID     uint      `gorm:"not null;index"`
+	SellerID    uint      `gorm:"not null;index"`
+	SellerName  string    `gorm:"not null"`
+	Status      string    `gorm:"not null;size:20;index;default:pending"`
+	TotalPrice  int       `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+	PaidAt      *time.Time
+	ShippedAt   *time.Time
+	CompletedAt *time.Time
+	CancelledAt *time.Time
+	Items       []OrderItem `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+}
+
+type OrderItem struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement"`
+	OrderID     uint   `gorm:"not null;index"`
+	ProductID   uint   `gorm:"not null;index"`
+	ProductName string `gorm:"not null"`
+	UnitPrice   int    `gorm:"not null"`
+	Quantity    int    `gorm:"not null"`
+}
+
+type Payment struct {
+	ID            uint    `gorm:"primaryKey;autoIncrement"`
+	OrderID       uint    `gorm:"not null;index"`
+	PayerID       uint    `gorm:"not null;index"`
+	Provider      string  `gorm:"not null;size:32;uniqueIndex:idx_payment_intent;uniqueIndex:idx_payment_transaction"`
+	IntentID      string  `gorm:"not null;size:128;uniqueIndex:idx_payment_intent"`
+	TransactionID *string `gorm:"size:128;uniqueIndex:idx_payment_transaction"`
+	Amount        int     `gorm:"not null"`
+	Status        string  `gorm:"not null;size:20;index;default:pending"`
+	RedirectURL   string  `gorm:"size:500"`
+	QRCode        string  `gorm:"size:500"`
+	PaidAt        *time.Time
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+	Order         *Order    `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+}
+
+type Suspension struct {
+	ID         uint   `gorm:"primaryKey;autoIncrement"`
+	UserID     uint   `gorm:"not null;index"`
+	Reason     string `gorm:"not null;size:500"`
+	IssuedByID uint   `gorm:"not null"`
+	ExpiresAt  *time.Time
+	LiftedAt   *time.Time
+	LiftedByID *uint
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	User       *User     `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+type Review struct {
+	ID         uint   `gorm:"primaryKey;autoIncrement"`
+	SellerID   uint   `gorm:"not null;uniqueIndex:idx_review_seller_reviewer"`
+	ReviewerID uint   `gorm:"not null;uniqueIndex:idx_review_seller_reviewer;index"`
+	Rating     int    `gorm:"not null"`
+	Comment    string `gorm:"size:1000"`
+	Reply      string `gorm:"size:1000"`
+	RepliedAt  *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+	Seller     *User     `gorm:"foreignKey:SellerID;constraint:OnDelete:CASCADE"`
+	Reviewer   *User     `gorm:"foreignKey:ReviewerID;constraint:OnDelete:CASCADE"`
+}
+
+type Conversation struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement"`
+	ProductID     uint      `gorm:"not null;uniqueIndex:idx_conversation_product_buyer"`
+	BuyerID       uint      `gorm:"not null;uniqueIndex:idx_conversation_product_buyer;index"`
+	SellerID      uint      `gorm:"not null;index"`
+	LastMessageAt time.Time `gorm:"not null;index"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	Product       *Product  `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
+	Buyer         *User     `gorm:"foreignKey:BuyerID;constraint:OnDelete:CASCADE"`
+	Seller        *User     `gorm:"foreignKey:SellerID;constraint:OnDelete:CASCADE"`
+}
+
+type Message struct {
+	ID             uint          `gorm:"primaryKey;autoIncrement"`
+	ConversationID uint          `gorm:"not null;index:idx_message_conversation_read"`
+	SenderID       uint          `gorm:"not null"`
+	Body           string        `gorm:"not null;size:2000"`
+	ReadAt         *time.Time    `gorm:"index:idx_message_conversation_read"`
+	CreatedAt      time.Time     `gorm:"autoCreateTime"`
+	Conversation   *Conversation `gorm:"foreignKey:ConversationID;constraint:OnDelete:CASCADE"`
+}
+
+type Offer struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement"`
+	ProductID   uint      `gorm:"not null;index:idx_offer_product_buyer"`
+	BuyerID     uint      `gorm:"not null;index:idx_offer_product_buyer;index"`
+	SellerID    uint      `gorm:"not null;index"`
+	Price       int       `gorm:"not null"`
+	Status      string    `gorm:"not null;size:20;index;default:pending"`
+	ExpiresAt   time.Time `gorm:"not null;index"`
+	RespondedAt *time.Time
+	OrderID     *uint     `gorm:"index"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+	Product     *Product  `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
+	Buyer       *User     `gorm:"foreignKey:BuyerID;constraint:OnDelete:CASCADE"`
+	Seller      *User     `gorm:"foreignKey:SellerID;constraint:OnDelete:CASCADE"`
+}
+
+type Notification struct {
+	ID         uint   `gorm:"primaryKey;autoIncrement"`
+	UserID     uint   `gorm:"not null;index:idx_notification_user_read"`
+	Type       string `gorm:"not null;size:64"`
+	Message    string `gorm:"not null;size:500"`
+	TargetType string `gorm:"size:32"`
+	TargetID   uint
+	ReadAt     *time.Time `gorm:"index:idx_notification_user_read"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+	User       *User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+type Wallet struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement"`
+	UserID     *uint     `gorm:"uniqueIndex"`
+	SystemName *string   `gorm:"size:32;uniqueIndex"`
+	Balance    int       `gorm:"not null;default:0"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
+
+type LedgerTransaction struct {
+	ID        uint          `gorm:"primaryKey;autoIncrement"`
+	Kind      string        `gorm:"not null;size:20;index"`
+	Memo      string        `gorm:"size:255"`
+	ActorID   *uint         `gorm:"index"`
+	CreatedAt time.Time     `gorm:"autoCreateTime"`
+	Entries   []LedgerEntry `gorm:"foreignKey:TransactionID"`
+}
+
+type LedgerEntry struct {
+	ID            uint               `gorm:"primaryKey;autoIncrement"`
+	TransactionID uint               `gorm:"not null;index"`
+	WalletID      uint               `gorm:"not null;index"`
+	Amount        int                `gorm:"not null"`
+	BalanceAfter  int                `gorm:"not null"`
+	CreatedAt     time.Time          `gorm:"autoCreateTime"`
+	Transaction   *LedgerTransaction `gorm:"foreignKey:TransactionID"`
+	Wallet        *Wallet            `gorm:"foreignKey:WalletID"`
+}
+
+type Job struct {
+	ID          uint            `gorm:"primaryKey;autoIncrement"`
+	Type        string          `gorm:"not null;size:64;index"`
+	Payload     json.RawMessage `gorm:"type:text"`
+	Status      string          `gorm:"not null;size:20;default:pending;index:idx_job_due,priority:1"`
+	RunAt       time.Time       `gorm:"not null;index:idx_job_due,priority:2"`
+	Attempts    int             `gorm:"not null;default:0"`
+	MaxAttempts int             `gorm:"not null"`
+	LastError   string          `gorm:"type:text"`
+	LockedBy    string          `gorm:"size:128"`
+	LockedUntil *time.Time
+	ScheduleID  *uint `gorm:"index"`
+	FinishedAt  *time.Time
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+type JobSchedule struct {
+	ID        uint            `gorm:"primaryKey;autoIncrement"`
+	Name      string          `gorm:"not null;size:64;uniqueIndex"`
+	Spec      string          `gorm:"not null;size:64"`
+	Type      string          `gorm:"not null;size:64"`
+	Payload   json.RawMessage `gorm:"type:text"`
+	NextRunAt time.Time       `gorm:"not null;index"`
+	LastRunAt *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+type WebhookEndpoint struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement"`
+	URL         string    `gorm:"not null;size:500"`
+	Description string    `gorm:"size:255"`
+	Events      []string  `gorm:"serializer:json;type:text"`
+	Secret      string    `gorm:"not null;size:64"`
+	Active      bool      `gorm:"not null;default:true"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+type OutboxEvent struct {
+	ID           uint            `gorm:"primaryKey;autoIncrement"`
+	Type         string          `gorm:"not null;size:64;index"`
+	Payload      json.RawMessage `gorm:"type:text"`
+	DispatchedAt *time.Time
+	CreatedAt    time.Time `gorm:"autoCreateTime;index"`
+}
+
+type WebhookDelivery struct {
+	ID             uint   `gorm:"primaryKey;autoIncrement"`
+	EndpointID     uint   `gorm:"not null;index"`
+	EventID        uint   `gorm:"not null;index"`
+	EventType      string `gorm:"not null;size:64"`
+	Status         string `gorm:"not null;size:20;default:pending;index"`
+	Attempts       int    `gorm:"not null;default:0"`
+	ResponseStatus int
+	ResponseBody   string `gorm:"type:text"`
+	Error          string `gorm:"type:text"`
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time        `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time        `gorm:"autoUpdateTime"`
+	Endpoint       *WebhookEndpoint `gorm:"foreignKey:EndpointID;constraint:OnDelete:CASCADE"`
+	Event          *OutboxEvent     `gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE"`
+}
+
+type AuditLog struct {
+	ID         uint            `gorm:"primaryKey;autoIncrement"`
+	ActorID    *uint           `gorm:"index"`
+	Action     string          `gorm:"not null;size:64;index"`
+	TargetType string          `gorm:"not null;size:32;index:idx_audit_target"`
+	TargetID   string          `gorm:"size:64;index:idx_audit_target"`
+	Before     json.RawMessage `gorm:"type:text"`
+	After      json.RawMessage `gorm:"type:text"`
+	IP         string          `gorm:"size:45"`
+	RequestID  string          `gorm:"size:64;index"`
+	CreatedAt  time.Time       `gorm:"autoCreateTime;index"`
+}
+
+// Models lists the baseline tables in an order that satisfies their foreign keys
+func Models() []any {
+	return []any{
+		&User{}, &UserAuth{}, &Role{}, &Permission{}, &Session{}, &PasswordResetToken{},
+		&EmailVerificationToken{}, &Category{}, &Product{}, &ProductImage{}, &CartItem{},
+		&Favorite{}, &Order{}, &OrderItem{}, &Payment{}, &Suspension{}, &Review{},
+		&Conversation{}, &Message{}, &Offer{}, &Notification{}, &Wallet{},
+		&LedgerTransaction{}, &LedgerEntry{}, &Job{}, &JobSchedule{}, &WebhookEndpoint{},
+		&OutboxEvent{}, &WebhookDelivery{}, &AuditLog{},
+	}
+}
@@ -0,0 +1,186 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPending        = errors.New("database schema has unapplied migrations")
+	ErrUnknownVersion = errors.New("database schema is newer than this build")
+)
+
+// Migration is one versioned change to the schema. Up applies it and Down reverts it.
+// Each runs in a transaction together with its schema_migrations row; note that MySQL
+// commits DDL statements implicitly, so a migration failing halfway there must be
+// cleaned up by hand.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// All lists every migration in the order they apply. Add new ones at the end with the next
+// version; never edit, renumber or remove one that has shipped.
+var All = []Migration{
+	initialSchema,
+	dropUsersIsAdmin,
+	addSearchIndexState,
+}
+
+// SchemaMigration records a migration applied to the database
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null;size:255"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationStatus reports whether a migration has been applied. Known is false for versions
+// found in the database that this build does not have.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+	Known     bool
+}
+
+// Up applies every pending migration in order and returns the ones it applied. It stops at
+// the first failure; the migrations before it stay applied.
+func Up(db *gorm.DB) ([]Migration, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range All {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d_%s: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// Down reverts the most recently applied migrations, newest first, and returns the ones it
+// reverted. It refuses to touch a version this build does not know how to revert.
+func Down(db *gorm.DB, steps int) ([]Migration, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]int, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	slices.Sort(versions)
+	slices.Reverse(versions)
+
+	var done []Migration
+	for _, version := range versions[:min(steps, len(versions))] {
+		i := slices.IndexFunc(All, func(m Migration) bool { return m.Version == version })
+		if i < 0 {
+			return done, fmt.Errorf("%w: cannot revert migration %d", ErrUnknownVersion, version)
+		}
+		m := All[i]
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, m.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("revert migration %d_%s: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// Status lists every known migration in order, followed by any applied versions this build
+// does not know about
+func Status(db *gorm.DB) ([]MigrationStatus, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(All))
+	for _, m := range All {
+		status := MigrationStatus{Version: m.Version, Name: m.Name, Known: true}
+		if record, ok := applied[m.Version]; ok {
+			status.AppliedAt = &record.AppliedAt
+			delete(applied, m.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	var unknown []MigrationStatus
+	for _, record := range applied {
+		unknown = append(unknown, MigrationStatus{Version: record.Version, Name: record.Name, AppliedAt: &record.AppliedAt})
+	}
+	slices.SortFunc(unknown, func(a, b MigrationStatus) int { return a.Version - b.Version })
+	return append(statuses, unknown...), nil
+}
+
+// Check returns ErrPending if the database is missing migrations, or ErrUnknownVersion if it
+// has migrations this build does not know about
+func Check(db *gorm.DB) error {
+	statuses, err := Status(db)
+	if err != nil {
+		return err
+	}
+
+	var pending []int
+	for _, status := range statuses {
+		if !status.Known {
+			return fmt.Errorf("%w: migration %d_%s is applied", ErrUnknownVersion, status.Version, status.Name)
+		}
+		if status.AppliedAt == nil {
+			pending = append(pending, status.Version)
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: versions %v", ErrPending, pending)
+	}
+	return nil
+}
+
+// appliedVersions loads the schema_migrations table, creating it on first use
+func appliedVersions(db *gorm.DB) (map[int]SchemaMigration, error) {
+	if !db.Migrator().HasTable(&SchemaMigration{}) {
+		if err := db.Migrator().CreateTable(&SchemaMigration{}); err != nil {
+			return nil, err
+		}
+	}
+
+	var records []SchemaMigration
+	if err := db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
@@ -0,0 +1,55 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"estore-server/models"
+	"estore-server/testdb"
+
+	"gorm.io/gorm"
+)
+
+// liveModels lists every model the server reads and writes. A model added here, or a field
+// added to one of them, fails the test below until a migration creates it.
+var liveModels = []any{
+	&models.User{}, &models.UserAuth{}, &models.Role{}, &models.Permission{}, &models.UserRole{},
+	&models.Session{}, &models.PasswordResetToken{}, &models.EmailVerificationToken{},
+	&models.Category{}, &models.Product{}, &models.ProductImage{}, &models.CartItem{},
+	&models.Favorite{}, &models.Order{}, &models.OrderItem{}, &models.Payment{},
+	&models.Suspension{}, &models.Review{}, &models.Conversation{}, &models.Message{},
+	&models.Offer{}, &models.Notification{}, &models.Wallet{}, &models.LedgerTransaction{},
+	&models.LedgerEntry{}, &models.Job{}, &models.JobSchedule{}, &models.WebhookEndpoint{},
+	&models.OutboxEvent{}, &models.WebhookDelivery{}, &models.AuditLog{},
+	&models.SearchIndexState{},
+}
+
+func TestMigrationsMatchModels(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		migrator := db.Migrator()
+		for _, model := range liveModels {
+			stmt := &gorm.Statement{DB: db}
+			if err := stmt.Parse(model); err != nil {
+				t.Fatalf("parse %T: %v", model, err)
+			}
+			table := stmt.Schema.Table
+			if !migrator.HasTable(model) {
+				t.Errorf("no migration creates table %s", table)
+				continue
+			}
+			for _, field := range stmt.Schema.Fields {
+				// Relations and ignored fields have no column
+				if field.DBName == "" {
+					continue
+				}
+				if !migrator.HasColumn(model, field.DBName) {
+					t.Errorf("no migration adds column %s.%s", table, field.DBName)
+				}
+			}
+			for _, index := range stmt.Schema.ParseIndexes() {
+				if !migrator.HasIndex(model, index.Name) {
+					t.Errorf("no migration creates index %s on %s", index.Name, table)
+				}
+			}
+		}
+	})
+}
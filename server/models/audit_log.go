@@ -0,0 +1,34 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrAuditLogImmutable = errors.New("audit log entries cannot be changed")
+
+// AuditLog records who changed what. Rows are append-only: the hooks below refuse
+// updates and deletes made through GORM.
+type AuditLog struct {
+	ID         uint            `json:"id" gorm:"primaryKey;autoIncrement"`
+	ActorID    *uint           `json:"actor_id" gorm:"index"` // nil for unauthenticated requests such as password resets
+	Action     string          `json:"action" gorm:"not null;size:64;index"`
+	TargetType string          `json:"target_type" gorm:"not null;size:32;index:idx_audit_target"`
+	TargetID   string          `json:"target_id" gorm:"size:64;index:idx_audit_target"`
+	Before     json.RawMessage `json:"before" gorm:"type:text"`
+	After      json.RawMessage `json:"after" gorm:"type:text"`
+	IP         string          `json:"ip" gorm:"size:45"`
+	RequestID  string          `json:"request_id" gorm:"size:64;index"`
+	CreatedAt  time.Time       `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+func (*AuditLog) BeforeUpdate(*gorm.DB) error {
+	return ErrAuditLogImmutable
+}
+
+func (*AuditLog) BeforeDelete(*gorm.DB) error {
+	return ErrAuditLogImmutable
+}
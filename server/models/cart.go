@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// CartItem is a product placed in a user's shopping cart
+type CartItem struct {
+	ID         uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_cart_user_product"`
+	ProductID  uint      `json:"product_id" gorm:"not null;uniqueIndex:idx_cart_user_product"`
+	Quantity   int       `json:"quantity" gorm:"not null;default:1"`
+	PriceAtAdd int       `json:"price_at_add" gorm:"not null"` // Product price when the item was (last) added
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Product is loaded live when listing the cart; nil if it no longer exists
+	Product *Product `json:"product,omitempty" gorm:"-"`
+}
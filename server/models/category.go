@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Category is a node in the product category tree
+type Category struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name      string    `json:"name" gorm:"not null"`
+	Slug      string    `json:"slug" gorm:"not null;size:100;uniqueIndex"`
+	SortOrder int       `json:"sort_order" gorm:"not null;default:0"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// Self-referencing relationship; nil ParentID marks a root category
+	ParentID *uint      `json:"parent_id" gorm:"index"`
+	Children []Category `json:"children,omitempty" gorm:"foreignKey:ParentID"`
+}
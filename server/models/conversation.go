@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Conversation is a private thread between a buyer and the seller about one product.
+// There is at most one conversation per product and buyer.
+type Conversation struct {
+	ID            uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	ProductID     uint      `json:"product_id" gorm:"not null;uniqueIndex:idx_conversation_product_buyer"`
+	BuyerID       uint      `json:"buyer_id" gorm:"not null;uniqueIndex:idx_conversation_product_buyer;index"`
+	SellerID      uint      `json:"seller_id" gorm:"not null;index"`
+	LastMessageAt time.Time `json:"last_message_at" gorm:"not null;index"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	Product *Product `json:"-" gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
+	Buyer   *User    `json:"-" gorm:"foreignKey:BuyerID;constraint:OnDelete:CASCADE"`
+	Seller  *User    `json:"-" gorm:"foreignKey:SellerID;constraint:OnDelete:CASCADE"`
+}
+
+// HasParticipant reports whether the user is the buyer or the seller of the conversation
+func (c *Conversation) HasParticipant(userID uint) bool {
+	return c.BuyerID == userID || c.SellerID == userID
+}
+
+// OtherParticipant returns the ID of the participant who is not userID
+func (c *Conversation) OtherParticipant(userID uint) uint {
+	if c.BuyerID == userID {
+		return c.SellerID
+	}
+	return c.BuyerID
+}
+
+// Message is one message in a conversation; ReadAt is set once the recipient has read it
+type Message struct {
+	ID             uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	ConversationID uint       `json:"conversation_id" gorm:"not null;index:idx_message_conversation_read"`
+	SenderID       uint       `json:"sender_id" gorm:"not null"`
+	Body           string     `json:"body" gorm:"not null;size:2000"`
+	ReadAt         *time.Time `json:"read_at" gorm:"index:idx_message_conversation_read"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"autoCreateTime"`
+
+	Conversation *Conversation `json:"-" gorm:"foreignKey:ConversationID;constraint:OnDelete:CASCADE"`
+}
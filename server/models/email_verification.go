@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// EmailVerificationToken is a single-use token emailed to prove a user owns an address.
+// Email records the address it was sent to, so the token stops working if the user
+// changes their email in the meantime. Only the SHA-256 hash of the token is stored.
+type EmailVerificationToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	Email     string     `json:"email" gorm:"not null"`
+	TokenHash string     `json:"-" gorm:"not null;size:64;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
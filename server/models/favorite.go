@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Favorite is a product a user has bookmarked to their watchlist
+type Favorite struct {
+	UserID    uint      `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	ProductID uint      `json:"product_id" gorm:"primaryKey;autoIncrement:false;index"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	User    *User    `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Product *Product `json:"-" gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
+}
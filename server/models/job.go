@@ -0,0 +1,59 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JobStatus is where a background job is in its lifecycle
+type JobStatus string
+
+const (
+	// JobStatusPending jobs wait for RunAt, including failed jobs waiting to be retried
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	// JobStatusDead jobs failed every attempt and stay put until an admin retries them
+	JobStatusDead JobStatus = "dead"
+)
+
+// IsValid reports whether s is a known job status
+func (s JobStatus) IsValid() bool {
+	switch s {
+	case JobStatusPending, JobStatusRunning, JobStatusSucceeded, JobStatusDead:
+		return true
+	}
+	return false
+}
+
+// Job is one unit of deferred work. A worker claims it by moving it to running with a
+// lease in LockedUntil; a job whose lease ran out is assumed abandoned and claimed again.
+type Job struct {
+	ID          uint            `json:"id" gorm:"primaryKey;autoIncrement"`
+	Type        string          `json:"type" gorm:"not null;size:64;index"`
+	Payload     json.RawMessage `json:"payload" gorm:"type:text"`
+	Status      JobStatus       `json:"status" gorm:"not null;size:20;default:pending;index:idx_job_due,priority:1"`
+	RunAt       time.Time       `json:"run_at" gorm:"not null;index:idx_job_due,priority:2"`
+	Attempts    int             `json:"attempts" gorm:"not null;default:0"`
+	MaxAttempts int             `json:"max_attempts" gorm:"not null"`
+	LastError   string          `json:"last_error" gorm:"type:text"`
+	LockedBy    string          `json:"locked_by" gorm:"size:128"`
+	LockedUntil *time.Time      `json:"locked_until"`
+	ScheduleID  *uint           `json:"schedule_id" gorm:"index"` // Set when a recurring schedule enqueued the job
+	FinishedAt  *time.Time      `json:"finished_at"`
+	CreatedAt   time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// JobSchedule enqueues a job of Type each time its cron Spec comes due
+type JobSchedule struct {
+	ID        uint            `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name      string          `json:"name" gorm:"not null;size:64;uniqueIndex"`
+	Spec      string          `json:"spec" gorm:"not null;size:64"` // Standard five-field cron or a descriptor such as "@hourly"
+	Type      string          `json:"type" gorm:"not null;size:64"`
+	Payload   json.RawMessage `json:"payload" gorm:"type:text"`
+	NextRunAt time.Time       `json:"next_run_at" gorm:"not null;index"`
+	LastRunAt *time.Time      `json:"last_run_at"`
+	CreatedAt time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Notification types, named after what happened
+const (
+	NotificationProductRemoved     = "product.removed"
+	NotificationPasswordChanged    = "password.changed"
+	NotificationAccountSuspended   = "account.suspended"
+	NotificationAccountUnsuspended = "account.unsuspended"
+	NotificationOrderCreated       = "order.created"
+	NotificationOrderStatus        = "order.status"
+	NotificationOfferReceived      = "offer.received"
+	NotificationOfferUpdated       = "offer.updated"
+	NotificationReviewReceived     = "review.received"
+	NotificationWalletCredited     = "wallet.credited"
+)
+
+// Notification tells a user that something happened to their account or listings.
+// TargetType and TargetID point at the affected record, if any.
+type Notification struct {
+	ID         uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     uint       `json:"user_id" gorm:"not null;index:idx_notification_user_read"`
+	Type       string     `json:"type" gorm:"not null;size:64"`
+	Message    string     `json:"message" gorm:"not null;size:500"`
+	TargetType string     `json:"target_type" gorm:"size:32"`
+	TargetID   uint       `json:"target_id"`
+	ReadAt     *time.Time `json:"read_at" gorm:"index:idx_notification_user_read"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+
+	User *User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// OfferStatus is a stage in a price negotiation
+type OfferStatus string
+
+const (
+	OfferStatusPending   OfferStatus = "pending"   // Waiting for the seller to respond
+	OfferStatusCountered OfferStatus = "countered" // The seller countered; waiting for the buyer
+	OfferStatusAccepted  OfferStatus = "accepted"
+	OfferStatusRejected  OfferStatus = "rejected"
+	OfferStatusWithdrawn OfferStatus = "withdrawn"
+	OfferStatusExpired   OfferStatus = "expired"
+)
+
+// IsValid reports whether the status is one of the known offer statuses
+func (s OfferStatus) IsValid() bool {
+	switch s {
+	case OfferStatusPending, OfferStatusCountered, OfferStatusAccepted, OfferStatusRejected, OfferStatusWithdrawn, OfferStatusExpired:
+		return true
+	}
+	return false
+}
+
+// IsOpen reports whether an offer in this status is still waiting for a response
+func (s OfferStatus) IsOpen() bool {
+	return s == OfferStatusPending || s == OfferStatusCountered
+}
+
+// Offer is a buyer's proposed price for a product. The seller may accept, reject or counter;
+// a counter hands the decision back to the buyer. Once accepted, Price is the unit price the
+// buyer pays on their next order of the product, which is recorded in OrderID.
+type Offer struct {
+	ID          uint        `json:"id" gorm:"primaryKey;autoIncrement"`
+	ProductID   uint        `json:"product_id" gorm:"not null;index:idx_offer_product_buyer"`
+	BuyerID     uint        `json:"buyer_id" gorm:"not null;index:idx_offer_product_buyer;index"`
+	SellerID    uint        `json:"seller_id" gorm:"not null;index"`
+	Price       int         `json:"price" gorm:"not null"`
+	Status      OfferStatus `json:"status" gorm:"not null;size:20;index;default:pending"`
+	ExpiresAt   time.Time   `json:"expires_at" gorm:"not null;index"` // Open offers lapse after this time
+	RespondedAt *time.Time  `json:"responded_at"`
+	OrderID     *uint       `json:"order_id" gorm:"index"` // Order that used the agreed price
+	CreatedAt   time.Time   `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time   `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Product *Product `json:"-" gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
+	Buyer   *User    `json:"-" gorm:"foreignKey:BuyerID;constraint:OnDelete:CASCADE"`
+	Seller  *User    `json:"-" gorm:"foreignKey:SellerID;constraint:OnDelete:CASCADE"`
+}
+
+// AwaitingResponseFrom returns the ID of the party whose turn it is to respond to an open offer
+func (o *Offer) AwaitingResponseFrom() uint {
+	if o.Status == OfferStatusCountered {
+		return o.BuyerID
+	}
+	return o.SellerID
+}
+
+// IsExpired reports whether an open offer has run past its expiry time
+func (o *Offer) IsExpired(now time.Time) bool {
+	return o.Status.IsOpen() && !now.Before(o.ExpiresAt)
+}
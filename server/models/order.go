@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// OrderStatus is a stage in the order lifecycle
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusShipped   OrderStatus = "shipped"
+	OrderStatusCompleted OrderStatus = "completed"
+	OrderStatusCancelled OrderStatus = "cancelled"
+)
+
+// orderTransitions lists the statuses each status may move to
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending: {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:    {OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusShipped: {OrderStatusCompleted},
+}
+
+// IsValid reports whether the status is one of the known order statuses
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case OrderStatusPending, OrderStatusPaid, OrderStatusShipped, OrderStatusCompleted, OrderStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo reports whether an order may move from s to next
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Order is a purchase from a single seller; product and seller details are
+// snapshotted so later edits or deletions do not rewrite history
+type Order struct {
+	ID         uint        `json:"id" gorm:"primaryKey;autoIncrement"`
+	BuyerID    uint        `json:"buyer_id" gorm:"not null;index"`
+	SellerID   uint        `json:"seller_id" gorm:"not null;index"`
+	SellerName string      `json:"seller_name" gorm:"not null"`
+	Status     OrderStatus `json:"status" gorm:"not null;size:20;index;default:pending"`
+	TotalPrice int         `json:"total_price" gorm:"not null"`
+	CreatedAt  time.Time   `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time   `json:"updated_at" gorm:"autoUpdateTime"`
+
+	PaidAt      *time.Time `json:"paid_at"`
+	ShippedAt   *time.Time `json:"shipped_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CancelledAt *time.Time `json:"cancelled_at"`
+
+	// One-to-many relationship with OrderItem
+	Items []OrderItem `json:"items" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+}
+
+// OrderItem is a product line in an order, priced at purchase time
+type OrderItem struct {
+	ID          uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	OrderID     uint   `json:"order_id" gorm:"not null;index"`
+	ProductID   uint   `json:"product_id" gorm:"not null;index"`
+	ProductName string `json:"product_name" gorm:"not null"`
+	UnitPrice   int    `json:"unit_price" gorm:"not null"`
+	Quantity    int    `json:"quantity" gorm:"not null"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// PaymentStatus is the outcome of a payment attempt
+type PaymentStatus string
+
+const (
+	PaymentStatusPending   PaymentStatus = "pending"
+	PaymentStatusSucceeded PaymentStatus = "succeeded"
+	PaymentStatusFailed    PaymentStatus = "failed"
+	// PaymentStatusRefundDue marks money collected for an order that was no longer awaiting
+	// payment, e.g. a second checkout for an order another payment already settled
+	PaymentStatusRefundDue PaymentStatus = "refund_due"
+)
+
+// Payment is one attempt to pay for an order through a payment provider. The provider's
+// transaction ID is unique, so a settled payment is recorded exactly once.
+type Payment struct {
+	ID            uint          `json:"id" gorm:"primaryKey;autoIncrement"`
+	OrderID       uint          `json:"order_id" gorm:"not null;index"`
+	PayerID       uint          `json:"payer_id" gorm:"not null;index"`
+	Provider      string        `json:"provider" gorm:"not null;size:32;uniqueIndex:idx_payment_intent;uniqueIndex:idx_payment_transaction"`
+	IntentID      string        `json:"intent_id" gorm:"not null;size:128;uniqueIndex:idx_payment_intent"`
+	TransactionID *string       `json:"transaction_id" gorm:"size:128;uniqueIndex:idx_payment_transaction"` // Set by the provider's callback
+	Amount        int           `json:"amount" gorm:"not null"`
+	Status        PaymentStatus `json:"status" gorm:"not null;size:20;index;default:pending"`
+	RedirectURL   string        `json:"redirect_url" gorm:"size:500"`
+	QRCode        string        `json:"qr_code" gorm:"size:500"`
+	PaidAt        *time.Time    `json:"paid_at"`
+	CreatedAt     time.Time     `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time     `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Order *Order `json:"-" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+}
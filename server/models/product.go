@@ -1,15 +1,33 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Product struct {
 	ID          uint      `json:"id" gorm:"primaryKey;autoIncrement"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 	Price       int       `json:"price" gorm:"not null"`
+	Stock       int       `json:"stock" gorm:"not null;default:1"`
 	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
 
+	// FavoriteCount is maintained alongside favorites so listings need no count query
+	FavoriteCount int `json:"favorite_count" gorm:"not null;default:0"`
+
+	// DeletedAt marks the product as in the trash; GORM hides such rows from every query
+	// unless Unscoped is used
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Optional many-to-one relationship with Category
+	CategoryID *uint `json:"category_id" gorm:"index"`
+
 	// Many-to-one relationship with User
 	UserID uint `json:"user_id" gorm:"not null"`
 	User   User `json:"user" gorm:"foreignKey:UserID;references:ID"`
+
+	// One-to-many relationship with ProductImage, ordered by position when preloaded
+	Images []ProductImage `json:"images,omitempty" gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
 }
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// ProductImage is an uploaded picture in a product's gallery, ordered by Position
+type ProductImage struct {
+	ID           uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	ProductID    uint      `json:"product_id" gorm:"not null;index"`
+	Key          string    `json:"-" gorm:"not null;size:255"`
+	ThumbnailKey string    `json:"-" gorm:"not null;size:255"`
+	URL          string    `json:"url" gorm:"not null;size:512"`
+	ThumbnailURL string    `json:"thumbnail_url" gorm:"not null;size:512"`
+	ContentType  string    `json:"content_type" gorm:"not null;size:50"`
+	Size         int64     `json:"size" gorm:"not null"`
+	Position     int       `json:"position" gorm:"not null;default:0"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
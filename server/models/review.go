@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Review is a buyer's star rating of a seller. Each user may review a seller once,
+// and the seller may answer it with a single public reply.
+type Review struct {
+	ID         uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	SellerID   uint       `json:"seller_id" gorm:"not null;uniqueIndex:idx_review_seller_reviewer"`
+	ReviewerID uint       `json:"reviewer_id" gorm:"not null;uniqueIndex:idx_review_seller_reviewer;index"`
+	Rating     int        `json:"rating" gorm:"not null"` // 1 to 5 stars
+	Comment    string     `json:"comment" gorm:"size:1000"`
+	Reply      string     `json:"reply" gorm:"size:1000"`
+	RepliedAt  *time.Time `json:"replied_at"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Seller   *User `json:"-" gorm:"foreignKey:SellerID;constraint:OnDelete:CASCADE"`
+	Reviewer *User `json:"reviewer,omitempty" gorm:"foreignKey:ReviewerID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// Built-in role names
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Permissions granted through roles. Owners may always manage their own products
+// and orders; these permissions cover acting on other users' data.
+const (
+	PermProductUpdate  = "product:update"
+	PermProductDelete  = "product:delete"
+	PermOrderRead      = "order:read"
+	PermOrderUpdate    = "order:update"
+	PermUserRead       = "user:read"
+	PermUserUpdate     = "user:update"
+	PermUserDelete     = "user:delete"
+	PermUserSuspend    = "user:suspend"
+	PermRoleAssign     = "role:assign"
+	PermCategoryManage = "category:manage"
+	PermAuditRead      = "audit:read"
+	PermWalletManage   = "wallet:manage"
+	PermJobManage      = "job:manage"
+	PermWebhookManage  = "webhook:manage"
+)
+
+// DefaultRolePermissions is the role set seeded at startup
+var DefaultRolePermissions = map[string][]string{
+	RoleUser: {},
+	RoleModerator: {
+		PermProductUpdate,
+		PermProductDelete,
+		PermOrderRead,
+		PermUserRead,
+		PermUserSuspend,
+	},
+	RoleAdmin: {
+		PermProductUpdate,
+		PermProductDelete,
+		PermOrderRead,
+		PermOrderUpdate,
+		PermUserRead,
+		PermUserUpdate,
+		PermUserDelete,
+		PermUserSuspend,
+		PermRoleAssign,
+		PermCategoryManage,
+		PermAuditRead,
+		PermWalletManage,
+		PermJobManage,
+		PermWebhookManage,
+	},
+}
+
+// Role is a named set of permissions assigned to users
+type Role struct {
+	ID          uint         `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string       `json:"name" gorm:"not null;size:50;uniqueIndex"`
+	Permissions []Permission `json:"permissions" gorm:"many2many:role_permissions"`
+}
+
+// Permission is a single action that can be granted to roles, named "resource:action"
+type Permission struct {
+	ID   uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name string `json:"name" gorm:"not null;size:100;uniqueIndex"`
+}
+
+// UserRole is the join row assigning a role to a user
+type UserRole struct {
+	UserID    uint      `json:"user_id" gorm:"primaryKey"`
+	RoleID    uint      `json:"role_id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
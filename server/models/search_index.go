@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// SearchIndexState is the single row servers poll to learn that their in-memory search
+// indexes should be rebuilt. The "search rebuild" command bumps Generation.
+type SearchIndexState struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	Generation int64     `json:"generation" gorm:"not null;default:0"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
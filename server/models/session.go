@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Session is a login on one device. The access token carries the session ID and
+// the refresh token is stored hashed, so revoking the row invalidates both.
+type Session struct {
+	ID               string     `json:"id" gorm:"primaryKey;size:32"`
+	UserID           uint       `json:"user_id" gorm:"not null;index"`
+	RefreshTokenHash string     `json:"-" gorm:"size:64;index"`
+	UserAgent        string     `json:"user_agent" gorm:"size:255"`
+	IP               string     `json:"ip" gorm:"size:45"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	LastSeenAt       time.Time  `json:"last_seen_at" gorm:"not null"`
+	ExpiresAt        time.Time  `json:"expires_at" gorm:"not null;index"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+
+	User *User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// IsActive reports whether the session can still authenticate requests at now
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Suspension blocks a user from logging in and using existing tokens until it expires
+// or is lifted. Rows are kept after they end as the user's moderation history.
+type Suspension struct {
+	ID         uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     uint       `json:"user_id" gorm:"not null;index"`
+	Reason     string     `json:"reason" gorm:"not null;size:500"`
+	IssuedByID uint       `json:"issued_by_id" gorm:"not null"`
+	ExpiresAt  *time.Time `json:"expires_at"` // nil suspends until lifted by hand
+	LiftedAt   *time.Time `json:"lifted_at"`
+	LiftedByID *uint      `json:"lifted_by_id"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+
+	User *User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// IsActive reports whether the suspension is in force at now
+func (s *Suspension) IsActive(now time.Time) bool {
+	return s.LiftedAt == nil && (s.ExpiresAt == nil || now.Before(*s.ExpiresAt))
+}
@@ -1,5 +1,11 @@
 package models
 
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
 // User represents the user in the system
 type User struct {
 	ID       uint   `json:"id" gorm:"primaryKey"`
@@ -7,15 +13,60 @@ type User struct {
 	Email    string `json:"email" gorm:"not null"`
 	Phone    string `json:"phone"`
 	Address  string `json:"address"`
-	IsAdmin  bool   `json:"is_admin" gorm:"not null;default:false"`
+
+	// EmailVerifiedAt is set once the user proves they own Email; changing Email clears it
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+
+	// Seller rating aggregated from received reviews, so reading it needs no join
+	RatingCount int `json:"rating_count" gorm:"not null;default:0"`
+	RatingSum   int `json:"-" gorm:"not null;default:0"`
+
+	// DeletedAt marks the account as in the trash; the row (and its unique username)
+	// stays until the purge removes it
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// One-to-one relationship with UserAuth (shared primary key)
 	UserAuth UserAuth `json:"-" gorm:"foreignKey:ID;constraint:OnDelete:CASCADE;OnUpdate:CASCADE"`
 
+	// Many-to-many relationship with Role
+	Roles []Role `json:"roles,omitempty" gorm:"many2many:user_roles;constraint:OnDelete:CASCADE"`
+
 	// One-to-many relationship with Product
 	Products []Product `json:"products,omitempty" gorm:"foreignKey:UserID"`
 }
 
+// IsEmailVerified reports whether the user's current email address has been verified
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// AverageRating returns the mean star rating the user has received as a seller, or 0 without reviews
+func (u *User) AverageRating() float64 {
+	if u.RatingCount == 0 {
+		return 0
+	}
+	return float64(u.RatingSum) / float64(u.RatingCount)
+}
+
+// RoleNames returns the names of the user's loaded roles
+func (u *User) RoleNames() []string {
+	names := make([]string, 0, len(u.Roles))
+	for _, role := range u.Roles {
+		names = append(names, role.Name)
+	}
+	return names
+}
+
+// HasRole reports whether the user's loaded roles include name
+func (u *User) HasRole(name string) bool {
+	for _, role := range u.Roles {
+		if role.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // UserAuth stores user authentication information
 type UserAuth struct {
 	ID       uint   `json:"id" gorm:"primaryKey;autoIncrement:false"`
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrLedgerImmutable = errors.New("ledger entries cannot be changed")
+
+// SystemWalletExternal is the system wallet standing for money outside the app. Deposits
+// move money out of it and withdrawals back into it, so its balance is normally negative.
+const SystemWalletExternal = "external"
+
+// LedgerKind says why money moved
+type LedgerKind string
+
+const (
+	LedgerDeposit    LedgerKind = "deposit"
+	LedgerWithdrawal LedgerKind = "withdrawal"
+	LedgerTransfer   LedgerKind = "transfer"
+)
+
+// Wallet holds a balance for a user, or for the system when SystemName is set. Balance is
+// a running total of the wallet's ledger entries, kept so reads need no aggregate. Wallets
+// have no foreign key to users so the ledger outlives purged accounts.
+type Wallet struct {
+	ID         uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     *uint     `json:"user_id" gorm:"uniqueIndex"`
+	SystemName *string   `json:"system_name,omitempty" gorm:"size:32;uniqueIndex"`
+	Balance    int       `json:"balance" gorm:"not null;default:0"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// LedgerTransaction groups the entries of one movement of money. Its entries always sum to
+// zero: whatever one wallet gains, another loses.
+type LedgerTransaction struct {
+	ID        uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Kind      LedgerKind `json:"kind" gorm:"not null;size:20;index"`
+	Memo      string     `json:"memo" gorm:"size:255"`
+	ActorID   *uint      `json:"actor_id" gorm:"index"` // Who initiated it, e.g. an admin crediting a user
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+
+	Entries []LedgerEntry `json:"entries,omitempty" gorm:"foreignKey:TransactionID"`
+}
+
+// LedgerEntry changes one wallet's balance by Amount, positive for credits and negative
+// for debits. Like transactions, entries are append-only: the hooks below refuse updates
+// and deletes made through GORM.
+type LedgerEntry struct {
+	ID            uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	TransactionID uint      `json:"transaction_id" gorm:"not null;index"`
+	WalletID      uint      `json:"wallet_id" gorm:"not null;index"`
+	Amount        int       `json:"amount" gorm:"not null"`
+	BalanceAfter  int       `json:"balance_after" gorm:"not null"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	Transaction *LedgerTransaction `json:"-" gorm:"foreignKey:TransactionID"`
+	Wallet      *Wallet            `json:"-" gorm:"foreignKey:WalletID"`
+}
+
+func (*LedgerTransaction) BeforeUpdate(*gorm.DB) error {
+	return ErrLedgerImmutable
+}
+
+func (*LedgerTransaction) BeforeDelete(*gorm.DB) error {
+	return ErrLedgerImmutable
+}
+
+func (*LedgerEntry) BeforeUpdate(*gorm.DB) error {
+	return ErrLedgerImmutable
+}
+
+func (*LedgerEntry) BeforeDelete(*gorm.DB) error {
+	return ErrLedgerImmutable
+}
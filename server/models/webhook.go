@@ -0,0 +1,90 @@
+package models
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// Event types published to webhook endpoints
+const (
+	EventProductCreated = "product.created"
+	EventProductUpdated = "product.updated"
+	EventProductDeleted = "product.deleted"
+	EventUserCreated    = "user.created"
+	EventUserUpdated    = "user.updated"
+	EventUserDeleted    = "user.deleted"
+
+	// WebhookAllEvents subscribes an endpoint to every event type
+	WebhookAllEvents = "*"
+)
+
+// WebhookEventTypes lists the event types endpoints can subscribe to
+var WebhookEventTypes = []string{
+	EventProductCreated,
+	EventProductUpdated,
+	EventProductDeleted,
+	EventUserCreated,
+	EventUserUpdated,
+	EventUserDeleted,
+}
+
+// WebhookEndpoint is an external URL registered by an admin to receive events. Payloads
+// are signed with Secret, which is only shown when the endpoint is created.
+type WebhookEndpoint struct {
+	ID          uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	URL         string    `json:"url" gorm:"not null;size:500"`
+	Description string    `json:"description" gorm:"size:255"`
+	Events      []string  `json:"events" gorm:"serializer:json;type:text"`
+	Secret      string    `json:"-" gorm:"not null;size:64"`
+	Active      bool      `json:"active" gorm:"not null;default:true"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Subscribes reports whether the endpoint wants events of eventType
+func (e *WebhookEndpoint) Subscribes(eventType string) bool {
+	return slices.Contains(e.Events, WebhookAllEvents) || slices.Contains(e.Events, eventType)
+}
+
+// OutboxEvent is an event written in the same transaction as the change it describes,
+// so it exists exactly when the change committed. DispatchedAt is set once deliveries
+// to the subscribed endpoints have been queued.
+type OutboxEvent struct {
+	ID           uint            `json:"id" gorm:"primaryKey;autoIncrement"`
+	Type         string          `json:"type" gorm:"not null;size:64;index"`
+	Payload      json.RawMessage `json:"payload" gorm:"type:text"`
+	DispatchedAt *time.Time      `json:"dispatched_at"`
+	CreatedAt    time.Time       `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+// WebhookDeliveryStatus is the outcome of the latest attempt to deliver an event
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	// WebhookDeliveryFailed deliveries are retried with backoff until their job runs out
+	// of attempts
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery logs sending one event to one endpoint. A manual redelivery adds a new
+// row so earlier attempts stay in the log.
+type WebhookDelivery struct {
+	ID             uint                  `json:"id" gorm:"primaryKey;autoIncrement"`
+	EndpointID     uint                  `json:"endpoint_id" gorm:"not null;index"`
+	EventID        uint                  `json:"event_id" gorm:"not null;index"`
+	EventType      string                `json:"event_type" gorm:"not null;size:64"`
+	Status         WebhookDeliveryStatus `json:"status" gorm:"not null;size:20;default:pending;index"`
+	Attempts       int                   `json:"attempts" gorm:"not null;default:0"`
+	ResponseStatus int                   `json:"response_status"`
+	ResponseBody   string                `json:"response_body" gorm:"type:text"` // Truncated
+	Error          string                `json:"error" gorm:"type:text"`
+	DeliveredAt    *time.Time            `json:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time             `json:"updated_at" gorm:"autoUpdateTime"`
+
+	Endpoint *WebhookEndpoint `json:"-" gorm:"foreignKey:EndpointID;constraint:OnDelete:CASCADE"`
+	Event    *OutboxEvent     `json:"-" gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE"`
+}
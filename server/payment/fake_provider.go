@@ -0,0 +1,193 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// SignatureHeader carries "t=<unix time>,v1=<hex HMAC-SHA256 of "<t>.<body>">"
+	SignatureHeader = "X-Fakepay-Signature"
+
+	// signatureTolerance bounds how old a signed callback may be, which limits replays
+	signatureTolerance = 5 * time.Minute
+)
+
+// FakeProvider stands in for a real payment provider in demos and development. Intents
+// are kept in memory; Complete plays the payer finishing checkout and posts a signed
+// callback to the webhook URL, like a real provider would.
+type FakeProvider struct {
+	secret      []byte
+	checkoutURL string
+	callbackURL string
+	client      *http.Client
+
+	mu      sync.Mutex
+	intents map[string]*fakeIntent
+}
+
+type fakeIntent struct {
+	Intent
+	completed bool
+}
+
+var _ PaymentProvider = (*FakeProvider)(nil)
+
+// NewFakeProvider signs callbacks with secret and posts them to callbackURL. Checkout
+// links point at checkoutURL followed by the intent ID.
+func NewFakeProvider(secret, checkoutURL, callbackURL string) *FakeProvider {
+	return &FakeProvider{
+		secret:      []byte(secret),
+		checkoutURL: strings.TrimRight(checkoutURL, "/"),
+		callbackURL: callbackURL,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		intents:     make(map[string]*fakeIntent),
+	}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error) {
+	id, err := randomID("pi_fake_")
+	if err != nil {
+		return nil, err
+	}
+
+	intent := Intent{
+		ID:          id,
+		Amount:      req.Amount,
+		Reference:   req.Reference,
+		RedirectURL: p.checkoutURL + "/" + id,
+		QRCode:      fmt.Sprintf("fakepay://pay?intent=%s&amount=%d", url.QueryEscape(id), req.Amount),
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.intents[id] = &fakeIntent{Intent: intent}
+	return &intent, nil
+}
+
+// Complete settles an intent with the given outcome and delivers the signed callback.
+// It returns once the webhook has answered, failing if it did not accept the callback.
+func (p *FakeProvider) Complete(ctx context.Context, intentID string, status CallbackStatus) error {
+	p.mu.Lock()
+	intent, ok := p.intents[intentID]
+	if !ok {
+		p.mu.Unlock()
+		return ErrUnknownIntent
+	}
+	if intent.completed {
+		p.mu.Unlock()
+		return ErrIntentCompleted
+	}
+	intent.completed = true
+	p.mu.Unlock()
+
+	if err := p.deliver(ctx, intent.Intent, status); err != nil {
+		// Leave the intent open so checkout can be retried
+		p.mu.Lock()
+		intent.completed = false
+		p.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// deliver posts a signed callback reporting the intent's outcome to the webhook URL
+func (p *FakeProvider) deliver(ctx context.Context, intent Intent, status CallbackStatus) error {
+	transactionID, err := randomID("tx_fake_")
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(Callback{
+		IntentID:      intent.ID,
+		TransactionID: transactionID,
+		Reference:     intent.Reference,
+		Amount:        intent.Amount,
+		Status:        status,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, p.sign(time.Now(), body))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("deliver payment callback: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("payment callback rejected with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (p *FakeProvider) VerifyCallback(header http.Header, body []byte) (*Callback, error) {
+	var timestamp, signature string
+	for _, part := range strings.Split(header.Get(SignatureHeader), ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	signedAt := time.Unix(unix, 0)
+	if age := time.Since(signedAt); age > signatureTolerance || age < -signatureTolerance {
+		return nil, ErrInvalidSignature
+	}
+
+	expected := p.sign(signedAt, body)
+	if !hmac.Equal([]byte(expected), []byte("t="+timestamp+",v1="+signature)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var callback Callback
+	if err := json.Unmarshal(body, &callback); err != nil {
+		return nil, ErrInvalidSignature
+	}
+	return &callback, nil
+}
+
+// sign returns the signature header value for a callback body sent at signedAt
+func (p *FakeProvider) sign(signedAt time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func randomID(prefix string) (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return prefix + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,51 @@
+package payment
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestVerifyCallbackAcceptsSignedCallback(t *testing.T) {
+	provider := NewFakeProvider("secret", "http://localhost/checkout", "http://localhost/webhook")
+	body := []byte(`{"intent_id":"pi_1","transaction_id":"tx_1","amount":100,"status":"succeeded"}`)
+	header := http.Header{SignatureHeader: {provider.sign(time.Now(), body)}}
+
+	callback, err := provider.VerifyCallback(header, body)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if callback.IntentID != "pi_1" || callback.TransactionID != "tx_1" || callback.Amount != 100 || callback.Status != CallbackSucceeded {
+		t.Errorf("decoded %+v", callback)
+	}
+}
+
+func TestVerifyCallbackRejectsForgedAndStaleCallbacks(t *testing.T) {
+	provider := NewFakeProvider("secret", "http://localhost/checkout", "http://localhost/webhook")
+	forger := NewFakeProvider("guessed", "http://localhost/checkout", "http://localhost/webhook")
+	body := []byte(`{"intent_id":"pi_1","transaction_id":"tx_1","amount":100,"status":"succeeded"}`)
+	tampered := []byte(`{"intent_id":"pi_1","transaction_id":"tx_1","amount":1,"status":"succeeded"}`)
+
+	for _, tc := range []struct {
+		name      string
+		signature string
+		body      []byte
+	}{
+		{"missing signature", "", body},
+		{"wrong secret", forger.sign(time.Now(), body), body},
+		{"tampered body", provider.sign(time.Now(), body), tampered},
+		{"stale timestamp", provider.sign(time.Now().Add(-2*signatureTolerance), body), body},
+		{"future timestamp", provider.sign(time.Now().Add(2*signatureTolerance), body), body},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			header := http.Header{}
+			if tc.signature != "" {
+				header.Set(SignatureHeader, tc.signature)
+			}
+			if _, err := provider.VerifyCallback(header, tc.body); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("verify returned %v, want %v", err, ErrInvalidSignature)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid payment callback signature")
+	ErrUnknownIntent    = errors.New("unknown payment intent")
+	ErrIntentCompleted  = errors.New("payment intent is already completed")
+)
+
+// CallbackStatus is the outcome a provider reports for a payment intent
+type CallbackStatus string
+
+const (
+	CallbackSucceeded CallbackStatus = "succeeded"
+	CallbackFailed    CallbackStatus = "failed"
+)
+
+// IntentRequest describes a payment to collect
+type IntentRequest struct {
+	Reference   string // Our identifier for the payment, echoed back in callbacks
+	Amount      int
+	Description string
+}
+
+// Intent is a payment the provider is ready to collect
+type Intent struct {
+	ID          string
+	Amount      int
+	Reference   string
+	RedirectURL string // Page where the payer completes the payment
+	QRCode      string // Payload to render as a QR code for paying from a phone
+}
+
+// Callback is a verified notification from the provider about an intent's outcome
+type Callback struct {
+	IntentID      string         `json:"intent_id"`
+	TransactionID string         `json:"transaction_id"` // Unique per settled payment at the provider
+	Reference     string         `json:"reference"`
+	Amount        int            `json:"amount"`
+	Status        CallbackStatus `json:"status"`
+}
+
+// PaymentProvider creates payment intents and authenticates the webhook callbacks that
+// report their outcome. Implementations must be safe for concurrent use.
+type PaymentProvider interface {
+	// Name identifies the provider in stored payment records
+	Name() string
+	CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error)
+	// VerifyCallback checks a webhook request's signature and decodes it, returning
+	// ErrInvalidSignature for anything the provider did not send
+	VerifyCallback(header http.Header, body []byte) (*Callback, error)
+}
@@ -0,0 +1,21 @@
+package realtime
+
+// Event is a message pushed to a user's live connections, e.g. over a WebSocket
+type Event struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+// Subscription receives the events published to one user until it is closed
+type Subscription interface {
+	// Events delivers published events; it is closed when the subscription is
+	Events() <-chan Event
+	Close()
+}
+
+// Broker fans events out to every live connection of a user. Implementations must be
+// safe for concurrent use, and Publish must never block on a slow subscriber.
+type Broker interface {
+	Subscribe(userID uint) Subscription
+	Publish(userID uint, event Event)
+}
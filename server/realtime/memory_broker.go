@@ -0,0 +1,80 @@
+package realtime
+
+import "sync"
+
+// subscriptionBuffer is how many events a subscriber may fall behind before new ones are dropped
+const subscriptionBuffer = 32
+
+// MemoryBroker delivers events to subscribers in this process only. With several server
+// instances, a user connected to another instance will not see the event live and picks
+// it up through the REST endpoints instead.
+type MemoryBroker struct {
+	mu          sync.RWMutex
+	subscribers map[uint]map[*memorySubscription]struct{}
+}
+
+var _ Broker = (*MemoryBroker)(nil)
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		subscribers: make(map[uint]map[*memorySubscription]struct{}),
+	}
+}
+
+func (b *MemoryBroker) Subscribe(userID uint) Subscription {
+	sub := &memorySubscription{
+		broker: b,
+		userID: userID,
+		events: make(chan Event, subscriptionBuffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[*memorySubscription]struct{})
+	}
+	b.subscribers[userID][sub] = struct{}{}
+	return sub
+}
+
+func (b *MemoryBroker) Publish(userID uint, event Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscribers[userID] {
+		select {
+		case sub.events <- event:
+		default:
+			// The subscriber is not keeping up; it will catch up from the REST endpoints
+		}
+	}
+}
+
+func (b *MemoryBroker) unsubscribe(sub *memorySubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[sub.userID][sub]; !ok {
+		return
+	}
+	delete(b.subscribers[sub.userID], sub)
+	if len(b.subscribers[sub.userID]) == 0 {
+		delete(b.subscribers, sub.userID)
+	}
+	close(sub.events)
+}
+
+type memorySubscription struct {
+	broker *MemoryBroker
+	userID uint
+	events chan Event
+}
+
+func (s *memorySubscription) Events() <-chan Event {
+	return s.events
+}
+
+func (s *memorySubscription) Close() {
+	s.broker.unsubscribe(s)
+}
@@ -0,0 +1,31 @@
+package route
+
+import (
+	"estore-server/controller"
+	"estore-server/middleware"
+	"estore-server/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// AuditRoutesModule exposes the audit log to admins
+type AuditRoutesModule struct {
+	controller *controller.AuditController
+}
+
+func NewAuditRoutesModule(db *gorm.DB) *AuditRoutesModule {
+	return &AuditRoutesModule{
+		controller: controller.NewAuditController(db),
+	}
+}
+
+func (arm *AuditRoutesModule) RegisterPublicRoutes(group *gin.RouterGroup) {}
+
+func (arm *AuditRoutesModule) RegisterUserRoutes(group *gin.RouterGroup) {}
+
+func (arm *AuditRoutesModule) RegisterAdminRoutes(group *gin.RouterGroup) {
+	group.GET("/audit-logs", middleware.RequirePermission(models.PermAuditRead), arm.controller.ListAuditLogs)
+}
+
+var _ RouteModule = (*AuditRoutesModule)(nil)
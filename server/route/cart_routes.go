@@ -0,0 +1,33 @@
+package route
+
+import (
+	"estore-server/controller"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CartRoutesModule wires shopping cart endpoints into the router
+type CartRoutesModule struct {
+	controller *controller.CartController
+}
+
+func NewCartRoutesModule(db *gorm.DB) *CartRoutesModule {
+	return &CartRoutesModule{
+		controller: controller.NewCartController(db),
+	}
+}
+
+func (crm *CartRoutesModule) RegisterPublicRoutes(group *gin.RouterGroup) {}
+
+func (crm *CartRoutesModule) RegisterUserRoutes(group *gin.RouterGroup) {
+	group.GET("/cart", crm.controller.GetCart)
+	group.DELETE("/cart", crm.controller.ClearCart)
+	group.POST("/cart/item", crm.controller.AddItem)
+	group.PUT("/cart/item/:id", crm.controller.UpdateItem)
+	group.DELETE("/cart/item/:id", crm.controller.RemoveItem)
+}
+
+func (crm *CartRoutesModule) RegisterAdminRoutes(group *gin.RouterGroup) {}
+
+var _ RouteModule = (*CartRoutesModule)(nil)
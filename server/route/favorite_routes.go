@@ -0,0 +1,31 @@
+package route
+
+import (
+	"estore-server/controller"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// FavoriteRoutesModule wires the product watchlist endpoints into the router
+type FavoriteRoutesModule struct {
+	controller *controller.FavoriteController
+}
+
+func NewFavoriteRoutesModule(db *gorm.DB) *FavoriteRoutesModule {
+	return &FavoriteRoutesModule{
+		controller: controller.NewFavoriteController(db),
+	}
+}
+
+func (frm *FavoriteRoutesModule) RegisterPublicRoutes(group *gin.RouterGroup) {}
+
+func (frm *FavoriteRoutesModule) RegisterUserRoutes(group *gin.RouterGroup) {
+	group.GET("/user/favorites", frm.controller.ListFavorites)
+	group.POST("/product/:id/favorite", frm.controller.AddFavorite)
+	group.DELETE("/product/:id/favorite", frm.controller.RemoveFavorite)
+}
+
+func (frm *FavoriteRoutesModule) RegisterAdminRoutes(group *gin.RouterGroup) {}
+
+var _ RouteModule = (*FavoriteRoutesModule)(nil)
@@ -0,0 +1,36 @@
+package route
+
+import (
+	"estore-server/controller"
+	"estore-server/middleware"
+	"estore-server/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// JobRoutesModule exposes the background job queue to admins
+type JobRoutesModule struct {
+	controller *controller.JobController
+}
+
+func NewJobRoutesModule(db *gorm.DB) *JobRoutesModule {
+	return &JobRoutesModule{
+		controller: controller.NewJobController(db),
+	}
+}
+
+func (jrm *JobRoutesModule) RegisterPublicRoutes(group *gin.RouterGroup) {}
+
+func (jrm *JobRoutesModule) RegisterUserRoutes(group *gin.RouterGroup) {}
+
+func (jrm *JobRoutesModule) RegisterAdminRoutes(group *gin.RouterGroup) {
+	manageJobs := middleware.RequirePermission(models.PermJobManage)
+	group.GET("/jobs", manageJobs, jrm.controller.ListJobs)
+	group.GET("/jobs/stats", manageJobs, jrm.controller.GetJobStats)
+	group.GET("/jobs/schedules", manageJobs, jrm.controller.ListSchedules)
+	group.GET("/job/:id", manageJobs, jrm.controller.GetJob)
+	group.POST("/job/:id/retry", manageJobs, jrm.controller.RetryJob)
+}
+
+var _ RouteModule = (*JobRoutesModule)(nil)
@@ -0,0 +1,45 @@
+package route
+
+import (
+	"estore-server/controller"
+	"estore-server/middleware"
+	"estore-server/realtime"
+
+	ginjwt "github.com/appleboy/gin-jwt/v3"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// MessageRoutesModule wires buyer–seller messaging endpoints into the router
+type MessageRoutesModule struct {
+	controller *controller.MessageController
+	auth       *ginjwt.GinJWTMiddleware
+}
+
+func NewMessageRoutesModule(db *gorm.DB, broker realtime.Broker, auth *ginjwt.GinJWTMiddleware) *MessageRoutesModule {
+	return &MessageRoutesModule{
+		controller: controller.NewMessageController(db, broker),
+		auth:       auth,
+	}
+}
+
+func (mrm *MessageRoutesModule) RegisterPublicRoutes(group *gin.RouterGroup) {
+	// Browsers cannot set headers on a WebSocket handshake, so the access token may
+	// also come as ?token=; it is checked by the same JWT middleware as every other route.
+	// The parameter is stripped before logging, see StripQueryToken in main.
+	group.GET("/messages/ws", middleware.TokenFromQuery(), mrm.auth.MiddlewareFunc(), mrm.controller.Stream)
+}
+
+func (mrm *MessageRoutesModule) RegisterUserRoutes(group *gin.RouterGroup) {
+	group.GET("/conversations", mrm.controller.ListConversations)
+	group.POST("/conversations", mrm.controller.StartConversation)
+	group.GET("/conversations/unread", mrm.controller.UnreadCount)
+
+	group.GET("/conversation/:id/messages", mrm.controller.ListMessages)
+	group.POST("/conversation/:id/messages", mrm.controller.SendMessage)
+	group.POST("/conversation/:id/read", mrm.controller.MarkRead)
+}
+
+func (mrm *MessageRoutesModule) RegisterAdminRoutes(group *gin.RouterGroup) {}
+
+var _ RouteModule = (*MessageRoutesModule)(nil)
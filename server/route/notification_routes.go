@@ -0,0 +1,41 @@
+package route
+
+import (
+	"estore-server/controller"
+	"estore-server/middleware"
+	"estore-server/realtime"
+
+	ginjwt "github.com/appleboy/gin-jwt/v3"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// NotificationRoutesModule wires in-app notification endpoints into the router
+type NotificationRoutesModule struct {
+	controller *controller.NotificationController
+	auth       *ginjwt.GinJWTMiddleware
+}
+
+func NewNotificationRoutesModule(db *gorm.DB, broker realtime.Broker, auth *ginjwt.GinJWTMiddleware) *NotificationRoutesModule {
+	return &NotificationRoutesModule{
+		controller: controller.NewNotificationController(db, broker),
+		auth:       auth,
+	}
+}
+
+func (nrm *NotificationRoutesModule) RegisterPublicRoutes(group *gin.RouterGroup) {
+	// EventSource cannot set headers either, so the stream accepts ?token= like the WebSocket;
+	// StripQueryToken has already removed it from the URL the access log sees
+	group.GET("/notifications/stream", middleware.TokenFromQuery(), nrm.auth.MiddlewareFunc(), nrm.controller.Stream)
+}
+
+func (nrm *NotificationRoutesModule) RegisterUserRoutes(group *gin.RouterGroup) {
+	group.GET("/notifications", nrm.controller.ListNotifications)
+	group.GET("/notifications/unread", nrm.controller.UnreadCount)
+	group.POST("/notifications/read", nrm.controller.MarkAllRead)
+	group.POST("/notification/:id/read", nrm.controller.MarkRead)
+}
+
+func (nrm *NotificationRoutesModule) RegisterAdminRoutes(group *gin.RouterGroup) {}
+
+var _ RouteModule = (*NotificationRoutesModule)(nil)
@@ -0,0 +1,42 @@
+package route
+
+import (
+	"time"
+
+	"estore-server/controller"
+	"estore-server/realtime"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// OfferRoutesModule wires price offer endpoints into the router
+type OfferRoutesModule struct {
+	controller *controller.OfferController
+}
+
+func NewOfferRoutesModule(db *gorm.DB, ttl time.Duration, broker realtime.Broker) *OfferRoutesModule {
+	return &OfferRoutesModule{
+		controller: controller.NewOfferController(db, ttl, broker),
+	}
+}
+
+func (orm *OfferRoutesModule) RegisterPublicRoutes(group *gin.RouterGroup) {}
+
+func (orm *OfferRoutesModule) RegisterUserRoutes(group *gin.RouterGroup) {
+	group.POST("/product/:id/offer", orm.controller.MakeOffer)
+
+	// Buyer and seller views of the current user's offers
+	group.GET("/offers/sent", orm.controller.ListSentOffers)
+	group.GET("/offers/received", orm.controller.ListReceivedOffers)
+
+	// The party an offer is waiting on responds; the buyer may withdraw while it is open
+	group.POST("/offer/:id/accept", orm.controller.AcceptOffer)
+	group.POST("/offer/:id/reject", orm.controller.RejectOffer)
+	group.POST("/offer/:id/counter", orm.controller.CounterOffer)
+	group.POST("/offer/:id/withdraw", orm.controller.WithdrawOffer)
+}
+
+func (orm *OfferRoutesModule) RegisterAdminRoutes(group *gin.RouterGroup) {}
+
+var _ RouteModule = (*OfferRoutesModule)(nil)
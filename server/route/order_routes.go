@@ -0,0 +1,41 @@
+package route
+
+import (
+	"estore-server/controller"
+	"estore-server/middleware"
+	"estore-server/models"
+	"estore-server/realtime"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// OrderRoutesModule wires order endpoints into the router
+type OrderRoutesModule struct {
+	controller *controller.OrderController
+}
+
+func NewOrderRoutesModule(db *gorm.DB, broker realtime.Broker) *OrderRoutesModule {
+	return &OrderRoutesModule{
+		controller: controller.NewOrderController(db, broker),
+	}
+}
+
+func (orm *OrderRoutesModule) RegisterPublicRoutes(group *gin.RouterGroup) {}
+
+func (orm *OrderRoutesModule) RegisterUserRoutes(group *gin.RouterGroup) {
+	group.POST("/order", orm.controller.CreateOrder)
+	group.POST("/order/checkout", orm.controller.Checkout)
+	group.GET("/order/:id", orm.controller.GetOrder)
+	group.PUT("/order/:id/status", orm.controller.UpdateOrderStatus)
+
+	// Buyer and seller views of the current user's orders
+	group.GET("/orders/purchases", orm.controller.ListPurchases)
+	group.GET("/orders/sales", orm.controller.ListSales)
+}
+
+func (orm *OrderRoutesModule) RegisterAdminRoutes(group *gin.RouterGroup) {
+	group.GET("/orders", middleware.RequirePermission(models.PermOrderRead), orm.controller.ListAllOrders)
+}
+
+var _ RouteModule = (*OrderRoutesModule)(nil)
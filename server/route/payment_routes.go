@@ -0,0 +1,43 @@
+package route
+
+import (
+	"estore-server/controller"
+	"estore-server/payment"
+	"estore-server/realtime"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// PaymentRoutesModule wires payment endpoints and the provider webhook into the router
+type PaymentRoutesModule struct {
+	controller *controller.PaymentController
+	fake       bool
+}
+
+func NewPaymentRoutesModule(db *gorm.DB, provider payment.PaymentProvider, broker realtime.Broker) *PaymentRoutesModule {
+	_, fake := provider.(*payment.FakeProvider)
+	return &PaymentRoutesModule{
+		controller: controller.NewPaymentController(db, provider, broker),
+		fake:       fake,
+	}
+}
+
+func (prm *PaymentRoutesModule) RegisterPublicRoutes(group *gin.RouterGroup) {
+	// Called by the provider; authenticated by its signature rather than a session
+	group.POST("/payments/webhook", prm.controller.Webhook)
+
+	// The fake provider's checkout page, where intent redirect URLs point
+	if prm.fake {
+		group.POST("/payments/fake/:intent", prm.controller.FakeCheckout)
+	}
+}
+
+func (prm *PaymentRoutesModule) RegisterUserRoutes(group *gin.RouterGroup) {
+	group.POST("/order/:id/payment", prm.controller.CreatePayment)
+	group.GET("/payment/:id", prm.controller.GetPayment)
+}
+
+func (prm *PaymentRoutesModule) RegisterAdminRoutes(group *gin.RouterGroup) {}
+
+var _ RouteModule = (*PaymentRoutesModule)(nil)
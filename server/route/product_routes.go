@@ -2,32 +2,57 @@ package route
 
 import (
 	"estore-server/controller"
+	"estore-server/middleware"
+	"estore-server/models"
+	"estore-server/realtime"
+	"estore-server/search"
+	"estore-server/storage"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-// ProductRoutesModule wires product endpoints into the router
+// ProductRoutesModule wires product and category endpoints into the router
 type ProductRoutesModule struct {
-	controller *controller.ProductController
+	controller         *controller.ProductController
+	categoryController *controller.CategoryController
+	requireVerified    gin.HandlerFunc
 }
 
-func NewProductRoutesModule(db *gorm.DB) *ProductRoutesModule {
+func NewProductRoutesModule(db *gorm.DB, store storage.BlobStore, index search.SearchIndex, broker realtime.Broker) *ProductRoutesModule {
 	return &ProductRoutesModule{
-		controller: controller.NewProductController(db),
+		controller:         controller.NewProductController(db, store, index, broker),
+		categoryController: controller.NewCategoryController(db),
+		requireVerified:    middleware.RequireVerifiedEmail(db),
 	}
 }
 
-func (prm *ProductRoutesModule) RegisterPublicRoutes(group *gin.RouterGroup) {}
+func (prm *ProductRoutesModule) RegisterPublicRoutes(group *gin.RouterGroup) {
+	// Image files are public so clients can load them in <img> tags without a token
+	group.GET("/images/*key", prm.controller.ServeImage)
+}
 
 func (prm *ProductRoutesModule) RegisterUserRoutes(group *gin.RouterGroup) {
 	group.GET("/products", prm.controller.SearchProducts)
 	group.GET("/product/:id", prm.controller.GetProductByID)
-	group.POST("/product", prm.controller.CreateProduct)
+	group.POST("/product", prm.requireVerified, prm.controller.CreateProduct)
 	group.PUT("/product/:id", prm.controller.UpdateProduct)
 	group.DELETE("/product/:id", prm.controller.DeleteProduct)
+
+	// Product image gallery routes
+	group.POST("/product/:id/images", prm.controller.UploadImages)
+	group.PUT("/product/:id/images/order", prm.controller.ReorderImages)
+	group.DELETE("/product/:id/image/:imageId", prm.controller.DeleteImage)
+
+	group.GET("/categories", prm.categoryController.GetCategoryTree)
 }
 
-func (prm *ProductRoutesModule) RegisterAdminRoutes(group *gin.RouterGroup) {}
+func (prm *ProductRoutesModule) RegisterAdminRoutes(group *gin.RouterGroup) {
+	// Category management routes
+	manageCategories := middleware.RequirePermission(models.PermCategoryManage)
+	group.POST("/category", manageCategories, prm.categoryController.CreateCategory)
+	group.PUT("/category/:id", manageCategories, prm.categoryController.UpdateCategory)
+	group.DELETE("/category/:id", manageCategories, prm.categoryController.DeleteCategory)
+}
 
 var _ RouteModule = (*ProductRoutesModule)(nil)
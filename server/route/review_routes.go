@@ -0,0 +1,37 @@
+package route
+
+import (
+	"estore-server/controller"
+	"estore-server/realtime"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ReviewRoutesModule wires seller review endpoints into the router
+type ReviewRoutesModule struct {
+	controller *controller.ReviewController
+}
+
+func NewReviewRoutesModule(db *gorm.DB, broker realtime.Broker) *ReviewRoutesModule {
+	return &ReviewRoutesModule{
+		controller: controller.NewReviewController(db, broker),
+	}
+}
+
+func (rrm *ReviewRoutesModule) RegisterPublicRoutes(group *gin.RouterGroup) {}
+
+func (rrm *ReviewRoutesModule) RegisterUserRoutes(group *gin.RouterGroup) {
+	// Reviews of a seller
+	group.GET("/user/:id/reviews", rrm.controller.ListReviews)
+	group.POST("/user/:id/reviews", rrm.controller.CreateReview)
+
+	// Reviewers edit their own review; the reviewed seller may reply once
+	group.PUT("/review/:id", rrm.controller.UpdateReview)
+	group.DELETE("/review/:id", rrm.controller.DeleteReview)
+	group.POST("/review/:id/reply", rrm.controller.ReplyToReview)
+}
+
+func (rrm *ReviewRoutesModule) RegisterAdminRoutes(group *gin.RouterGroup) {}
+
+var _ RouteModule = (*ReviewRoutesModule)(nil)
@@ -0,0 +1,40 @@
+package route
+
+import (
+	"estore-server/controller"
+	"estore-server/middleware"
+	"estore-server/models"
+	"estore-server/search"
+	"estore-server/storage"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TrashRoutesModule exposes the trash of soft-deleted products and users to admins
+type TrashRoutesModule struct {
+	controller *controller.TrashController
+}
+
+func NewTrashRoutesModule(db *gorm.DB, store storage.BlobStore, index search.SearchIndex) *TrashRoutesModule {
+	return &TrashRoutesModule{
+		controller: controller.NewTrashController(db, store, index),
+	}
+}
+
+func (trm *TrashRoutesModule) RegisterPublicRoutes(group *gin.RouterGroup) {}
+
+func (trm *TrashRoutesModule) RegisterUserRoutes(group *gin.RouterGroup) {}
+
+func (trm *TrashRoutesModule) RegisterAdminRoutes(group *gin.RouterGroup) {
+	// Whoever may delete an item may also bring it back
+	deleteProducts := middleware.RequirePermission(models.PermProductDelete)
+	group.GET("/trash/products", deleteProducts, trm.controller.ListDeletedProducts)
+	group.POST("/product/:id/restore", deleteProducts, trm.controller.RestoreProduct)
+
+	deleteUsers := middleware.RequirePermission(models.PermUserDelete)
+	group.GET("/trash/users", deleteUsers, trm.controller.ListDeletedUsers)
+	group.POST("/user/:id/restore", deleteUsers, trm.controller.RestoreUser)
+}
+
+var _ RouteModule = (*TrashRoutesModule)(nil)
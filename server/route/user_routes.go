@@ -1,24 +1,51 @@
 package route
 
 import (
+	"time"
+
 	"estore-server/controller"
+	"estore-server/mail"
+	"estore-server/middleware"
+	"estore-server/models"
+	"estore-server/realtime"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+// forgotPasswordLimit is how many password reset emails one client IP may ask for per
+// forgotPasswordWindow
+const (
+	forgotPasswordLimit  = 10
+	forgotPasswordWindow = time.Hour
+)
+
 // UserRoutesModule handles user-related route registration
 type UserRoutesModule struct {
-	controller *controller.UserController
+	controller           *controller.UserController
+	sessionController    *controller.SessionController
+	roleController       *controller.RoleController
+	suspensionController *controller.SuspensionController
 }
 
-func NewUserRoutesModule(db *gorm.DB) *UserRoutesModule {
-	controller := controller.NewUserController(db)
-	return &UserRoutesModule{controller}
+func NewUserRoutesModule(db *gorm.DB, mailer mail.Mailer, broker realtime.Broker) *UserRoutesModule {
+	return &UserRoutesModule{
+		controller:           controller.NewUserController(db, mailer, broker),
+		sessionController:    controller.NewSessionController(db),
+		roleController:       controller.NewRoleController(db),
+		suspensionController: controller.NewSuspensionController(db, broker),
+	}
 }
 
 func (urm *UserRoutesModule) RegisterPublicRoutes(group *gin.RouterGroup) {
 	group.POST("/register", urm.controller.Register)
+
+	// Forgotten password recovery by email
+	group.POST("/password/forgot", middleware.RateLimit(forgotPasswordLimit, forgotPasswordWindow), urm.controller.ForgotPassword)
+	group.POST("/password/reset", urm.controller.ResetPassword)
+
+	// Verification links work without logging in
+	group.POST("/email/verify", urm.controller.VerifyEmail)
 }
 
 func (urm *UserRoutesModule) RegisterUserRoutes(group *gin.RouterGroup) {
@@ -28,14 +55,33 @@ func (urm *UserRoutesModule) RegisterUserRoutes(group *gin.RouterGroup) {
 	// Regular user updates their own information via JWT context
 	group.PUT("/user/me", urm.controller.UpdateUser)
 	group.PUT("/user/:id/password", urm.controller.UpdateUserPassword)
+	group.POST("/user/me/email/verification", urm.controller.ResendVerification)
+
+	// Sessions of the current user
+	group.GET("/user/sessions", urm.sessionController.ListSessions)
+	group.DELETE("/user/sessions", urm.sessionController.RevokeOtherSessions)
+	group.DELETE("/user/sessions/:id", urm.sessionController.RevokeSession)
 
 	group.GET("/user/:id", urm.controller.GetUser)
 }
 
 func (urm *UserRoutesModule) RegisterAdminRoutes(group *gin.RouterGroup) {
-	// User management routes (admin only)
-	group.GET("/users", urm.controller.GetAllUsers)
-	group.DELETE("/user/:id", urm.controller.DeleteUser)
+	// User management routes
+	group.GET("/users", middleware.RequirePermission(models.PermUserRead), urm.controller.GetAllUsers)
+	group.DELETE("/user/:id", middleware.RequirePermission(models.PermUserDelete), urm.controller.DeleteUser)
+
+	// Role assignment routes
+	assignRoles := middleware.RequirePermission(models.PermRoleAssign)
+	group.GET("/roles", assignRoles, urm.roleController.ListRoles)
+	group.GET("/user/:id/roles", assignRoles, urm.roleController.GetUserRoles)
+	group.POST("/user/:id/roles", assignRoles, urm.roleController.AssignRole)
+	group.DELETE("/user/:id/roles/:role", assignRoles, urm.roleController.RemoveRole)
+
+	// Suspension routes
+	suspendUsers := middleware.RequirePermission(models.PermUserSuspend)
+	group.GET("/user/:id/suspensions", suspendUsers, urm.suspensionController.ListSuspensions)
+	group.POST("/user/:id/suspension", suspendUsers, urm.suspensionController.SuspendUser)
+	group.DELETE("/user/:id/suspension", suspendUsers, urm.suspensionController.LiftSuspension)
 }
 
 var _ RouteModule = (*UserRoutesModule)(nil)
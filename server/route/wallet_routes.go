@@ -0,0 +1,40 @@
+package route
+
+import (
+	"estore-server/controller"
+	"estore-server/middleware"
+	"estore-server/models"
+	"estore-server/realtime"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// WalletRoutesModule wires wallet and ledger endpoints into the router
+type WalletRoutesModule struct {
+	controller *controller.WalletController
+}
+
+func NewWalletRoutesModule(db *gorm.DB, broker realtime.Broker) *WalletRoutesModule {
+	return &WalletRoutesModule{
+		controller: controller.NewWalletController(db, broker),
+	}
+}
+
+func (wrm *WalletRoutesModule) RegisterPublicRoutes(group *gin.RouterGroup) {}
+
+func (wrm *WalletRoutesModule) RegisterUserRoutes(group *gin.RouterGroup) {
+	group.GET("/wallet", wrm.controller.GetWallet)
+	group.GET("/wallet/statement", wrm.controller.GetStatement)
+	group.POST("/wallet/transfer", wrm.controller.Transfer)
+	group.POST("/wallet/withdraw", wrm.controller.Withdraw)
+}
+
+func (wrm *WalletRoutesModule) RegisterAdminRoutes(group *gin.RouterGroup) {
+	manageWallets := middleware.RequirePermission(models.PermWalletManage)
+	group.POST("/user/:id/wallet/deposit", manageWallets, wrm.controller.AdminDeposit)
+	group.POST("/user/:id/wallet/withdraw", manageWallets, wrm.controller.AdminWithdraw)
+	group.GET("/wallets/reconcile", manageWallets, wrm.controller.Reconcile)
+}
+
+var _ RouteModule = (*WalletRoutesModule)(nil)
@@ -0,0 +1,39 @@
+package route
+
+import (
+	"estore-server/controller"
+	"estore-server/middleware"
+	"estore-server/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// WebhookRoutesModule exposes outgoing webhook management to admins
+type WebhookRoutesModule struct {
+	controller *controller.WebhookController
+}
+
+func NewWebhookRoutesModule(db *gorm.DB) *WebhookRoutesModule {
+	return &WebhookRoutesModule{
+		controller: controller.NewWebhookController(db),
+	}
+}
+
+func (wrm *WebhookRoutesModule) RegisterPublicRoutes(group *gin.RouterGroup) {}
+
+func (wrm *WebhookRoutesModule) RegisterUserRoutes(group *gin.RouterGroup) {}
+
+func (wrm *WebhookRoutesModule) RegisterAdminRoutes(group *gin.RouterGroup) {
+	manageWebhooks := middleware.RequirePermission(models.PermWebhookManage)
+	group.GET("/webhooks", manageWebhooks, wrm.controller.ListEndpoints)
+	group.POST("/webhooks", manageWebhooks, wrm.controller.CreateEndpoint)
+	group.PUT("/webhook/:id", manageWebhooks, wrm.controller.UpdateEndpoint)
+	group.DELETE("/webhook/:id", manageWebhooks, wrm.controller.DeleteEndpoint)
+
+	// Delivery log and manual redelivery
+	group.GET("/webhook/:id/deliveries", manageWebhooks, wrm.controller.ListDeliveries)
+	group.POST("/webhook-delivery/:id/redeliver", manageWebhooks, wrm.controller.Redeliver)
+}
+
+var _ RouteModule = (*WebhookRoutesModule)(nil)
@@ -0,0 +1,28 @@
+package search
+
+// Document is the searchable text of a product
+type Document struct {
+	ID          uint
+	Name        string
+	Description string
+}
+
+// Hit is a matching document and its relevance score; higher is better
+type Hit struct {
+	ID    uint
+	Score float64
+}
+
+// SearchIndex is a full-text index over products. Implementations must be safe for concurrent use.
+type SearchIndex interface {
+	// Index adds the document, replacing any previous version with the same ID
+	Index(doc Document)
+	// Remove drops the document; removing an unknown ID is a no-op
+	Remove(id uint)
+	// Search returns up to limit documents containing every query term, best first
+	Search(query string, limit int) []Hit
+	// Rebuild atomically replaces the whole index with docs
+	Rebuild(docs []Document)
+	// Len reports the number of indexed documents
+	Len() int
+}
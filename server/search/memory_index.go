@@ -0,0 +1,197 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// BM25 parameters and per-field boosts; a match in the name counts three times a description match
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	nameBoost        = 3.0
+	descriptionBoost = 1.0
+)
+
+// fieldFreq counts a term's occurrences in each field of one document
+type fieldFreq struct {
+	name        int
+	description int
+}
+
+// docStats remembers a document's field lengths and terms so it can be scored and removed
+type docStats struct {
+	nameLen        int
+	descriptionLen int
+	terms          []string
+}
+
+// MemoryIndex is an embedded inverted index scored with BM25F
+type MemoryIndex struct {
+	mu                  sync.RWMutex
+	postings            map[string]map[uint]*fieldFreq
+	docs                map[uint]*docStats
+	totalNameLen        int
+	totalDescriptionLen int
+}
+
+var _ SearchIndex = (*MemoryIndex)(nil)
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		postings: make(map[string]map[uint]*fieldFreq),
+		docs:     make(map[uint]*docStats),
+	}
+}
+
+func (idx *MemoryIndex) Index(doc Document) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(doc.ID)
+	idx.add(doc)
+}
+
+func (idx *MemoryIndex) Remove(id uint) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(id)
+}
+
+func (idx *MemoryIndex) Rebuild(docs []Document) {
+	fresh := NewMemoryIndex()
+	for _, doc := range docs {
+		fresh.add(doc)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.postings = fresh.postings
+	idx.docs = fresh.docs
+	idx.totalNameLen = fresh.totalNameLen
+	idx.totalDescriptionLen = fresh.totalDescriptionLen
+}
+
+func (idx *MemoryIndex) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return len(idx.docs)
+}
+
+func (idx *MemoryIndex) Search(query string, limit int) []Hit {
+	terms := QueryTerms(query)
+	if len(terms) == 0 || limit <= 0 {
+		return nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	// Walk the rarest term's postings and require every other term to match too
+	lists := make([]map[uint]*fieldFreq, 0, len(terms))
+	for _, term := range terms {
+		list, ok := idx.postings[term]
+		if !ok {
+			return nil
+		}
+		lists = append(lists, list)
+	}
+	sort.Slice(lists, func(i, j int) bool { return len(lists[i]) < len(lists[j]) })
+
+	docCount := float64(len(idx.docs))
+	avgNameLen := math.Max(float64(idx.totalNameLen)/docCount, 1)
+	avgDescriptionLen := math.Max(float64(idx.totalDescriptionLen)/docCount, 1)
+
+	idfs := make([]float64, len(lists))
+	for i, list := range lists {
+		df := float64(len(list))
+		idfs[i] = math.Log(1 + (docCount-df+0.5)/(df+0.5))
+	}
+
+	var hits []Hit
+candidates:
+	for id := range lists[0] {
+		stats := idx.docs[id]
+		nameNorm := 1 - bm25B + bm25B*float64(stats.nameLen)/avgNameLen
+		descriptionNorm := 1 - bm25B + bm25B*float64(stats.descriptionLen)/avgDescriptionLen
+
+		score := 0.0
+		for i, list := range lists {
+			freq, ok := list[id]
+			if !ok {
+				continue candidates
+			}
+			tf := nameBoost*float64(freq.name)/nameNorm + descriptionBoost*float64(freq.description)/descriptionNorm
+			score += idfs[i] * tf * (bm25K1 + 1) / (tf + bm25K1)
+		}
+		hits = append(hits, Hit{ID: id, Score: score})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID > hits[j].ID
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// add indexes a document that is not currently present; callers hold the write lock
+func (idx *MemoryIndex) add(doc Document) {
+	nameTokens := Tokenize(doc.Name)
+	descriptionTokens := Tokenize(doc.Description)
+
+	stats := &docStats{nameLen: len(nameTokens), descriptionLen: len(descriptionTokens)}
+	freqs := make(map[string]*fieldFreq)
+	for _, token := range nameTokens {
+		if freqs[token] == nil {
+			freqs[token] = &fieldFreq{}
+		}
+		freqs[token].name++
+	}
+	for _, token := range descriptionTokens {
+		if freqs[token] == nil {
+			freqs[token] = &fieldFreq{}
+		}
+		freqs[token].description++
+	}
+
+	for term, freq := range freqs {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[uint]*fieldFreq)
+		}
+		idx.postings[term][doc.ID] = freq
+		stats.terms = append(stats.terms, term)
+	}
+
+	idx.docs[doc.ID] = stats
+	idx.totalNameLen += stats.nameLen
+	idx.totalDescriptionLen += stats.descriptionLen
+}
+
+// remove drops a document if present; callers hold the write lock
+func (idx *MemoryIndex) remove(id uint) {
+	stats, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+
+	for _, term := range stats.terms {
+		delete(idx.postings[term], id)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+
+	delete(idx.docs, id)
+	idx.totalNameLen -= stats.nameLen
+	idx.totalDescriptionLen -= stats.descriptionLen
+}
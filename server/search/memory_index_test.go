@@ -0,0 +1,93 @@
+package search
+
+import (
+	"testing"
+)
+
+// hitIDs lists the IDs of hits in order
+func hitIDs(hits []Hit) []uint {
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func TestSearchRequiresEveryTerm(t *testing.T) {
+	idx := NewMemoryIndex()
+	idx.Rebuild([]Document{
+		{ID: 1, Name: "二手书 数学", Description: "九成新"},
+		{ID: 2, Name: "手书信纸"},
+		{ID: 3, Name: "二手 自行车"},
+	})
+
+	// "二手书" matches as a phrase, not any document containing one of its characters
+	if got := hitIDs(idx.Search("二手书", 10)); len(got) != 1 || got[0] != 1 {
+		t.Errorf("二手书 matched %v, want [1]", got)
+	}
+	if got := hitIDs(idx.Search("二手 数学", 10)); len(got) != 1 || got[0] != 1 {
+		t.Errorf("二手 数学 matched %v, want [1]", got)
+	}
+	if got := idx.Search("钢琴", 10); len(got) != 0 {
+		t.Errorf("unknown term matched %v", hitIDs(got))
+	}
+}
+
+func TestNameMatchOutranksDescriptionMatch(t *testing.T) {
+	idx := NewMemoryIndex()
+	idx.Rebuild([]Document{
+		{ID: 1, Name: "Desk", Description: "Comes with a lamp"},
+		{ID: 2, Name: "Lamp", Description: "Fits on a desk"},
+	})
+
+	got := hitIDs(idx.Search("lamp", 10))
+	if len(got) != 2 || got[0] != 2 {
+		t.Errorf("lamp ranked %v, want the product named Lamp first", got)
+	}
+}
+
+func TestBM25FavoursRareTermsAndShortFields(t *testing.T) {
+	idx := NewMemoryIndex()
+	idx.Rebuild([]Document{
+		{ID: 1, Name: "Red lamp"},
+		{ID: 2, Name: "Red lamp with a long list of extra words in its name"},
+		{ID: 3, Name: "Red chair"},
+		{ID: 4, Name: "Red table"},
+	})
+
+	// The same match in a shorter name scores higher
+	got := idx.Search("lamp", 10)
+	if len(got) != 2 || got[0].ID != 1 || got[0].Score <= got[1].Score {
+		t.Errorf("lamp ranked %+v, want the short name first", got)
+	}
+
+	// "red" is in every document, so it says little; "lamp" decides the score
+	red := idx.Search("red", 10)
+	if len(red) != 4 || red[0].Score >= got[0].Score {
+		t.Errorf("the common term red scored %v, want below the rare term lamp at %v", red[0].Score, got[0].Score)
+	}
+}
+
+func TestIndexUpdatesIncrementally(t *testing.T) {
+	idx := NewMemoryIndex()
+	idx.Index(Document{ID: 1, Name: "Lamp"})
+	idx.Index(Document{ID: 2, Name: "Chair"})
+
+	// Reindexing replaces the old text
+	idx.Index(Document{ID: 1, Name: "Table"})
+	if got := idx.Search("lamp", 10); len(got) != 0 {
+		t.Errorf("stale text still matches: %v", hitIDs(got))
+	}
+	if got := hitIDs(idx.Search("table", 10)); len(got) != 1 || got[0] != 1 {
+		t.Errorf("table matched %v, want [1]", got)
+	}
+
+	idx.Remove(2)
+	idx.Remove(99)
+	if got := idx.Search("chair", 10); len(got) != 0 {
+		t.Errorf("removed document still matches: %v", hitIDs(got))
+	}
+	if idx.Len() != 1 {
+		t.Errorf("index holds %d documents, want 1", idx.Len())
+	}
+}
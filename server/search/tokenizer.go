@@ -0,0 +1,97 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenize splits text into index terms. Runs of letters and digits become lowercase words.
+// CJK text has no spaces, so each run is indexed as overlapping character bigrams plus the
+// single characters, which lets both one-character and multi-character queries match.
+func Tokenize(text string) []string {
+	var tokens []string
+	forEachRun(text, func(run []rune, cjk bool) {
+		if !cjk {
+			tokens = append(tokens, string(run))
+			return
+		}
+		for i := range run {
+			tokens = append(tokens, string(run[i]))
+			if i+1 < len(run) {
+				tokens = append(tokens, string(run[i:i+2]))
+			}
+		}
+	})
+	return tokens
+}
+
+// QueryTerms splits a search query into the distinct terms a document must contain.
+// CJK runs longer than one character use bigrams only, so "二手书" must match as a phrase
+// rather than any document containing "二", "手" or "书".
+func QueryTerms(query string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	add := func(term string) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+
+	forEachRun(query, func(run []rune, cjk bool) {
+		if !cjk || len(run) == 1 {
+			add(string(run))
+			return
+		}
+		for i := 0; i+1 < len(run); i++ {
+			add(string(run[i : i+2]))
+		}
+	})
+	return terms
+}
+
+// forEachRun walks normalised text and reports maximal runs of word or CJK characters
+func forEachRun(text string, fn func(run []rune, cjk bool)) {
+	var run []rune
+	runCJK := false
+	flush := func() {
+		if len(run) > 0 {
+			fn(run, runCJK)
+			run = nil
+		}
+	}
+
+	for _, r := range normalize(text) {
+		switch {
+		case isCJK(r):
+			if !runCJK {
+				flush()
+			}
+			runCJK = true
+			run = append(run, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if runCJK {
+				flush()
+			}
+			runCJK = false
+			run = append(run, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+}
+
+// normalize lowercases text and folds full-width ASCII, common in Chinese input, to half-width
+func normalize(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 0xFF01 && r <= 0xFF5E {
+			r -= 0xFEE0
+		}
+		return unicode.ToLower(r)
+	}, text)
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
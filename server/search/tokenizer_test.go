@@ -0,0 +1,39 @@
+package search
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	for _, tc := range []struct {
+		text string
+		want []string
+	}{
+		{"Vintage Lamp", []string{"vintage", "lamp"}},
+		{"二手书", []string{"二", "二手", "手", "手书", "书"}},
+		{"iPhone 15 九成新", []string{"iphone", "15", "九", "九成", "成", "成新", "新"}},
+		{"ＡＢＣ１２３", []string{"abc123"}},
+		{"台灯,lamp!", []string{"台", "台灯", "灯", "lamp"}},
+	} {
+		if got := Tokenize(tc.text); !slices.Equal(got, tc.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestQueryTerms(t *testing.T) {
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		{"二手书", []string{"二手", "手书"}},
+		{"书", []string{"书"}},
+		{"lamp LAMP 台灯", []string{"lamp", "台灯"}},
+		{"  ", nil},
+	} {
+		if got := QueryTerms(tc.query); !slices.Equal(got, tc.want) {
+			t.Errorf("QueryTerms(%q) = %q, want %q", tc.query, got, tc.want)
+		}
+	}
+}
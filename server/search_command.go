@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+
+	"estore-server/service"
+	"estore-server/service/impl"
+)
+
+const searchUsage = "usage: search rebuild"
+
+// runSearch handles "search rebuild", which makes every running server rebuild its search
+// index from the database. The indexes live in the servers' memory, so the command only
+// signals them; each picks the request up within a few seconds.
+func runSearch(db *gorm.DB, args []string) {
+	if len(args) != 1 || args[0] != "rebuild" {
+		log.Fatal(searchUsage)
+	}
+
+	generation, err := impl.NewProductServiceImpl(db, nil, nil, nil).RequestSearchRebuild(service.SystemActor("cli:search"))
+	if err != nil {
+		log.Fatal("Failed to request a search index rebuild:", err)
+	}
+	fmt.Printf("Requested search index rebuild %d; running servers rebuild their indexes within a minute\n", generation)
+}
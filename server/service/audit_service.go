@@ -0,0 +1,56 @@
+package service
+
+import (
+	"time"
+
+	"estore-server/models"
+)
+
+const (
+	DefaultAuditPageSize = 50
+	MaxAuditPageSize     = 200
+)
+
+// Actor is who a change is made on behalf of. Every mutating service call takes one and
+// records it in the audit log in the same transaction as the change.
+type Actor struct {
+	UserID    *uint // nil for anonymous requests such as password resets, and for background work
+	IP        string
+	RequestID string // The HTTP request ID, or the job that made the change
+}
+
+// UserActor acts as the given user with no request attached
+func UserActor(userID uint) Actor {
+	return Actor{UserID: &userID}
+}
+
+// SystemActor acts for background work, such as the job named by source
+func SystemActor(source string) Actor {
+	return Actor{RequestID: source}
+}
+
+// AuditFilter narrows an audit log query; zero fields are ignored
+type AuditFilter struct {
+	ActorID    *uint
+	Action     string
+	TargetType string
+	TargetID   string
+	Since      *time.Time
+	Until      *time.Time
+	// Cursor is the NextCursor of the previous page; empty starts from the newest entry
+	Cursor string
+	// Limit defaults to DefaultAuditPageSize and is capped at MaxAuditPageSize
+	Limit int
+}
+
+// AuditPage is one page of audit entries, newest first
+type AuditPage struct {
+	Logs       []models.AuditLog
+	Total      int64
+	NextCursor string // Empty on the last page
+}
+
+// AuditService keeps the append-only record of privileged and destructive actions
+type AuditService interface {
+	ListLogs(filter AuditFilter) (*AuditPage, error)
+}
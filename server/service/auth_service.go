@@ -1,8 +1,12 @@
 package service
 
-import "github.com/gin-gonic/gin"
+import (
+	"estore-server/models"
+
+	"github.com/gin-gonic/gin"
+)
 
 type AuthService interface {
 	LoginAuthenticator(c *gin.Context) (any, error)
-	RegisterUser(username, email, password string) error
-}
\ No newline at end of file
+	RegisterUser(actor Actor, username, email, password string) (*models.User, error)
+}
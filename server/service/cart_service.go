@@ -0,0 +1,21 @@
+package service
+
+import (
+	"errors"
+
+	"estore-server/models"
+)
+
+var (
+	ErrCartOwnProduct      = errors.New("cannot add your own product to the cart")
+	ErrCartInvalidQuantity = errors.New("invalid quantity")
+)
+
+// CartService manages the current user's shopping cart
+type CartService interface {
+	AddItem(actor Actor, userID, productID uint, quantity int) (*models.CartItem, error)
+	UpdateItemQuantity(actor Actor, userID, itemID uint, quantity int) (*models.CartItem, error)
+	RemoveItem(actor Actor, userID, itemID uint) error
+	ClearCart(actor Actor, userID uint) error
+	ListItems(userID uint) ([]models.CartItem, error)
+}
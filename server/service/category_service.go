@@ -0,0 +1,27 @@
+package service
+
+import (
+	"errors"
+
+	"estore-server/models"
+)
+
+var (
+	ErrCategoryInvalidSlug    = errors.New("invalid category slug")
+	ErrCategorySlugTaken      = errors.New("category slug conflict")
+	ErrCategoryParentNotFound = errors.New("parent category not found")
+	ErrCategoryCycle          = errors.New("invalid parent: category cannot be moved under itself")
+	ErrCategoryInUse          = errors.New("category still has products; a reassignment target is required")
+	ErrCategoryInvalidTarget  = errors.New("invalid reassignment target category")
+)
+
+// CategoryService manages the product category tree
+type CategoryService interface {
+	CreateCategory(actor Actor, name, slug string, parentID *uint, sortOrder int) (*models.Category, error)
+	GetCategory(categoryID uint) (*models.Category, error)
+	GetCategoryBySlug(slug string) (*models.Category, error)
+	ListCategories() ([]models.Category, error)
+	UpdateCategory(actor Actor, categoryID uint, name, slug string, parentID *uint, sortOrder int) (*models.Category, error)
+	DeleteCategory(actor Actor, categoryID uint, reassignTo *uint) error
+	DescendantIDs(categoryID uint) ([]uint, error)
+}
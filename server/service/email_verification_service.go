@@ -0,0 +1,20 @@
+package service
+
+import (
+	"errors"
+	"time"
+)
+
+// EmailVerificationTokenTTL is how long an emailed verification token stays usable
+const EmailVerificationTokenTTL = 24 * time.Hour
+
+var (
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+	ErrVerificationTokenInvalid = errors.New("verification token is invalid or has expired")
+)
+
+// EmailVerificationService confirms that users own the email address they registered with
+type EmailVerificationService interface {
+	SendVerification(userID uint) error
+	VerifyEmail(actor Actor, token string) error
+}
@@ -0,0 +1,14 @@
+package service
+
+import "estore-server/models"
+
+// FavoriteService manages users' watchlists of products
+type FavoriteService interface {
+	// AddFavorite bookmarks a product; adding a product twice is a no-op
+	AddFavorite(actor Actor, userID, productID uint) error
+	RemoveFavorite(actor Actor, userID, productID uint) error
+	// ListFavorites returns the user's favorited products, most recently added first
+	ListFavorites(userID uint) ([]models.Product, error)
+	// FavoritedProductIDs reports which of productIDs the user has favorited
+	FavoritedProductIDs(userID uint, productIDs []uint) (map[uint]bool, error)
+}
@@ -0,0 +1,143 @@
+package impl
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"estore-server/models"
+	"estore-server/service"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AuditServiceImpl appends audit entries to the database
+type AuditServiceImpl struct {
+	DB *gorm.DB
+}
+
+var _ service.AuditService = (*AuditServiceImpl)(nil)
+
+func NewAuditServiceImpl(db *gorm.DB) *AuditServiceImpl {
+	return &AuditServiceImpl{DB: db}
+}
+
+// ListLogs returns one page of entries matching the filter, newest first
+func (s *AuditServiceImpl) ListLogs(filter service.AuditFilter) (*service.AuditPage, error) {
+	ctx := context.Background()
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = service.DefaultAuditPageSize
+	}
+	limit = min(limit, service.MaxAuditPageSize)
+
+	var conds []clause.Expression
+	if filter.ActorID != nil {
+		conds = append(conds, clause.Eq{Column: "actor_id", Value: *filter.ActorID})
+	}
+	if filter.Action != "" {
+		conds = append(conds, clause.Eq{Column: "action", Value: filter.Action})
+	}
+	if filter.TargetType != "" {
+		conds = append(conds, clause.Eq{Column: "target_type", Value: filter.TargetType})
+	}
+	if filter.TargetID != "" {
+		conds = append(conds, clause.Eq{Column: "target_id", Value: filter.TargetID})
+	}
+	if filter.Since != nil {
+		conds = append(conds, clause.Gte{Column: "created_at", Value: *filter.Since})
+	}
+	if filter.Until != nil {
+		conds = append(conds, clause.Lt{Column: "created_at", Value: *filter.Until})
+	}
+	query := gorm.G[models.AuditLog](s.DB).Where(clause.And(conds...))
+
+	total, err := query.Count(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	logs, next, err := paginateByID(ctx, query, filter.Cursor, limit, func(log *models.AuditLog) uint { return log.ID })
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.AuditPage{Logs: logs, Total: total, NextCursor: next}, nil
+}
+
+// recordAudit appends an audit entry for a change made inside tx, so the entry commits or
+// rolls back with the change. Before and after are marshalled to JSON; leave before nil for
+// creations and after nil for deletions. Actions are named "<target>.<verb>".
+func recordAudit(ctx context.Context, tx *gorm.DB, actor service.Actor, action, targetType string, targetID any, before, after any) error {
+	beforeJSON, err := marshalAuditState(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := marshalAuditState(after)
+	if err != nil {
+		return err
+	}
+
+	return gorm.G[models.AuditLog](tx).Create(ctx, &models.AuditLog{
+		ActorID:    actor.UserID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   fmt.Sprint(targetID),
+		Before:     beforeJSON,
+		After:      afterJSON,
+		IP:         actor.IP,
+		RequestID:  actor.RequestID,
+	})
+}
+
+// marshalAuditState renders a snapshot as JSON, keeping nil as SQL NULL
+func marshalAuditState(state any) (json.RawMessage, error) {
+	if state == nil {
+		return nil, nil
+	}
+	return json.Marshal(state)
+}
+
+// encodeIDCursor and decodeIDCursor carry the last ID of a page for lists paged by ID, newest first
+func encodeIDCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+func decodeIDCursor(raw string) (uint, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return 0, service.ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(string(data), 10, 64)
+	if err != nil || id == 0 {
+		return 0, service.ErrInvalidCursor
+	}
+	return uint(id), nil
+}
+
+// paginateByID returns the page of rows that follows cursor, newest first, and the cursor of
+// the next page, empty on the last one. id reads a row's ID.
+func paginateByID[T any](ctx context.Context, query gorm.ChainInterface[T], cursor string, limit int, id func(*T) uint) ([]T, string, error) {
+	if cursor != "" {
+		lastID, err := decodeIDCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query = query.Where("id < ?", lastID)
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	rows, err := query.Order("id DESC").Limit(limit + 1).Find(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(rows) <= limit {
+		return rows, "", nil
+	}
+	rows = rows[:limit]
+	return rows, encodeIDCursor(id(&rows[limit-1])), nil
+}
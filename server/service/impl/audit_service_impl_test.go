@@ -0,0 +1,84 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"estore-server/models"
+	"estore-server/search"
+	"estore-server/service"
+	"estore-server/testdb"
+
+	"gorm.io/gorm"
+)
+
+func TestTrashPurgeIsAudited(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+
+		admin := createTestUser(t, db, "admin")
+		seller := createTestUser(t, db, "seller")
+		product := createTestProduct(t, db, seller, 100, 1)
+
+		if err := NewUserServiceImpl(db, nil).DeleteUser(service.UserActor(admin.ID), seller.ID); err != nil {
+			t.Fatalf("delete user: %v", err)
+		}
+		trash := NewTrashServiceImpl(db, nil, search.NewMemoryIndex())
+		if _, err := trash.Purge(ctx, service.SystemActor("job:trash.purge"), time.Now().Add(time.Hour)); err != nil {
+			t.Fatalf("purge: %v", err)
+		}
+
+		logs, err := gorm.G[models.AuditLog](db).Order("id").Find(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []struct {
+			action   string
+			targetID uint
+			actorID  *uint
+		}{
+			{"user.delete", seller.ID, &admin.ID},
+			{"product.purge", product.ID, nil},
+			{"user.purge", seller.ID, nil},
+		}
+		if len(logs) != len(want) {
+			t.Fatalf("got %d audit entries, want %d", len(logs), len(want))
+		}
+		for i, w := range want {
+			got := logs[i]
+			if got.Action != w.action || got.TargetID != fmt.Sprint(w.targetID) {
+				t.Errorf("entry %d is %s on %s, want %s on %d", i, got.Action, got.TargetID, w.action, w.targetID)
+			}
+			if (got.ActorID == nil) != (w.actorID == nil) || (w.actorID != nil && *got.ActorID != *w.actorID) {
+				t.Errorf("entry %d has actor %v, want %v", i, got.ActorID, w.actorID)
+			}
+			if w.actorID == nil && got.RequestID != "job:trash.purge" {
+				t.Errorf("entry %d has request ID %q, want the purge job", i, got.RequestID)
+			}
+		}
+	})
+}
+
+func TestFailedChangeLeavesNoAuditEntry(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+
+		seller := createTestUser(t, db, "seller")
+		buyer := createTestUser(t, db, "buyer")
+		product := createTestProduct(t, db, seller, 100, 1)
+
+		if _, err := NewOrderServiceImpl(db, nil).CreateOrder(service.UserActor(buyer.ID), buyer.ID, product.ID, 2); err == nil {
+			t.Fatal("ordering more than the stock succeeded")
+		}
+
+		count, err := gorm.G[models.AuditLog](db).Count(ctx, "id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 {
+			t.Errorf("got %d audit entries for a rolled back order", count)
+		}
+	})
+}
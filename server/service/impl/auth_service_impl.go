@@ -3,6 +3,8 @@ package impl
 import (
 	"context"
 	"errors"
+	"time"
+
 	"estore-server/dto"
 	"estore-server/models"
 	"estore-server/service"
@@ -35,7 +37,7 @@ func (s *AuthServiceImpl) LoginAuthenticator(c *gin.Context) (any, error) {
 
 	// Find user by username with associated UserAuth
 	ctx := context.Background()
-	user, err := gorm.G[models.User](s.DB).Preload("UserAuth", nil).Where("username = ?", username).First(ctx)
+	user, err := gorm.G[models.User](s.DB).Preload("UserAuth", nil).Preload("Roles", nil).Where("username = ?", username).First(ctx)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("user not found")
@@ -48,31 +50,38 @@ func (s *AuthServiceImpl) LoginAuthenticator(c *gin.Context) (any, error) {
 		return nil, errors.New("invalid username or password")
 	}
 
+	// Only tell the user about a suspension once they have proven who they are
+	suspension, err := activeSuspension(ctx, s.DB, user.ID, time.Now())
+	if err == nil {
+		return nil, &service.SuspendedError{Suspension: suspension}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
 	return &user, nil
 }
 
 // RegisterUser creates a new user with encrypted password
-func (s *AuthServiceImpl) RegisterUser(username, email, password string) error {
-	// Check if user already exists
+func (s *AuthServiceImpl) RegisterUser(actor service.Actor, username, email, password string) (*models.User, error) {
+	// Check if user already exists; trashed accounts keep their username until purged
 	ctx := context.Background()
 
-	_, err := gorm.G[models.User](s.DB).Where("username = ?", username).First(ctx)
+	_, err := gorm.G[models.User](s.DB).Scopes(withTrashed).Where("username = ?", username).First(ctx)
 	if err == nil {
-		return errors.New("username already exists")
+		return nil, errors.New("username already exists")
 	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
-		return err
+		return nil, err
 	}
 
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return errors.New("failed to hash password")
+		return nil, errors.New("failed to hash password")
 	}
 
 	user := &models.User{
 		Username: username,
 		Email:    email,
-		IsAdmin:  false,
 		UserAuth: models.UserAuth{
 			Password: string(hashedPassword),
 		},
@@ -81,12 +90,19 @@ func (s *AuthServiceImpl) RegisterUser(username, email, password string) error {
 		if err := gorm.G[models.User](tx).Create(ctx, user); err != nil {
 			return err
 		}
-		return nil
+		// Registration only ever creates regular users
+		if err := assignRole(ctx, tx, user.ID, models.RoleUser); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, actor, "user.register", "user", user.ID, nil, user); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, models.EventUserCreated, userEventData{ID: user.ID, Username: user.Username})
 	})
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return user, nil
 }
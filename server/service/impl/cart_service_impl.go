@@ -0,0 +1,196 @@
+package impl
+
+import (
+	"context"
+	"errors"
+
+	"estore-server/models"
+	"estore-server/service"
+
+	"gorm.io/gorm"
+)
+
+// CartServiceImpl stores cart items and prices them against live products
+type CartServiceImpl struct {
+	DB *gorm.DB
+}
+
+var _ service.CartService = (*CartServiceImpl)(nil)
+
+func NewCartServiceImpl(db *gorm.DB) *CartServiceImpl {
+	return &CartServiceImpl{DB: db}
+}
+
+// AddItem puts a product into the cart, or increases its quantity if it is already there
+func (s *CartServiceImpl) AddItem(actor service.Actor, userID, productID uint, quantity int) (*models.CartItem, error) {
+	if quantity <= 0 {
+		return nil, service.ErrCartInvalidQuantity
+	}
+
+	ctx := context.Background()
+	var item models.CartItem
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		product, err := gorm.G[models.Product](tx).Where("id = ?", productID).First(ctx)
+		if err != nil {
+			return err
+		}
+
+		if product.UserID == userID {
+			return service.ErrCartOwnProduct
+		}
+
+		// An accepted offer locks in the price the buyer pays
+		agreed, err := agreedOffers(ctx, tx, userID, []uint{productID})
+		if err != nil {
+			return err
+		}
+		if offer, ok := agreed[productID]; ok {
+			product.Price = offer.Price
+		}
+
+		item, err = gorm.G[models.CartItem](tx).Where("user_id = ? AND product_id = ?", userID, productID).First(ctx)
+		if err == nil {
+			if item.Quantity+quantity > product.Stock {
+				return service.ErrInsufficientStock
+			}
+
+			// Re-adding refreshes the snapshot so the user sees the current price
+			item.Quantity += quantity
+			item.PriceAtAdd = product.Price
+			if _, err := gorm.G[models.CartItem](tx).Updates(ctx, item); err != nil {
+				return err
+			}
+			return recordAudit(ctx, tx, actor, "cart.add", "cart_item", item.ID, nil, item)
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if quantity > product.Stock {
+			return service.ErrInsufficientStock
+		}
+
+		item = models.CartItem{
+			UserID:     userID,
+			ProductID:  productID,
+			Quantity:   quantity,
+			PriceAtAdd: product.Price,
+		}
+		if err := gorm.G[models.CartItem](tx).Create(ctx, &item); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "cart.add", "cart_item", item.ID, nil, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// UpdateItemQuantity sets the quantity of an item in the user's cart, which may not exceed
+// the product's stock
+func (s *CartServiceImpl) UpdateItemQuantity(actor service.Actor, userID, itemID uint, quantity int) (*models.CartItem, error) {
+	if quantity <= 0 {
+		return nil, service.ErrCartInvalidQuantity
+	}
+
+	ctx := context.Background()
+	var item models.CartItem
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		item, err = gorm.G[models.CartItem](tx).Where("id = ? AND user_id = ?", itemID, userID).First(ctx)
+		if err != nil {
+			return err
+		}
+		before := item
+
+		product, err := gorm.G[models.Product](tx).Where("id = ?", item.ProductID).First(ctx)
+		if err != nil {
+			return err
+		}
+		if quantity > product.Stock {
+			return service.ErrInsufficientStock
+		}
+
+		item.Quantity = quantity
+		if _, err := gorm.G[models.CartItem](tx).Updates(ctx, item); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "cart.update", "cart_item", itemID, before, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// RemoveItem deletes a single item from the user's cart
+func (s *CartServiceImpl) RemoveItem(actor service.Actor, userID, itemID uint) error {
+	ctx := context.Background()
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		item, err := gorm.G[models.CartItem](tx).Where("id = ? AND user_id = ?", itemID, userID).First(ctx)
+		if err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.CartItem](tx).Where("id = ?", itemID).Delete(ctx); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "cart.remove", "cart_item", itemID, item, nil)
+	})
+}
+
+// ClearCart removes every item from the user's cart
+func (s *CartServiceImpl) ClearCart(actor service.Actor, userID uint) error {
+	ctx := context.Background()
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		rows, err := gorm.G[models.CartItem](tx).Where("user_id = ?", userID).Delete(ctx)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "cart.clear", "user", userID, nil, map[string]int{"removed": rows})
+	})
+}
+
+// ListItems returns the user's cart with each item's current product and seller attached.
+// Items whose product has been deleted are returned with a nil Product.
+func (s *CartServiceImpl) ListItems(userID uint) ([]models.CartItem, error) {
+	ctx := context.Background()
+	items, err := gorm.G[models.CartItem](s.DB).Where("user_id = ?", userID).Order("created_at").Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return items, nil
+	}
+
+	productIDs := make([]uint, 0, len(items))
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	products, err := gorm.G[models.Product](s.DB).Preload("User", nil).Where("id IN ?", productIDs).Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Products the user has an accepted offer on are shown at the agreed price,
+	// which is what checkout will charge
+	agreed, err := agreedOffers(ctx, s.DB, userID, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*models.Product, len(products))
+	for i := range products {
+		if offer, ok := agreed[products[i].ID]; ok {
+			products[i].Price = offer.Price
+		}
+		byID[products[i].ID] = &products[i]
+	}
+	for i := range items {
+		items[i].Product = byID[items[i].ProductID]
+	}
+
+	return items, nil
+}
@@ -0,0 +1,36 @@
+package impl
+
+import (
+	"errors"
+	"testing"
+
+	"estore-server/service"
+	"estore-server/testdb"
+
+	"gorm.io/gorm"
+)
+
+func TestUpdateItemQuantityRespectsStock(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		seller := createTestUser(t, db, "seller")
+		buyer := createTestUser(t, db, "buyer")
+		product := createTestProduct(t, db, seller, 100, 3)
+
+		carts := NewCartServiceImpl(db)
+		item, err := carts.AddItem(service.UserActor(buyer.ID), buyer.ID, product.ID, 1)
+		if err != nil {
+			t.Fatalf("add item: %v", err)
+		}
+
+		if _, err := carts.UpdateItemQuantity(service.UserActor(buyer.ID), buyer.ID, item.ID, 4); !errors.Is(err, service.ErrInsufficientStock) {
+			t.Errorf("raising the quantity past the stock returned %v, want %v", err, service.ErrInsufficientStock)
+		}
+		updated, err := carts.UpdateItemQuantity(service.UserActor(buyer.ID), buyer.ID, item.ID, 3)
+		if err != nil {
+			t.Fatalf("update within stock: %v", err)
+		}
+		if updated.Quantity != 3 {
+			t.Errorf("quantity is %d, want 3", updated.Quantity)
+		}
+	})
+}
@@ -0,0 +1,238 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"estore-server/models"
+	"estore-server/service"
+
+	"gorm.io/gorm"
+)
+
+// slugPattern accepts lowercase ASCII words separated by single hyphens, e.g. "second-hand-books"
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// CategoryServiceImpl persists the category tree
+type CategoryServiceImpl struct {
+	DB *gorm.DB
+}
+
+var _ service.CategoryService = (*CategoryServiceImpl)(nil)
+
+func NewCategoryServiceImpl(db *gorm.DB) *CategoryServiceImpl {
+	return &CategoryServiceImpl{DB: db}
+}
+
+func (s *CategoryServiceImpl) CreateCategory(actor service.Actor, name, slug string, parentID *uint, sortOrder int) (*models.Category, error) {
+	ctx := context.Background()
+	if err := s.checkSlug(ctx, slug, 0); err != nil {
+		return nil, err
+	}
+	if err := s.checkParent(ctx, 0, parentID); err != nil {
+		return nil, err
+	}
+
+	category := &models.Category{
+		Name:      name,
+		Slug:      slug,
+		ParentID:  parentID,
+		SortOrder: sortOrder,
+	}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := gorm.G[models.Category](tx).Create(ctx, category); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "category.create", "category", category.ID, nil, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *CategoryServiceImpl) GetCategory(categoryID uint) (*models.Category, error) {
+	ctx := context.Background()
+	category, err := gorm.G[models.Category](s.DB).Where("id = ?", categoryID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (s *CategoryServiceImpl) GetCategoryBySlug(slug string) (*models.Category, error) {
+	ctx := context.Background()
+	category, err := gorm.G[models.Category](s.DB).Where("slug = ?", slug).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// ListCategories returns every category as a flat list ordered for display
+func (s *CategoryServiceImpl) ListCategories() ([]models.Category, error) {
+	ctx := context.Background()
+	return gorm.G[models.Category](s.DB).Order("sort_order, id").Find(ctx)
+}
+
+func (s *CategoryServiceImpl) UpdateCategory(actor service.Actor, categoryID uint, name, slug string, parentID *uint, sortOrder int) (*models.Category, error) {
+	ctx := context.Background()
+	if err := s.checkSlug(ctx, slug, categoryID); err != nil {
+		return nil, err
+	}
+	if err := s.checkParent(ctx, categoryID, parentID); err != nil {
+		return nil, err
+	}
+
+	var category models.Category
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		category, err = gorm.G[models.Category](tx).Where("id = ?", categoryID).First(ctx)
+		if err != nil {
+			return err
+		}
+		before := category
+
+		category.Name = name
+		category.Slug = slug
+		category.ParentID = parentID
+		category.SortOrder = sortOrder
+
+		if _, err := gorm.G[models.Category](tx).Select("name", "slug", "parent_id", "sort_order").Updates(ctx, category); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "category.update", "category", categoryID, before, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// DeleteCategory removes a category. Its children move up to its parent. If products still
+// reference it, they are moved to reassignTo, and deletion is refused when no target is given.
+// Products in the trash count too, so restoring one never brings back a deleted category.
+func (s *CategoryServiceImpl) DeleteCategory(actor service.Actor, categoryID uint, reassignTo *uint) error {
+	ctx := context.Background()
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		category, err := gorm.G[models.Category](tx).Where("id = ?", categoryID).First(ctx)
+		if err != nil {
+			return err
+		}
+
+		productCount, err := gorm.G[models.Product](tx).Scopes(withTrashed).Where("category_id = ?", categoryID).Count(ctx, "id")
+		if err != nil {
+			return err
+		}
+
+		if productCount > 0 {
+			if reassignTo == nil {
+				return service.ErrCategoryInUse
+			}
+			if *reassignTo == categoryID {
+				return service.ErrCategoryInvalidTarget
+			}
+
+			targetCount, err := gorm.G[models.Category](tx).Where("id = ?", *reassignTo).Count(ctx, "id")
+			if err != nil {
+				return err
+			}
+			if targetCount == 0 {
+				return service.ErrCategoryInvalidTarget
+			}
+
+			if _, err := gorm.G[models.Product](tx).Scopes(withTrashed).Where("category_id = ?", categoryID).Update(ctx, "category_id", *reassignTo); err != nil {
+				return err
+			}
+		}
+
+		if _, err := gorm.G[models.Category](tx).Where("parent_id = ?", categoryID).Update(ctx, "parent_id", category.ParentID); err != nil {
+			return err
+		}
+
+		if _, err := gorm.G[models.Category](tx).Where("id = ?", categoryID).Delete(ctx); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "category.delete", "category", categoryID, category, map[string]*uint{"reassigned_to": reassignTo})
+	})
+}
+
+// DescendantIDs returns the category's ID followed by the IDs of all categories beneath it
+func (s *CategoryServiceImpl) DescendantIDs(categoryID uint) ([]uint, error) {
+	categories, err := s.ListCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	childrenOf := make(map[uint][]uint)
+	for _, category := range categories {
+		if category.ID == categoryID {
+			found = true
+		}
+		if category.ParentID != nil {
+			childrenOf[*category.ParentID] = append(childrenOf[*category.ParentID], category.ID)
+		}
+	}
+	if !found {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	ids := []uint{categoryID}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, childrenOf[ids[i]]...)
+	}
+
+	return ids, nil
+}
+
+// checkSlug validates the slug format and that no other category uses it
+func (s *CategoryServiceImpl) checkSlug(ctx context.Context, slug string, categoryID uint) error {
+	if !slugPattern.MatchString(slug) {
+		return service.ErrCategoryInvalidSlug
+	}
+
+	existing, err := gorm.G[models.Category](s.DB).Where("slug = ?", slug).First(ctx)
+	if err == nil && existing.ID != categoryID {
+		return service.ErrCategorySlugTaken
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// checkParent verifies the parent exists and, when moving an existing category,
+// that the parent is neither the category itself nor one of its descendants
+func (s *CategoryServiceImpl) checkParent(ctx context.Context, categoryID uint, parentID *uint) error {
+	if parentID == nil {
+		return nil
+	}
+
+	count, err := gorm.G[models.Category](s.DB).Where("id = ?", *parentID).Count(ctx, "id")
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return service.ErrCategoryParentNotFound
+	}
+
+	if categoryID == 0 {
+		return nil
+	}
+
+	descendants, err := s.DescendantIDs(categoryID)
+	if err != nil {
+		return err
+	}
+	for _, id := range descendants {
+		if id == *parentID {
+			return service.ErrCategoryCycle
+		}
+	}
+	return nil
+}
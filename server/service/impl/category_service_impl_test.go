@@ -0,0 +1,49 @@
+package impl
+
+import (
+	"context"
+	"testing"
+
+	"estore-server/models"
+	"estore-server/search"
+	"estore-server/service"
+	"estore-server/testdb"
+
+	"gorm.io/gorm"
+)
+
+func TestDeleteCategoryReassignsTrashedProducts(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+
+		lamps := &models.Category{Name: "Lamps", Slug: "lamps"}
+		lighting := &models.Category{Name: "Lighting", Slug: "lighting"}
+		for _, category := range []*models.Category{lamps, lighting} {
+			if err := gorm.G[models.Category](db).Create(ctx, category); err != nil {
+				t.Fatal(err)
+			}
+		}
+		seller := createTestUser(t, db, "seller")
+		product := createTestProduct(t, db, seller, 100, 1)
+		if err := db.Model(product).Update("category_id", lamps.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		index := search.NewMemoryIndex()
+		products := NewProductServiceImpl(db, nil, index, nil)
+		if err := products.DeleteProduct(service.UserActor(seller.ID), product.ID); err != nil {
+			t.Fatalf("trash product: %v", err)
+		}
+		if err := NewCategoryServiceImpl(db).DeleteCategory(service.UserActor(seller.ID), lamps.ID, &lighting.ID); err != nil {
+			t.Fatalf("delete category: %v", err)
+		}
+
+		restored, err := NewTrashServiceImpl(db, nil, index).RestoreProduct(service.UserActor(seller.ID), product.ID)
+		if err != nil {
+			t.Fatalf("restore product: %v", err)
+		}
+		if restored.CategoryID == nil || *restored.CategoryID != lighting.ID {
+			t.Errorf("restored product is in category %v, want %d", restored.CategoryID, lighting.ID)
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"estore-server/mail"
+	"estore-server/models"
+	"estore-server/service"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EmailVerificationServiceImpl emails verification tokens and marks addresses verified
+type EmailVerificationServiceImpl struct {
+	DB     *gorm.DB
+	Mailer mail.Mailer
+	// VerifyURL is the page that accepts the token; when empty the email carries only the token
+	VerifyURL string
+}
+
+var _ service.EmailVerificationService = (*EmailVerificationServiceImpl)(nil)
+
+func NewEmailVerificationServiceImpl(db *gorm.DB, mailer mail.Mailer) *EmailVerificationServiceImpl {
+	return &EmailVerificationServiceImpl{
+		DB:        db,
+		Mailer:    mailer,
+		VerifyURL: os.Getenv("EMAIL_VERIFY_URL"),
+	}
+}
+
+// SendVerification emails a new verification token for the user's current address.
+// Tokens sent earlier stay valid until they expire.
+func (s *EmailVerificationServiceImpl) SendVerification(userID uint) error {
+	ctx := context.Background()
+	user, err := gorm.G[models.User](s.DB).Where("id = ?", userID).First(ctx)
+	if err != nil {
+		return err
+	}
+	if user.IsEmailVerified() {
+		return service.ErrEmailAlreadyVerified
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return err
+	}
+
+	verification := models.EmailVerificationToken{
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(service.EmailVerificationTokenTTL),
+	}
+	if err := gorm.G[models.EmailVerificationToken](s.DB).Create(ctx, &verification); err != nil {
+		return err
+	}
+
+	return s.Mailer.Send(ctx, s.verificationMessage(&user, token))
+}
+
+// VerifyEmail marks the user's address verified if the token was sent to their current email
+func (s *EmailVerificationServiceImpl) VerifyEmail(actor service.Actor, token string) error {
+	ctx := context.Background()
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		verification, err := gorm.G[models.EmailVerificationToken](tx, clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hashToken(token), time.Now()).
+			First(ctx)
+		if err != nil {
+			return service.ErrVerificationTokenInvalid
+		}
+
+		now := time.Now()
+		rows, err := gorm.G[models.User](tx).
+			Where("id = ? AND email = ?", verification.UserID, verification.Email).
+			Update(ctx, "email_verified_at", now)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return service.ErrVerificationTokenInvalid
+		}
+
+		if _, err := gorm.G[models.EmailVerificationToken](tx).
+			Where("user_id = ? AND used_at IS NULL", verification.UserID).
+			Update(ctx, "used_at", now); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "user.email.verify", "user", verification.UserID, nil, map[string]string{"email": verification.Email})
+	})
+}
+
+func (s *EmailVerificationServiceImpl) verificationMessage(user *models.User, token string) mail.Message {
+	body := fmt.Sprintf("Hi %s,\n\nPlease confirm that this is your email address. ", user.Username)
+	if s.VerifyURL != "" {
+		body += fmt.Sprintf("Open the link below to verify it:\n\n%s?token=%s\n", s.VerifyURL, url.QueryEscape(token))
+	} else {
+		body += fmt.Sprintf("Use this verification token to verify it:\n\n%s\n", token)
+	}
+	body += fmt.Sprintf("\nThe token expires in %d hours. If you did not create an estore account, "+
+		"you can ignore this email.\n", int(service.EmailVerificationTokenTTL.Hours()))
+
+	return mail.Message{
+		To:      user.Email,
+		Subject: "Verify your estore email address",
+		Body:    body,
+	}
+}
@@ -0,0 +1,117 @@
+package impl
+
+import (
+	"context"
+
+	"estore-server/models"
+	"estore-server/service"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FavoriteServiceImpl stores favorites and keeps each product's favorite_count in step
+type FavoriteServiceImpl struct {
+	DB *gorm.DB
+}
+
+var _ service.FavoriteService = (*FavoriteServiceImpl)(nil)
+
+func NewFavoriteServiceImpl(db *gorm.DB) *FavoriteServiceImpl {
+	return &FavoriteServiceImpl{DB: db}
+}
+
+// AddFavorite bookmarks a product for the user; adding it again changes nothing
+func (s *FavoriteServiceImpl) AddFavorite(actor service.Actor, userID, productID uint) error {
+	ctx := context.Background()
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		// Locking the product serialises favorites of it, which keeps the count exact
+		if _, err := gorm.G[models.Product](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", productID).First(ctx); err != nil {
+			return err
+		}
+
+		count, err := gorm.G[models.Favorite](tx).Where("user_id = ? AND product_id = ?", userID, productID).Count(ctx, "product_id")
+		if err != nil || count > 0 {
+			return err
+		}
+
+		if err := gorm.G[models.Favorite](tx).Create(ctx, &models.Favorite{UserID: userID, ProductID: productID}); err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.Product](tx).Where("id = ?", productID).Update(ctx, "favorite_count", gorm.Expr("favorite_count + 1")); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "favorite.add", "product", productID, nil, nil)
+	})
+}
+
+// RemoveFavorite takes a product off the user's watchlist
+func (s *FavoriteServiceImpl) RemoveFavorite(actor service.Actor, userID, productID uint) error {
+	ctx := context.Background()
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		rows, err := gorm.G[models.Favorite](tx).Where("user_id = ? AND product_id = ?", userID, productID).Delete(ctx)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if _, err := gorm.G[models.Product](tx).Where("id = ?", productID).Update(ctx, "favorite_count", gorm.Expr("favorite_count - 1")); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "favorite.remove", "product", productID, nil, nil)
+	})
+}
+
+// ListFavorites returns the user's favorited products with seller and gallery, most recently
+// added first. Products in the trash are left out.
+func (s *FavoriteServiceImpl) ListFavorites(userID uint) ([]models.Product, error) {
+	ctx := context.Background()
+	favorites, err := gorm.G[models.Favorite](s.DB).Where("user_id = ?", userID).Order("created_at DESC").Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(favorites) == 0 {
+		return []models.Product{}, nil
+	}
+
+	productIDs := make([]uint, 0, len(favorites))
+	for _, favorite := range favorites {
+		productIDs = append(productIDs, favorite.ProductID)
+	}
+
+	products, err := gorm.G[models.Product](s.DB).Preload("User", nil).Preload("Images", preloadImages).Where("id IN ?", productIDs).Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*models.Product, len(products))
+	for i := range products {
+		byID[products[i].ID] = &products[i]
+	}
+	ordered := make([]models.Product, 0, len(products))
+	for _, id := range productIDs {
+		if product, ok := byID[id]; ok {
+			ordered = append(ordered, *product)
+		}
+	}
+	return ordered, nil
+}
+
+// FavoritedProductIDs reports which of productIDs the user has favorited
+func (s *FavoriteServiceImpl) FavoritedProductIDs(userID uint, productIDs []uint) (map[uint]bool, error) {
+	favorited := make(map[uint]bool, len(productIDs))
+	if len(productIDs) == 0 {
+		return favorited, nil
+	}
+
+	favorites, err := gorm.G[models.Favorite](s.DB).Where("user_id = ? AND product_id IN ?", userID, productIDs).Find(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	for _, favorite := range favorites {
+		favorited[favorite.ProductID] = true
+	}
+	return favorited, nil
+}
@@ -0,0 +1,142 @@
+package impl
+
+import (
+	"context"
+	"time"
+
+	"estore-server/models"
+	"estore-server/service"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JobServiceImpl reads and repairs the jobs table the job runner works from
+type JobServiceImpl struct {
+	DB *gorm.DB
+}
+
+var _ service.JobService = (*JobServiceImpl)(nil)
+
+func NewJobServiceImpl(db *gorm.DB) *JobServiceImpl {
+	return &JobServiceImpl{DB: db}
+}
+
+// ListJobs returns one page of jobs matching the filter, newest first
+func (s *JobServiceImpl) ListJobs(filter service.JobFilter) (*service.JobPage, error) {
+	ctx := context.Background()
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = service.DefaultJobPageSize
+	}
+	limit = min(limit, service.MaxJobPageSize)
+
+	var conds []clause.Expression
+	if filter.Status != "" {
+		conds = append(conds, clause.Eq{Column: "status", Value: filter.Status})
+	}
+	if filter.Type != "" {
+		conds = append(conds, clause.Eq{Column: "type", Value: filter.Type})
+	}
+	query := gorm.G[models.Job](s.DB).Where(clause.And(conds...))
+
+	total, err := query.Count(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, next, err := paginateByID(ctx, query, filter.Cursor, limit, func(job *models.Job) uint { return job.ID })
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.JobPage{Jobs: jobs, Total: total, NextCursor: next}, nil
+}
+
+func (s *JobServiceImpl) GetJob(jobID uint) (*models.Job, error) {
+	job, err := gorm.G[models.Job](s.DB).Where("id = ?", jobID).First(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *JobServiceImpl) CountByStatus() (map[models.JobStatus]int64, error) {
+	var rows []struct {
+		Status models.JobStatus
+		Count  int64
+	}
+	if err := s.DB.Model(&models.Job{}).Select("status, COUNT(*) AS count").Group("status").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := map[models.JobStatus]int64{
+		models.JobStatusPending:   0,
+		models.JobStatusRunning:   0,
+		models.JobStatusSucceeded: 0,
+		models.JobStatusDead:      0,
+	}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (s *JobServiceImpl) RetryJob(actor service.Actor, jobID uint) (*models.Job, error) {
+	ctx := context.Background()
+	var job models.Job
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		before, err := gorm.G[models.Job](tx).Where("id = ?", jobID).First(ctx)
+		if err != nil {
+			return err
+		}
+		if before.Status != models.JobStatusDead {
+			return service.ErrJobNotDead
+		}
+
+		// Conditional on the job still being dead, so two admins retrying at once queue it once
+		rows, err := gorm.G[models.Job](tx).Where("id = ? AND status = ?", jobID, models.JobStatusDead).Set(clause.Assignments(map[string]any{
+			"status":      models.JobStatusPending,
+			"attempts":    0,
+			"run_at":      time.Now(),
+			"finished_at": nil,
+		})).Update(ctx)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return service.ErrJobNotDead
+		}
+
+		job, err = gorm.G[models.Job](tx).Where("id = ?", jobID).First(ctx)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "job.retry", "job", jobID, before, job)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *JobServiceImpl) ListSchedules() ([]models.JobSchedule, error) {
+	return gorm.G[models.JobSchedule](s.DB).Order("name").Find(context.Background())
+}
+
+func (s *JobServiceImpl) PruneJobs(ctx context.Context, actor service.Actor, finishedBefore time.Time) (int64, error) {
+	var pruned int
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		pruned, err = gorm.G[models.Job](tx).Where("status = ? AND finished_at < ?", models.JobStatusSucceeded, finishedBefore).Delete(ctx)
+		if err != nil || pruned == 0 {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "job.prune", "job", "succeeded", nil, map[string]any{"deleted": pruned, "finished_before": finishedBefore})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(pruned), nil
+}
@@ -0,0 +1,51 @@
+package impl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"estore-server/models"
+	"estore-server/service"
+	"estore-server/testdb"
+
+	"gorm.io/gorm"
+)
+
+func TestPruneJobsKeepsDeadAndRecentJobs(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		old := time.Now().Add(-48 * time.Hour)
+		recent := time.Now()
+		rows := []models.Job{
+			{Type: "old", Status: models.JobStatusSucceeded, RunAt: old, MaxAttempts: 1, FinishedAt: &old},
+			{Type: "recent", Status: models.JobStatusSucceeded, RunAt: recent, MaxAttempts: 1, FinishedAt: &recent},
+			{Type: "dead", Status: models.JobStatusDead, RunAt: old, MaxAttempts: 1, FinishedAt: &old},
+			{Type: "pending", Status: models.JobStatusPending, RunAt: old, MaxAttempts: 1},
+		}
+		if err := gorm.G[models.Job](db).CreateInBatches(ctx, &rows, len(rows)); err != nil {
+			t.Fatal(err)
+		}
+
+		jobs := NewJobServiceImpl(db)
+		pruned, err := jobs.PruneJobs(ctx, service.SystemActor("job:jobs.prune"), time.Now().Add(-24*time.Hour))
+		if err != nil {
+			t.Fatalf("prune: %v", err)
+		}
+		if pruned != 1 {
+			t.Errorf("pruned %d jobs, want 1", pruned)
+		}
+
+		left, err := gorm.G[models.Job](db).Order("id").Find(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var types []string
+		for _, job := range left {
+			types = append(types, job.Type)
+		}
+		if len(types) != 3 || types[0] != "recent" || types[1] != "dead" || types[2] != "pending" {
+			t.Errorf("jobs left after pruning: %v, want [recent dead pending]", types)
+		}
+	})
+}
@@ -0,0 +1,276 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"estore-server/models"
+	"estore-server/realtime"
+	"estore-server/service"
+
+	"gorm.io/gorm"
+)
+
+// MessageServiceImpl stores conversations and pushes new messages and read receipts
+// through the broker once they are committed
+type MessageServiceImpl struct {
+	DB     *gorm.DB
+	Broker realtime.Broker
+}
+
+var _ service.MessageService = (*MessageServiceImpl)(nil)
+
+func NewMessageServiceImpl(db *gorm.DB, broker realtime.Broker) *MessageServiceImpl {
+	return &MessageServiceImpl{DB: db, Broker: broker}
+}
+
+// StartConversation opens the buyer's conversation about a product, or returns the existing one
+func (s *MessageServiceImpl) StartConversation(actor service.Actor, buyerID, productID uint) (*models.Conversation, error) {
+	ctx := context.Background()
+	product, err := gorm.G[models.Product](s.DB).Where("id = ?", productID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if product.UserID == buyerID {
+		return nil, service.ErrMessageOwnProduct
+	}
+
+	conversation, err := gorm.G[models.Conversation](s.DB).Where("product_id = ? AND buyer_id = ?", productID, buyerID).First(ctx)
+	if err == nil {
+		return &conversation, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	conversation = models.Conversation{
+		ProductID:     productID,
+		BuyerID:       buyerID,
+		SellerID:      product.UserID,
+		LastMessageAt: time.Now(),
+	}
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := gorm.G[models.Conversation](tx).Create(ctx, &conversation); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "conversation.start", "conversation", conversation.ID, nil, conversation)
+	})
+	if err != nil {
+		// A concurrent request may have opened it first; the unique index keeps one
+		if existing, findErr := gorm.G[models.Conversation](s.DB).Where("product_id = ? AND buyer_id = ?", productID, buyerID).First(ctx); findErr == nil {
+			return &existing, nil
+		}
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+// GetConversation returns a conversation the user takes part in
+func (s *MessageServiceImpl) GetConversation(userID, conversationID uint) (*models.Conversation, error) {
+	ctx := context.Background()
+	conversation, err := gorm.G[models.Conversation](s.DB).Preload("Buyer", nil).Preload("Seller", nil).
+		Where("id = ?", conversationID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !conversation.HasParticipant(userID) {
+		return nil, service.ErrNotParticipant
+	}
+
+	// Trashed products stay readable so the thread keeps its context
+	product, err := gorm.G[models.Product](s.DB).Scopes(withTrashed).Where("id = ?", conversation.ProductID).First(ctx)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	} else if err == nil {
+		conversation.Product = &product
+	}
+	return &conversation, nil
+}
+
+// ListConversations returns the user's inbox, most recently active first, with the last
+// message and unread count of each conversation
+func (s *MessageServiceImpl) ListConversations(userID uint) ([]service.ConversationSummary, error) {
+	ctx := context.Background()
+	conversations, err := gorm.G[models.Conversation](s.DB).Preload("Buyer", nil).Preload("Seller", nil).
+		Where("buyer_id = ? OR seller_id = ?", userID, userID).Order("last_message_at DESC, id DESC").Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]service.ConversationSummary, 0, len(conversations))
+	if len(conversations) == 0 {
+		return summaries, nil
+	}
+
+	ids := make([]uint, 0, len(conversations))
+	productIDs := make([]uint, 0, len(conversations))
+	for _, conversation := range conversations {
+		ids = append(ids, conversation.ID)
+		productIDs = append(productIDs, conversation.ProductID)
+	}
+
+	products, err := gorm.G[models.Product](s.DB).Scopes(withTrashed).Where("id IN ?", productIDs).Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	productsByID := make(map[uint]*models.Product, len(products))
+	for i := range products {
+		productsByID[products[i].ID] = &products[i]
+	}
+
+	// The newest message of each conversation is the one with the highest ID
+	var lastIDs []uint
+	if err := s.DB.Model(&models.Message{}).Where("conversation_id IN ?", ids).
+		Group("conversation_id").Pluck("MAX(id)", &lastIDs).Error; err != nil {
+		return nil, err
+	}
+	lastMessages, err := gorm.G[models.Message](s.DB).Where("id IN ?", lastIDs).Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lastByConversation := make(map[uint]*models.Message, len(lastMessages))
+	for i := range lastMessages {
+		lastByConversation[lastMessages[i].ConversationID] = &lastMessages[i]
+	}
+
+	var unread []struct {
+		ConversationID uint
+		Count          int64
+	}
+	if err := s.DB.Model(&models.Message{}).Select("conversation_id, COUNT(*) AS count").
+		Where("conversation_id IN ? AND sender_id <> ? AND read_at IS NULL", ids, userID).
+		Group("conversation_id").Scan(&unread).Error; err != nil {
+		return nil, err
+	}
+	unreadByConversation := make(map[uint]int64, len(unread))
+	for _, row := range unread {
+		unreadByConversation[row.ConversationID] = row.Count
+	}
+
+	for _, conversation := range conversations {
+		conversation.Product = productsByID[conversation.ProductID]
+		summaries = append(summaries, service.ConversationSummary{
+			Conversation: conversation,
+			LastMessage:  lastByConversation[conversation.ID],
+			UnreadCount:  unreadByConversation[conversation.ID],
+		})
+	}
+	return summaries, nil
+}
+
+// SendMessage adds a message to the conversation and pushes it to both participants,
+// so the sender's other devices stay in sync as well
+func (s *MessageServiceImpl) SendMessage(actor service.Actor, senderID, conversationID uint, body string) (*models.Message, error) {
+	ctx := context.Background()
+	conversation, err := gorm.G[models.Conversation](s.DB).Where("id = ?", conversationID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !conversation.HasParticipant(senderID) {
+		return nil, service.ErrNotParticipant
+	}
+
+	message := &models.Message{
+		ConversationID: conversationID,
+		SenderID:       senderID,
+		Body:           body,
+	}
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := gorm.G[models.Message](tx).Create(ctx, message); err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.Conversation](tx).Where("id = ?", conversationID).Update(ctx, "last_message_at", message.CreatedAt); err != nil {
+			return err
+		}
+		// The body stays out of the audit log, which staff can read
+		return recordAudit(ctx, tx, actor, "message.send", "message", message.ID, nil, map[string]uint{"conversation_id": conversationID, "sender_id": senderID})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	event := realtime.Event{Type: service.EventMessageNew, Data: message}
+	s.Broker.Publish(conversation.BuyerID, event)
+	s.Broker.Publish(conversation.SellerID, event)
+	return message, nil
+}
+
+// ListMessages returns one page of the conversation's history, newest first
+func (s *MessageServiceImpl) ListMessages(userID, conversationID uint, cursor string, limit int) (*service.MessagePage, error) {
+	ctx := context.Background()
+	if limit <= 0 {
+		limit = service.DefaultMessagePageSize
+	}
+	limit = min(limit, service.MaxMessagePageSize)
+
+	conversation, err := gorm.G[models.Conversation](s.DB).Where("id = ?", conversationID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !conversation.HasParticipant(userID) {
+		return nil, service.ErrNotParticipant
+	}
+
+	query := gorm.G[models.Message](s.DB).Where("conversation_id = ?", conversationID)
+	messages, next, err := paginateByID(ctx, query, cursor, limit, func(message *models.Message) uint { return message.ID })
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.MessagePage{Messages: messages, NextCursor: next}, nil
+}
+
+// MarkRead marks the other participant's unread messages up to upToID as read and sends
+// them a read receipt. upToID 0 marks everything received so far.
+func (s *MessageServiceImpl) MarkRead(actor service.Actor, userID, conversationID, upToID uint) (*service.ReadReceipt, error) {
+	ctx := context.Background()
+	conversation, err := gorm.G[models.Conversation](s.DB).Where("id = ?", conversationID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !conversation.HasParticipant(userID) {
+		return nil, service.ErrNotParticipant
+	}
+
+	if upToID == 0 {
+		latest, err := gorm.G[models.Message](s.DB).Where("conversation_id = ?", conversationID).Order("id DESC").First(ctx)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &service.ReadReceipt{ConversationID: conversationID, ReaderID: userID}, nil
+		} else if err != nil {
+			return nil, err
+		}
+		upToID = latest.ID
+	}
+
+	receipt := &service.ReadReceipt{ConversationID: conversationID, ReaderID: userID, UpToID: upToID}
+	var rows int
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		rows, err = gorm.G[models.Message](tx).
+			Where("conversation_id = ? AND sender_id <> ? AND read_at IS NULL AND id <= ?", conversationID, userID, upToID).
+			Update(ctx, "read_at", time.Now())
+		if err != nil || rows == 0 {
+			return err
+		}
+		receipt.Count = rows
+		return recordAudit(ctx, tx, actor, "message.read", "conversation", conversationID, nil, receipt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if rows > 0 {
+		event := realtime.Event{Type: service.EventMessageRead, Data: receipt}
+		s.Broker.Publish(conversation.OtherParticipant(userID), event)
+		s.Broker.Publish(userID, event)
+	}
+	return receipt, nil
+}
+
+// UnreadCount returns how many messages across all conversations the user has not read
+func (s *MessageServiceImpl) UnreadCount(userID uint) (int64, error) {
+	ctx := context.Background()
+	return gorm.G[models.Message](s.DB).
+		Where("sender_id <> ? AND read_at IS NULL", userID).
+		Where("conversation_id IN (SELECT id FROM conversations WHERE buyer_id = ? OR seller_id = ?)", userID, userID).
+		Count(ctx, "id")
+}
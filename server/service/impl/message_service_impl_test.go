@@ -0,0 +1,54 @@
+package impl
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"estore-server/models"
+	"estore-server/realtime"
+	"estore-server/service"
+	"estore-server/testdb"
+
+	"gorm.io/gorm"
+)
+
+func TestMessagingIsAuditedWithoutBodies(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		seller := createTestUser(t, db, "seller")
+		buyer := createTestUser(t, db, "buyer")
+		product := createTestProduct(t, db, seller, 100, 1)
+
+		messages := NewMessageServiceImpl(db, realtime.NewMemoryBroker())
+		conversation, err := messages.StartConversation(service.UserActor(buyer.ID), buyer.ID, product.ID)
+		if err != nil {
+			t.Fatalf("start conversation: %v", err)
+		}
+		// Reopening the conversation changes nothing, so it is not audited again
+		if _, err := messages.StartConversation(service.UserActor(buyer.ID), buyer.ID, product.ID); err != nil {
+			t.Fatalf("reopen conversation: %v", err)
+		}
+		if _, err := messages.SendMessage(service.UserActor(buyer.ID), buyer.ID, conversation.ID, "can we meet at the station?"); err != nil {
+			t.Fatalf("send message: %v", err)
+		}
+		if _, err := messages.MarkRead(service.UserActor(seller.ID), seller.ID, conversation.ID, 0); err != nil {
+			t.Fatalf("mark read: %v", err)
+		}
+
+		entries, err := gorm.G[models.AuditLog](db).Order("id").Find(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var actions []string
+		for _, entry := range entries {
+			actions = append(actions, entry.Action)
+			if strings.Contains(string(entry.After), "station") {
+				t.Errorf("%s audit entry holds the message body: %s", entry.Action, entry.After)
+			}
+		}
+		if got := strings.Join(actions, " "); got != "conversation.start message.send message.read" {
+			t.Errorf("audited %q, want conversation.start message.send message.read", got)
+		}
+	})
+}
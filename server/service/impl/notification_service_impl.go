@@ -0,0 +1,98 @@
+package impl
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"estore-server/models"
+	"estore-server/realtime"
+	"estore-server/service"
+
+	"gorm.io/gorm"
+)
+
+// NotificationServiceImpl stores notifications and pushes each new one through the broker
+type NotificationServiceImpl struct {
+	DB     *gorm.DB
+	Broker realtime.Broker
+}
+
+var _ service.NotificationService = (*NotificationServiceImpl)(nil)
+
+func NewNotificationServiceImpl(db *gorm.DB, broker realtime.Broker) *NotificationServiceImpl {
+	return &NotificationServiceImpl{DB: db, Broker: broker}
+}
+
+func (s *NotificationServiceImpl) Notify(notification *models.Notification) error {
+	ctx := context.Background()
+	if err := gorm.G[models.Notification](s.DB).Create(ctx, notification); err != nil {
+		return err
+	}
+
+	s.Broker.Publish(notification.UserID, realtime.Event{Type: service.EventNotificationNew, Data: notification})
+	return nil
+}
+
+func (s *NotificationServiceImpl) ListNotifications(userID uint, unreadOnly bool, cursor string, limit int) (*service.NotificationPage, error) {
+	ctx := context.Background()
+	if limit <= 0 {
+		limit = service.DefaultNotificationPageSize
+	}
+	limit = min(limit, service.MaxNotificationPageSize)
+
+	query := gorm.G[models.Notification](s.DB).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	total, err := query.Count(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	notifications, next, err := paginateByID(ctx, query, cursor, limit, func(notification *models.Notification) uint { return notification.ID })
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.NotificationPage{Notifications: notifications, Total: total, NextCursor: next}, nil
+}
+
+// MarkRead marks one of the user's notifications as read; marking it again is a no-op
+func (s *NotificationServiceImpl) MarkRead(userID, notificationID uint) (*models.Notification, error) {
+	ctx := context.Background()
+	if _, err := gorm.G[models.Notification](s.DB).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", notificationID, userID).
+		Update(ctx, "read_at", time.Now()); err != nil {
+		return nil, err
+	}
+
+	notification, err := gorm.G[models.Notification](s.DB).Where("id = ? AND user_id = ?", notificationID, userID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+func (s *NotificationServiceImpl) MarkAllRead(userID uint) (int64, error) {
+	ctx := context.Background()
+	rows, err := gorm.G[models.Notification](s.DB).Where("user_id = ? AND read_at IS NULL", userID).Update(ctx, "read_at", time.Now())
+	return int64(rows), err
+}
+
+func (s *NotificationServiceImpl) UnreadCount(userID uint) (int64, error) {
+	ctx := context.Background()
+	return gorm.G[models.Notification](s.DB).Where("user_id = ? AND read_at IS NULL", userID).Count(ctx, "id")
+}
+
+// notify sends a notification on behalf of another service. The change it reports has
+// already been committed, so a failure is logged rather than returned. Services built
+// without a notification service, e.g. for read-only use, send nothing.
+func notify(notifications service.NotificationService, notification models.Notification) {
+	if notifications == nil {
+		return
+	}
+	if err := notifications.Notify(&notification); err != nil {
+		log.Printf("notifications: failed to notify user %d of %s: %v", notification.UserID, notification.Type, err)
+	}
+}
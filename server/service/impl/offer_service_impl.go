@@ -0,0 +1,261 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"estore-server/models"
+	"estore-server/service"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// openOfferStatuses are the statuses of offers still waiting for a response
+var openOfferStatuses = []models.OfferStatus{models.OfferStatusPending, models.OfferStatusCountered}
+
+// OfferServiceImpl stores offers and validates every step of a negotiation. Offers that run
+// past ExpiresAt are treated as expired straight away and marked so when next touched.
+type OfferServiceImpl struct {
+	DB            *gorm.DB
+	TTL           time.Duration
+	Notifications service.NotificationService
+}
+
+var _ service.OfferService = (*OfferServiceImpl)(nil)
+
+func NewOfferServiceImpl(db *gorm.DB, ttl time.Duration, notifications service.NotificationService) *OfferServiceImpl {
+	return &OfferServiceImpl{DB: db, TTL: ttl, Notifications: notifications}
+}
+
+// MakeOffer proposes a price for a product. A buyer can have one open or accepted, unused
+// offer per product at a time.
+func (s *OfferServiceImpl) MakeOffer(actor service.Actor, buyerID, productID uint, price int) (*models.Offer, error) {
+	if price <= 0 {
+		return nil, service.ErrOfferInvalidPrice
+	}
+
+	ctx := context.Background()
+	var offer *models.Offer
+	var productName string
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		// Locking the product serialises offers on it, which guards the one-offer rule
+		product, err := gorm.G[models.Product](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", productID).First(ctx)
+		if err != nil {
+			return err
+		}
+		if product.UserID == buyerID {
+			return service.ErrOfferOwnProduct
+		}
+		productName = product.Name
+
+		now := time.Now()
+		count, err := gorm.G[models.Offer](tx).
+			Where("product_id = ? AND buyer_id = ?", productID, buyerID).
+			Where("(status IN ? AND expires_at > ?) OR (status = ? AND order_id IS NULL)",
+				openOfferStatuses, now, models.OfferStatusAccepted).
+			Count(ctx, "id")
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return service.ErrOfferExists
+		}
+
+		offer = &models.Offer{
+			ProductID: productID,
+			BuyerID:   buyerID,
+			SellerID:  product.UserID,
+			Price:     price,
+			Status:    models.OfferStatusPending,
+			ExpiresAt: now.Add(s.TTL),
+		}
+		if err := gorm.G[models.Offer](tx).Create(ctx, offer); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "offer.create", "offer", offer.ID, nil, offer)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notify(s.Notifications, models.Notification{
+		UserID:     offer.SellerID,
+		Type:       models.NotificationOfferReceived,
+		Message:    fmt.Sprintf("You received an offer of %d for %q.", price, productName),
+		TargetType: "offer",
+		TargetID:   offer.ID,
+	})
+	return offer, nil
+}
+
+// AcceptOffer agrees to the offered price, locking it in for the buyer
+func (s *OfferServiceImpl) AcceptOffer(actor service.Actor, userID, offerID uint) (*models.Offer, error) {
+	return s.respond(actor, "offer.accept", userID, offerID, awaitingResponseFrom(userID), func(offer *models.Offer, now time.Time) {
+		offer.Status = models.OfferStatusAccepted
+		offer.RespondedAt = &now
+	})
+}
+
+// RejectOffer declines the offered price and closes the negotiation
+func (s *OfferServiceImpl) RejectOffer(actor service.Actor, userID, offerID uint) (*models.Offer, error) {
+	return s.respond(actor, "offer.reject", userID, offerID, awaitingResponseFrom(userID), func(offer *models.Offer, now time.Time) {
+		offer.Status = models.OfferStatusRejected
+		offer.RespondedAt = &now
+	})
+}
+
+// CounterOffer answers with a different price and hands the decision to the other party
+func (s *OfferServiceImpl) CounterOffer(actor service.Actor, userID, offerID uint, price int) (*models.Offer, error) {
+	if price <= 0 {
+		return nil, service.ErrOfferInvalidPrice
+	}
+
+	return s.respond(actor, "offer.counter", userID, offerID, awaitingResponseFrom(userID), func(offer *models.Offer, now time.Time) {
+		offer.Price = price
+		offer.Status = models.OfferStatusCountered
+		if userID == offer.BuyerID {
+			offer.Status = models.OfferStatusPending
+		}
+		offer.ExpiresAt = now.Add(s.TTL)
+		offer.RespondedAt = &now
+	})
+}
+
+// WithdrawOffer closes the buyer's open offer
+func (s *OfferServiceImpl) WithdrawOffer(actor service.Actor, buyerID, offerID uint) (*models.Offer, error) {
+	allowed := func(offer *models.Offer) bool {
+		return offer.BuyerID == buyerID
+	}
+	return s.respond(actor, "offer.withdraw", buyerID, offerID, allowed, func(offer *models.Offer, now time.Time) {
+		offer.Status = models.OfferStatusWithdrawn
+	})
+}
+
+// awaitingResponseFrom allows a step only by the party the offer is waiting on
+func awaitingResponseFrom(userID uint) func(offer *models.Offer) bool {
+	return func(offer *models.Offer) bool {
+		return offer.AwaitingResponseFrom() == userID
+	}
+}
+
+// respond applies a step to an open offer if the caller is a party to it and allowed permits
+// them to take the step. The offer row is locked, so two steps racing on the same offer
+// cannot both succeed. An offer found past its expiry is marked expired and ErrOfferExpired
+// is returned. The step is audited as action.
+func (s *OfferServiceImpl) respond(actor service.Actor, action string, userID, offerID uint, allowed func(offer *models.Offer) bool, apply func(offer *models.Offer, now time.Time)) (*models.Offer, error) {
+	ctx := context.Background()
+	var offer models.Offer
+	expired := false
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		offer, err = gorm.G[models.Offer](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", offerID).First(ctx)
+		if err != nil {
+			return err
+		}
+
+		if offer.BuyerID != userID && offer.SellerID != userID {
+			return service.ErrOfferNotAllowed
+		}
+		if !offer.Status.IsOpen() {
+			return service.ErrOfferClosed
+		}
+		if !allowed(&offer) {
+			return service.ErrOfferNotAllowed
+		}
+
+		before := offer
+		now := time.Now()
+		if offer.IsExpired(now) {
+			expired = true
+			offer.Status = models.OfferStatusExpired
+			if _, err := gorm.G[models.Offer](tx).Where("id = ?", offerID).Update(ctx, "status", models.OfferStatusExpired); err != nil {
+				return err
+			}
+			return recordAudit(ctx, tx, actor, "offer.expire", "offer", offerID, before, offer)
+		}
+
+		apply(&offer, now)
+		if _, err := gorm.G[models.Offer](tx).Where("id = ?", offerID).Updates(ctx, offer); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, action, "offer", offerID, before, offer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if expired {
+		return nil, service.ErrOfferExpired
+	}
+
+	// Tell the other party what the caller did
+	recipient := offer.SellerID
+	if userID == offer.SellerID {
+		recipient = offer.BuyerID
+	}
+	notify(s.Notifications, models.Notification{
+		UserID:     recipient,
+		Type:       models.NotificationOfferUpdated,
+		Message:    offerUpdateMessage(&offer),
+		TargetType: "offer",
+		TargetID:   offer.ID,
+	})
+	return &offer, nil
+}
+
+// offerUpdateMessage describes the step that brought an offer to its current status
+func offerUpdateMessage(offer *models.Offer) string {
+	switch offer.Status {
+	case models.OfferStatusAccepted:
+		return fmt.Sprintf("Offer #%d was accepted at %d.", offer.ID, offer.Price)
+	case models.OfferStatusRejected:
+		return fmt.Sprintf("Offer #%d was rejected.", offer.ID)
+	case models.OfferStatusWithdrawn:
+		return fmt.Sprintf("Offer #%d was withdrawn.", offer.ID)
+	default:
+		return fmt.Sprintf("Offer #%d was countered at %d.", offer.ID, offer.Price)
+	}
+}
+
+func (s *OfferServiceImpl) ListBuyerOffers(buyerID uint, status models.OfferStatus) ([]models.Offer, error) {
+	return s.listOffers("buyer_id = ?", buyerID, status)
+}
+
+func (s *OfferServiceImpl) ListSellerOffers(sellerID uint, status models.OfferStatus) ([]models.Offer, error) {
+	return s.listOffers("seller_id = ?", sellerID, status)
+}
+
+// listOffers returns the newest offers first, optionally narrowed to a single status.
+// Lapsed offers are marked expired first, so the status filter sees them as such.
+func (s *OfferServiceImpl) listOffers(query string, userID uint, status models.OfferStatus) ([]models.Offer, error) {
+	ctx := context.Background()
+	if _, err := gorm.G[models.Offer](s.DB).Where(query, userID).
+		Where("status IN ? AND expires_at <= ?", openOfferStatuses, time.Now()).
+		Update(ctx, "status", models.OfferStatusExpired); err != nil {
+		return nil, err
+	}
+
+	offers := gorm.G[models.Offer](s.DB).Where(query, userID)
+	if status != "" {
+		offers = offers.Where("status = ?", status)
+	}
+	return offers.Order("created_at DESC, id DESC").Find(ctx)
+}
+
+// agreedOffers returns the buyer's accepted offers on the given products that no order has
+// used yet, keyed by product ID. Pass a locking clause to claim them within a transaction.
+func agreedOffers(ctx context.Context, db *gorm.DB, buyerID uint, productIDs []uint, opts ...clause.Expression) (map[uint]models.Offer, error) {
+	offers, err := gorm.G[models.Offer](db, opts...).
+		Where("buyer_id = ? AND product_id IN ? AND status = ? AND order_id IS NULL", buyerID, productIDs, models.OfferStatusAccepted).
+		Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byProduct := make(map[uint]models.Offer, len(offers))
+	for _, offer := range offers {
+		byProduct[offer.ProductID] = offer
+	}
+	return byProduct, nil
+}
@@ -0,0 +1,90 @@
+package impl
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"estore-server/service"
+	"estore-server/testdb"
+
+	"gorm.io/gorm"
+)
+
+func TestAcceptedOfferPricesOneOrder(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		seller := createTestUser(t, db, "seller")
+		buyer := createTestUser(t, db, "buyer")
+		product := createTestProduct(t, db, seller, 100, 2)
+
+		offers := NewOfferServiceImpl(db, service.DefaultOfferTTL, nil)
+		offer, err := offers.MakeOffer(service.UserActor(buyer.ID), buyer.ID, product.ID, 80)
+		if err != nil {
+			t.Fatalf("make offer: %v", err)
+		}
+		if _, err := offers.AcceptOffer(service.UserActor(seller.ID), seller.ID, offer.ID); err != nil {
+			t.Fatalf("accept offer: %v", err)
+		}
+
+		orders := NewOrderServiceImpl(db, nil)
+		first, err := orders.CreateOrder(service.UserActor(buyer.ID), buyer.ID, product.ID, 1)
+		if err != nil {
+			t.Fatalf("first order: %v", err)
+		}
+		if first.TotalPrice != 80 {
+			t.Errorf("first order costs %d, want the agreed 80", first.TotalPrice)
+		}
+
+		// The agreed price is used up by the first order
+		second, err := orders.CreateOrder(service.UserActor(buyer.ID), buyer.ID, product.ID, 1)
+		if err != nil {
+			t.Fatalf("second order: %v", err)
+		}
+		if second.TotalPrice != 100 {
+			t.Errorf("second order costs %d, want the list price 100", second.TotalPrice)
+		}
+
+		used, err := offers.ListBuyerOffers(buyer.ID, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(used) != 1 || used[0].OrderID == nil || *used[0].OrderID != first.ID {
+			t.Errorf("offer is not linked to order %d: %+v", first.ID, used)
+		}
+	})
+}
+
+func TestConcurrentOffersAllowOnlyOne(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		seller := createTestUser(t, db, "seller")
+		buyer := createTestUser(t, db, "buyer")
+		product := createTestProduct(t, db, seller, 100, 1)
+
+		offers := NewOfferServiceImpl(db, service.DefaultOfferTTL, nil)
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+		for i := range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := offers.MakeOffer(service.UserActor(buyer.ID), buyer.ID, product.ID, 50+i)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		made := 0
+		for err := range errs {
+			switch {
+			case err == nil:
+				made++
+			case !errors.Is(err, service.ErrOfferExists):
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+		if made != 1 {
+			t.Errorf("%d offers were made, want 1", made)
+		}
+	})
+}
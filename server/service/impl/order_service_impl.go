@@ -0,0 +1,346 @@
+package impl
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"estore-server/models"
+	"estore-server/service"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrderServiceImpl persists orders and drives their status transitions
+type OrderServiceImpl struct {
+	DB            *gorm.DB
+	Notifications service.NotificationService
+}
+
+var _ service.OrderService = (*OrderServiceImpl)(nil)
+
+func NewOrderServiceImpl(db *gorm.DB, notifications service.NotificationService) *OrderServiceImpl {
+	return &OrderServiceImpl{DB: db, Notifications: notifications}
+}
+
+// orderLine is a product and quantity about to be ordered
+type orderLine struct {
+	product  models.Product
+	quantity int
+}
+
+// CreateOrder places a pending order for a single product at its current price, or the price
+// agreed in an accepted offer, and reserves its stock
+func (s *OrderServiceImpl) CreateOrder(actor service.Actor, buyerID, productID uint, quantity int) (*models.Order, error) {
+	if quantity <= 0 {
+		return nil, service.ErrOrderInvalidQuantity
+	}
+
+	ctx := context.Background()
+	var order *models.Order
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		product, err := gorm.G[models.Product](tx).Preload("User", nil).Where("id = ?", productID).First(ctx)
+		if err != nil {
+			return err
+		}
+		if product.UserID == buyerID {
+			return service.ErrOrderOwnProduct
+		}
+
+		if err := reserveStock(ctx, tx, productID, quantity); err != nil {
+			return err
+		}
+
+		order, err = s.createOrder(ctx, tx, actor, buyerID, []orderLine{{product: product, quantity: quantity}})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyOrderCreated(order)
+	return order, nil
+}
+
+// CheckoutCart turns the buyer's cart into pending orders, one per seller, reserves their stock
+// and empties the cart. Nothing is ordered unless every item can be.
+func (s *OrderServiceImpl) CheckoutCart(actor service.Actor, buyerID uint) ([]models.Order, error) {
+	ctx := context.Background()
+	var orders []models.Order
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		items, err := gorm.G[models.CartItem](tx).Where("user_id = ?", buyerID).Order("created_at").Find(ctx)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return service.ErrOrderEmpty
+		}
+
+		// Reserve in product ID order so concurrent checkouts lock rows consistently and cannot deadlock
+		reservations := slices.Clone(items)
+		slices.SortFunc(reservations, func(a, b models.CartItem) int {
+			return cmp.Compare(a.ProductID, b.ProductID)
+		})
+		for _, item := range reservations {
+			if err := reserveStock(ctx, tx, item.ProductID, item.Quantity); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return service.ErrOrderProductUnavailable
+				}
+				return err
+			}
+		}
+
+		productIDs := make([]uint, 0, len(items))
+		for _, item := range items {
+			productIDs = append(productIDs, item.ProductID)
+		}
+		products, err := gorm.G[models.Product](tx).Preload("User", nil).Where("id IN ?", productIDs).Find(ctx)
+		if err != nil {
+			return err
+		}
+		byID := make(map[uint]models.Product, len(products))
+		for _, product := range products {
+			byID[product.ID] = product
+		}
+
+		// Group lines by seller, keeping the cart's order of first appearance
+		var sellerIDs []uint
+		linesBySeller := make(map[uint][]orderLine)
+		for _, item := range items {
+			product, ok := byID[item.ProductID]
+			if !ok {
+				return service.ErrOrderProductUnavailable
+			}
+			if _, seen := linesBySeller[product.UserID]; !seen {
+				sellerIDs = append(sellerIDs, product.UserID)
+			}
+			linesBySeller[product.UserID] = append(linesBySeller[product.UserID], orderLine{product: product, quantity: item.Quantity})
+		}
+
+		for _, sellerID := range sellerIDs {
+			order, err := s.createOrder(ctx, tx, actor, buyerID, linesBySeller[sellerID])
+			if err != nil {
+				return err
+			}
+			orders = append(orders, *order)
+		}
+
+		_, err = gorm.G[models.CartItem](tx).Where("user_id = ?", buyerID).Delete(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range orders {
+		s.notifyOrderCreated(&orders[i])
+	}
+	return orders, nil
+}
+
+// createOrder snapshots the given lines, which must share a seller, into a pending order.
+// Products the buyer has an accepted offer on are priced at the agreed price, and the offer
+// is marked as used by the order.
+func (s *OrderServiceImpl) createOrder(ctx context.Context, tx *gorm.DB, actor service.Actor, buyerID uint, lines []orderLine) (*models.Order, error) {
+	if len(lines) == 0 {
+		return nil, service.ErrOrderEmpty
+	}
+
+	productIDs := make([]uint, 0, len(lines))
+	for _, line := range lines {
+		productIDs = append(productIDs, line.product.ID)
+	}
+	agreed, err := agreedOffers(ctx, tx, buyerID, productIDs, clause.Locking{Strength: "UPDATE"})
+	if err != nil {
+		return nil, err
+	}
+
+	seller := lines[0].product.User
+	order := &models.Order{
+		BuyerID:    buyerID,
+		SellerID:   lines[0].product.UserID,
+		SellerName: seller.Username,
+		Status:     models.OrderStatusPending,
+	}
+
+	var offerIDs []uint
+	for _, line := range lines {
+		if line.product.UserID == buyerID {
+			return nil, service.ErrOrderOwnProduct
+		}
+		if line.quantity <= 0 {
+			return nil, service.ErrOrderInvalidQuantity
+		}
+
+		unitPrice := line.product.Price
+		if offer, ok := agreed[line.product.ID]; ok {
+			unitPrice = offer.Price
+			offerIDs = append(offerIDs, offer.ID)
+		}
+
+		order.Items = append(order.Items, models.OrderItem{
+			ProductID:   line.product.ID,
+			ProductName: line.product.Name,
+			UnitPrice:   unitPrice,
+			Quantity:    line.quantity,
+		})
+		order.TotalPrice += unitPrice * line.quantity
+	}
+
+	if err := gorm.G[models.Order](tx).Create(ctx, order); err != nil {
+		return nil, err
+	}
+
+	if len(offerIDs) > 0 {
+		if _, err := gorm.G[models.Offer](tx).Where("id IN ?", offerIDs).Update(ctx, "order_id", order.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := recordAudit(ctx, tx, actor, "order.create", "order", order.ID, nil, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// notifyOrderCreated tells the seller about a new order
+func (s *OrderServiceImpl) notifyOrderCreated(order *models.Order) {
+	notify(s.Notifications, models.Notification{
+		UserID:     order.SellerID,
+		Type:       models.NotificationOrderCreated,
+		Message:    fmt.Sprintf("You have a new order #%d.", order.ID),
+		TargetType: "order",
+		TargetID:   order.ID,
+	})
+}
+
+func (s *OrderServiceImpl) GetOrder(orderID uint) (*models.Order, error) {
+	ctx := context.Background()
+	order, err := gorm.G[models.Order](s.DB).Preload("Items", nil).Where("id = ?", orderID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (s *OrderServiceImpl) ListBuyerOrders(buyerID uint, status models.OrderStatus) ([]models.Order, error) {
+	query := gorm.G[models.Order](s.DB).Preload("Items", nil).Where("buyer_id = ?", buyerID)
+	return s.listOrders(query, status)
+}
+
+func (s *OrderServiceImpl) ListSellerOrders(sellerID uint, status models.OrderStatus) ([]models.Order, error) {
+	query := gorm.G[models.Order](s.DB).Preload("Items", nil).Where("seller_id = ?", sellerID)
+	return s.listOrders(query, status)
+}
+
+func (s *OrderServiceImpl) ListAllOrders(status models.OrderStatus) ([]models.Order, error) {
+	query := gorm.G[models.Order](s.DB).Preload("Items", nil)
+	return s.listOrders(query, status)
+}
+
+// listOrders returns the newest orders first, optionally narrowed to a single status
+func (s *OrderServiceImpl) listOrders(query gorm.ChainInterface[models.Order], status models.OrderStatus) ([]models.Order, error) {
+	ctx := context.Background()
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	return query.Order("created_at DESC, id DESC").Find(ctx)
+}
+
+// TransitionOrder moves an order to a new status if the lifecycle allows it.
+// The update is conditional on the status read, so concurrent transitions cannot both win.
+// Cancelling an order returns its items to stock.
+func (s *OrderServiceImpl) TransitionOrder(actor service.Actor, orderID uint, to models.OrderStatus) (*models.Order, error) {
+	ctx := context.Background()
+	order, err := gorm.G[models.Order](s.DB).Preload("Items", nil).Where("id = ?", orderID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !order.Status.CanTransitionTo(to) {
+		return nil, &service.OrderTransitionError{From: order.Status, To: to}
+	}
+
+	now := time.Now()
+	changes := models.Order{Status: to}
+	switch to {
+	case models.OrderStatusPaid:
+		changes.PaidAt = &now
+	case models.OrderStatusShipped:
+		changes.ShippedAt = &now
+	case models.OrderStatusCompleted:
+		changes.CompletedAt = &now
+	case models.OrderStatusCancelled:
+		changes.CancelledAt = &now
+	}
+
+	var updated models.Order
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		rows, err := gorm.G[models.Order](tx).Where("id = ? AND status = ?", orderID, order.Status).Updates(ctx, changes)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			// Someone else moved the order first; report against its current status
+			current, err := gorm.G[models.Order](tx).Where("id = ?", orderID).First(ctx)
+			if err != nil {
+				return err
+			}
+			return &service.OrderTransitionError{From: current.Status, To: to}
+		}
+
+		if to == models.OrderStatusCancelled {
+			for _, item := range order.Items {
+				if err := releaseStock(ctx, tx, item.ProductID, item.Quantity); err != nil {
+					return err
+				}
+			}
+			// Agreed prices the order used become available to the buyer again
+			if _, err := gorm.G[models.Offer](tx).Where("order_id = ?", orderID).Update(ctx, "order_id", nil); err != nil {
+				return err
+			}
+		}
+
+		updated, err = gorm.G[models.Order](tx).Preload("Items", nil).Where("id = ?", orderID).First(ctx)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "order.transition", "order", orderID, order, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notifyOrderStatus(s.Notifications, &order, to)
+	return &updated, nil
+}
+
+// notifyOrderStatus tells the party waiting on a transition that it happened, or both
+// parties when the order is cancelled
+func notifyOrderStatus(notifications service.NotificationService, order *models.Order, to models.OrderStatus) {
+	var recipients []uint
+	switch to {
+	case models.OrderStatusPaid, models.OrderStatusCompleted:
+		recipients = []uint{order.SellerID}
+	case models.OrderStatusShipped:
+		recipients = []uint{order.BuyerID}
+	case models.OrderStatusCancelled:
+		recipients = []uint{order.BuyerID, order.SellerID}
+	}
+
+	for _, userID := range recipients {
+		notify(notifications, models.Notification{
+			UserID:     userID,
+			Type:       models.NotificationOrderStatus,
+			Message:    fmt.Sprintf("Order #%d is now %s.", order.ID, to),
+			TargetType: "order",
+			TargetID:   order.ID,
+		})
+	}
+}
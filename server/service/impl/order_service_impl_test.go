@@ -0,0 +1,116 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"estore-server/models"
+	"estore-server/search"
+	"estore-server/service"
+	"estore-server/testdb"
+
+	"gorm.io/gorm"
+)
+
+// SQLite runs on a single connection, so only the MySQL and PostgreSQL runs in CI actually
+// race the orders against each other's row locks
+func TestConcurrentOrdersDoNotOversell(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+
+		const stock = 5
+		seller := createTestUser(t, db, "seller")
+		product := createTestProduct(t, db, seller, 100, stock)
+		buyers := createTestUsers(t, db, "buyer", 20)
+
+		// Half the buyers check out a cart, the other half order directly
+		carts := NewCartServiceImpl(db)
+		for _, buyer := range buyers[len(buyers)/2:] {
+			if _, err := carts.AddItem(service.UserActor(buyer.ID), buyer.ID, product.ID, 1); err != nil {
+				t.Fatalf("add to cart: %v", err)
+			}
+		}
+
+		orders := NewOrderServiceImpl(db, nil)
+		var wg sync.WaitGroup
+		errs := make(chan error, len(buyers))
+		for i, buyer := range buyers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var err error
+				if i < len(buyers)/2 {
+					_, err = orders.CreateOrder(service.UserActor(buyer.ID), buyer.ID, product.ID, 1)
+				} else {
+					_, err = orders.CheckoutCart(service.UserActor(buyer.ID), buyer.ID)
+				}
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		placed := 0
+		for err := range errs {
+			switch {
+			case err == nil:
+				placed++
+			case !errors.Is(err, service.ErrInsufficientStock):
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+		if placed != stock {
+			t.Errorf("placed %d orders, want %d", placed, stock)
+		}
+
+		remaining, err := gorm.G[models.Product](db).Where("id = ?", product.ID).First(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if remaining.Stock != 0 {
+			t.Errorf("stock is %d, want 0", remaining.Stock)
+		}
+
+		var ordered int64
+		if err := db.Model(&models.OrderItem{}).Where("product_id = ?", product.ID).Select("COALESCE(SUM(quantity), 0)").Scan(&ordered).Error; err != nil {
+			t.Fatal(err)
+		}
+		if ordered != stock {
+			t.Errorf("ordered %d units, want %d", ordered, stock)
+		}
+	})
+}
+
+func TestUpdateProductKeepsReservedStock(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+
+		seller := createTestUser(t, db, "seller")
+		buyer := createTestUser(t, db, "buyer")
+		product := createTestProduct(t, db, seller, 100, 3)
+
+		if _, err := NewOrderServiceImpl(db, nil).CreateOrder(service.UserActor(buyer.ID), buyer.ID, product.ID, 1); err != nil {
+			t.Fatalf("create order: %v", err)
+		}
+
+		// The seller's form still shows the stock from before the order; leaving it out must not
+		// hand the reserved unit back
+		products := NewProductServiceImpl(db, nil, search.NewMemoryIndex(), nil)
+		if _, err := products.UpdateProduct(service.UserActor(seller.ID), product.ID, service.ProductUpdate{Name: "Lamp", Description: "Now in red", Price: 100}); err != nil {
+			t.Fatalf("update product: %v", err)
+		}
+
+		updated, err := gorm.G[models.Product](db).Where("id = ?", product.ID).First(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if updated.Stock != 2 {
+			t.Errorf("stock is %d, want 2", updated.Stock)
+		}
+		if updated.Description != "Now in red" {
+			t.Errorf("description is %q, want %q", updated.Description, "Now in red")
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package impl
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"time"
+
+	"estore-server/jobs"
+	"estore-server/mail"
+	"estore-server/models"
+	"estore-server/service"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PasswordResetServiceImpl issues reset tokens by email and redeems them
+type PasswordResetServiceImpl struct {
+	DB            *gorm.DB
+	Mailer        mail.Mailer
+	Notifications service.NotificationService
+	// ResetURL is the page that accepts the token; when empty the email carries only the token
+	ResetURL string
+}
+
+var _ service.PasswordResetService = (*PasswordResetServiceImpl)(nil)
+
+func NewPasswordResetServiceImpl(db *gorm.DB, mailer mail.Mailer, notifications service.NotificationService) *PasswordResetServiceImpl {
+	return &PasswordResetServiceImpl{
+		DB:            db,
+		Mailer:        mailer,
+		Notifications: notifications,
+		ResetURL:      os.Getenv("PASSWORD_RESET_URL"),
+	}
+}
+
+// RequestReset queues a job that emails a fresh reset token to each account using the
+// address. The request does the same work whether or not the address is registered, so
+// neither the response time nor a mail failure reveals which it is.
+func (s *PasswordResetServiceImpl) RequestReset(email string) error {
+	_, err := jobs.Enqueue(context.Background(), s.DB, service.JobPasswordResetEmail, service.PasswordResetJob{Email: email}, jobs.EnqueueOptions{})
+	return err
+}
+
+func (s *PasswordResetServiceImpl) SendResets(ctx context.Context, email string) error {
+	users, err := gorm.G[models.User](s.DB).Where("email = ?", email).Find(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, user := range users {
+		if err := s.sendReset(ctx, user); err != nil {
+			errs = append(errs, fmt.Errorf("email user %d: %w", user.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sendReset issues a reset token for the user and emails it, unless the user has already
+// been sent as many as the throttle allows
+func (s *PasswordResetServiceImpl) sendReset(ctx context.Context, user models.User) error {
+	recent, err := gorm.G[models.PasswordResetToken](s.DB).
+		Where("user_id = ? AND created_at > ?", user.ID, time.Now().Add(-service.PasswordResetWindow)).
+		Count(ctx, "id")
+	if err != nil {
+		return err
+	}
+	if recent >= service.MaxPasswordResetsPerWindow {
+		log.Printf("password reset: user %d already got %d reset emails recently; not sending another", user.ID, recent)
+		return nil
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return err
+	}
+	err = gorm.G[models.PasswordResetToken](s.DB).Create(ctx, &models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(service.PasswordResetTokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+	return s.Mailer.Send(ctx, s.resetMessage(&user, token))
+}
+
+// ResetPassword sets a new password using an emailed token. Every outstanding token of
+// the user is spent and all of their sessions are logged out.
+func (s *PasswordResetServiceImpl) ResetPassword(actor service.Actor, token, newPassword string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	ctx := context.Background()
+	var userID uint
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		reset, err := gorm.G[models.PasswordResetToken](tx, clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hashToken(token), time.Now()).
+			First(ctx)
+		if err != nil {
+			return service.ErrResetTokenInvalid
+		}
+
+		rows, err := gorm.G[models.UserAuth](tx).Where("id = ?", reset.UserID).Update(ctx, "password", string(hashedPassword))
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return service.ErrResetTokenInvalid
+		}
+
+		if err := invalidateResetTokens(ctx, tx, reset.UserID); err != nil {
+			return err
+		}
+		userID = reset.UserID
+		if err := recordAudit(ctx, tx, actor, "user.password.reset", "user", userID, nil, nil); err != nil {
+			return err
+		}
+		return revokeUserSessions(ctx, tx, reset.UserID)
+	})
+	if err != nil {
+		return err
+	}
+
+	notify(s.Notifications, passwordChangedNotification(userID))
+	return nil
+}
+
+func (s *PasswordResetServiceImpl) resetMessage(user *models.User, token string) mail.Message {
+	body := fmt.Sprintf("Hi %s,\n\nWe received a request to reset your password. ", user.Username)
+	if s.ResetURL != "" {
+		body += fmt.Sprintf("Open the link below to choose a new one:\n\n%s?token=%s\n", s.ResetURL, url.QueryEscape(token))
+	} else {
+		body += fmt.Sprintf("Use this reset token to choose a new one:\n\n%s\n", token)
+	}
+	body += fmt.Sprintf("\nThe token expires in %d minutes and can be used once. "+
+		"If you did not ask for a reset, you can ignore this email.\n", int(service.PasswordResetTokenTTL.Minutes()))
+
+	return mail.Message{
+		To:      user.Email,
+		Subject: "Reset your estore password",
+		Body:    body,
+	}
+}
+
+// invalidateResetTokens spends every unused reset token of the user, so a password
+// change by any means makes previously emailed tokens useless
+func invalidateResetTokens(ctx context.Context, db *gorm.DB, userID uint) error {
+	_, err := gorm.G[models.PasswordResetToken](db).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update(ctx, "used_at", time.Now())
+	return err
+}
+
+// randomToken returns a 256-bit URL-safe secret
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
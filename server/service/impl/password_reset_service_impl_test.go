@@ -0,0 +1,87 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"estore-server/mail"
+	"estore-server/models"
+	"estore-server/service"
+	"estore-server/testdb"
+
+	"gorm.io/gorm"
+)
+
+// recordingMailer keeps every message it is asked to send and answers with err
+type recordingMailer struct {
+	mu   sync.Mutex
+	sent []mail.Message
+	err  error
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg mail.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return m.err
+}
+
+func TestRequestResetDoesNotRevealAccounts(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		createTestUser(t, db, "alice")
+
+		mailer := &recordingMailer{err: errors.New("smtp unavailable")}
+		resets := NewPasswordResetServiceImpl(db, mailer, nil)
+
+		if err := resets.RequestReset("nobody@example.com"); err != nil {
+			t.Errorf("unknown address: %v", err)
+		}
+		if err := resets.RequestReset("alice@example.com"); err != nil {
+			t.Errorf("registered address with a failing mailer: %v", err)
+		}
+
+		// Both requests only queue a job
+		queued, err := gorm.G[models.Job](db).Where("type = ?", service.JobPasswordResetEmail).Find(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(queued) != 2 {
+			t.Fatalf("queued %d jobs, want one per request", len(queued))
+		}
+		if len(mailer.sent) != 0 {
+			t.Errorf("mailed %d messages while handling the requests", len(mailer.sent))
+		}
+
+		if err := resets.SendResets(ctx, "nobody@example.com"); err != nil {
+			t.Errorf("sending to an unknown address: %v", err)
+		}
+		if err := resets.SendResets(ctx, "alice@example.com"); err == nil {
+			t.Error("a failed send was not reported to the job, so it would not be retried")
+		}
+		if len(mailer.sent) != 1 || mailer.sent[0].To != "alice@example.com" {
+			t.Errorf("mailed %+v, want one message to alice@example.com", mailer.sent)
+		}
+	})
+}
+
+func TestResetEmailsAreThrottledPerAccount(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		createTestUser(t, db, "alice")
+
+		mailer := &recordingMailer{}
+		resets := NewPasswordResetServiceImpl(db, mailer, nil)
+		for range service.MaxPasswordResetsPerWindow + 2 {
+			if err := resets.SendResets(ctx, "alice@example.com"); err != nil {
+				t.Fatalf("send: %v", err)
+			}
+		}
+
+		if len(mailer.sent) != service.MaxPasswordResetsPerWindow {
+			t.Errorf("sent %d reset emails, want %d", len(mailer.sent), service.MaxPasswordResetsPerWindow)
+		}
+	})
+}
@@ -0,0 +1,182 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"estore-server/models"
+	"estore-server/payment"
+	"estore-server/service"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PaymentServiceImpl records payments and settles orders when the provider reports success
+type PaymentServiceImpl struct {
+	DB            *gorm.DB
+	Provider      payment.PaymentProvider
+	Notifications service.NotificationService
+}
+
+var _ service.PaymentService = (*PaymentServiceImpl)(nil)
+
+func NewPaymentServiceImpl(db *gorm.DB, provider payment.PaymentProvider, notifications service.NotificationService) *PaymentServiceImpl {
+	return &PaymentServiceImpl{DB: db, Provider: provider, Notifications: notifications}
+}
+
+// CreatePayment opens a payment intent for the order's total. Each call starts a new
+// attempt; whichever attempt settles first pays the order.
+func (s *PaymentServiceImpl) CreatePayment(actor service.Actor, buyerID, orderID uint) (*models.Payment, error) {
+	ctx := context.Background()
+	order, err := gorm.G[models.Order](s.DB).Where("id = ?", orderID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if order.BuyerID != buyerID {
+		return nil, service.ErrPaymentNotAllowed
+	}
+	if order.Status != models.OrderStatusPending {
+		return nil, service.ErrOrderNotPayable
+	}
+
+	intent, err := s.Provider.CreateIntent(ctx, payment.IntentRequest{
+		Reference:   fmt.Sprintf("order-%d", order.ID),
+		Amount:      order.TotalPrice,
+		Description: fmt.Sprintf("Order #%d from %s", order.ID, order.SellerName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.Payment{
+		OrderID:     order.ID,
+		PayerID:     buyerID,
+		Provider:    s.Provider.Name(),
+		IntentID:    intent.ID,
+		Amount:      intent.Amount,
+		Status:      models.PaymentStatusPending,
+		RedirectURL: intent.RedirectURL,
+		QRCode:      intent.QRCode,
+	}
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := gorm.G[models.Payment](tx).Create(ctx, record); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "payment.create", "payment", record.ID, nil, record)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (s *PaymentServiceImpl) GetPayment(paymentID uint) (*models.Payment, error) {
+	record, err := gorm.G[models.Payment](s.DB).Where("id = ?", paymentID).First(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// HandleCallback settles a payment from a verified provider callback. A successful payment
+// moves its order from pending to paid in the same transaction. If the order was paid by
+// another attempt or cancelled in the meantime, the payment is recorded as refund_due and
+// the payer is told it will be refunded.
+func (s *PaymentServiceImpl) HandleCallback(actor service.Actor, header http.Header, body []byte) (*models.Payment, error) {
+	callback, err := s.Provider.VerifyCallback(header, body)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	provider := s.Provider.Name()
+	var record models.Payment
+	var order *models.Order
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		seen, err := gorm.G[models.Payment](tx).Where("provider = ? AND transaction_id = ?", provider, callback.TransactionID).Count(ctx, "id")
+		if err != nil {
+			return err
+		}
+		if seen > 0 {
+			return service.ErrDuplicateCallback
+		}
+
+		// Locking the payment serialises callbacks for the same intent
+		record, err = gorm.G[models.Payment](tx, clause.Locking{Strength: "UPDATE"}).
+			Where("provider = ? AND intent_id = ?", provider, callback.IntentID).First(ctx)
+		if err != nil {
+			return err
+		}
+		if record.Status != models.PaymentStatusPending {
+			return service.ErrDuplicateCallback
+		}
+		if callback.TransactionID == "" || callback.Amount != record.Amount {
+			return service.ErrCallbackMismatch
+		}
+
+		record.TransactionID = &callback.TransactionID
+		switch callback.Status {
+		case payment.CallbackSucceeded:
+			now := time.Now()
+			record.Status = models.PaymentStatusSucceeded
+			record.PaidAt = &now
+		case payment.CallbackFailed:
+			record.Status = models.PaymentStatusFailed
+		default:
+			return service.ErrCallbackMismatch
+		}
+
+		// Only the first payment to succeed settles the order; any other was collected for an
+		// order that is already paid or cancelled and must go back to the payer
+		if record.Status == models.PaymentStatusSucceeded {
+			rows, err := gorm.G[models.Order](tx).Where("id = ? AND status = ?", record.OrderID, models.OrderStatusPending).
+				Updates(ctx, models.Order{Status: models.OrderStatusPaid, PaidAt: record.PaidAt})
+			if err != nil {
+				return err
+			}
+			if rows == 0 {
+				record.Status = models.PaymentStatusRefundDue
+			} else {
+				paid, err := gorm.G[models.Order](tx).Where("id = ?", record.OrderID).First(ctx)
+				if err != nil {
+					return err
+				}
+				order = &paid
+			}
+		}
+
+		if _, err := gorm.G[models.Payment](tx).Where("id = ?", record.ID).Updates(ctx, record); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "payment.callback", "payment", record.ID, nil, record)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case order != nil:
+		notifyOrderStatus(s.Notifications, order, models.OrderStatusPaid)
+	case record.Status == models.PaymentStatusFailed:
+		notify(s.Notifications, models.Notification{
+			UserID:     record.PayerID,
+			Type:       models.NotificationOrderStatus,
+			Message:    fmt.Sprintf("Payment for order #%d failed.", record.OrderID),
+			TargetType: "order",
+			TargetID:   record.OrderID,
+		})
+	case record.Status == models.PaymentStatusRefundDue:
+		notify(s.Notifications, models.Notification{
+			UserID:     record.PayerID,
+			Type:       models.NotificationOrderStatus,
+			Message:    fmt.Sprintf("Order #%d was no longer awaiting payment, so your payment of %d will be refunded.", record.OrderID, record.Amount),
+			TargetType: "order",
+			TargetID:   record.OrderID,
+		})
+	}
+	return &record, nil
+}
@@ -0,0 +1,146 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"estore-server/models"
+	"estore-server/payment"
+	"estore-server/realtime"
+	"estore-server/service"
+	"estore-server/testdb"
+
+	"gorm.io/gorm"
+)
+
+// signedCallback is a callback as the provider posted it to the webhook
+type signedCallback struct {
+	header http.Header
+	body   []byte
+}
+
+// newCapturingProvider returns a fake provider whose callbacks are captured instead of
+// being handled, so the test decides when to deliver them
+func newCapturingProvider(t *testing.T) (*payment.FakeProvider, <-chan signedCallback) {
+	t.Helper()
+	callbacks := make(chan signedCallback, 10)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		callbacks <- signedCallback{header: r.Header.Clone(), body: body}
+	}))
+	t.Cleanup(webhook.Close)
+	return payment.NewFakeProvider("secret", "http://localhost/checkout", webhook.URL), callbacks
+}
+
+// complete plays the payer finishing checkout and returns the callback the provider sent
+func complete(t *testing.T, provider *payment.FakeProvider, callbacks <-chan signedCallback, intentID string) signedCallback {
+	t.Helper()
+	if err := provider.Complete(context.Background(), intentID, payment.CallbackSucceeded); err != nil {
+		t.Fatalf("complete checkout: %v", err)
+	}
+	return <-callbacks
+}
+
+func TestCallbackIsAppliedOncePerTransaction(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		seller := createTestUser(t, db, "seller")
+		buyer := createTestUser(t, db, "buyer")
+		product := createTestProduct(t, db, seller, 100, 1)
+		order, err := NewOrderServiceImpl(db, nil).CreateOrder(service.UserActor(buyer.ID), buyer.ID, product.ID, 1)
+		if err != nil {
+			t.Fatalf("create order: %v", err)
+		}
+
+		provider, callbacks := newCapturingProvider(t)
+		payments := NewPaymentServiceImpl(db, provider, nil)
+		record, err := payments.CreatePayment(service.UserActor(buyer.ID), buyer.ID, order.ID)
+		if err != nil {
+			t.Fatalf("create payment: %v", err)
+		}
+		callback := complete(t, provider, callbacks, record.IntentID)
+
+		settled, err := payments.HandleCallback(service.SystemActor("payment:fake"), callback.header, callback.body)
+		if err != nil {
+			t.Fatalf("handle callback: %v", err)
+		}
+		if settled.Status != models.PaymentStatusSucceeded {
+			t.Errorf("payment is %s, want succeeded", settled.Status)
+		}
+
+		// The provider retrying the same delivery changes nothing
+		if _, err := payments.HandleCallback(service.SystemActor("payment:fake"), callback.header, callback.body); !errors.Is(err, service.ErrDuplicateCallback) {
+			t.Errorf("replayed callback returned %v, want %v", err, service.ErrDuplicateCallback)
+		}
+		audits, err := gorm.G[models.AuditLog](db).Where("action = ?", "payment.callback").Count(context.Background(), "id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if audits != 1 {
+			t.Errorf("callback was applied %d times, want once", audits)
+		}
+	})
+}
+
+func TestLatePaymentForPaidOrderIsMarkedForRefund(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		seller := createTestUser(t, db, "seller")
+		buyer := createTestUser(t, db, "buyer")
+		product := createTestProduct(t, db, seller, 100, 1)
+		order, err := NewOrderServiceImpl(db, nil).CreateOrder(service.UserActor(buyer.ID), buyer.ID, product.ID, 1)
+		if err != nil {
+			t.Fatalf("create order: %v", err)
+		}
+
+		// The buyer opens checkout twice and pays both
+		provider, callbacks := newCapturingProvider(t)
+		payments := NewPaymentServiceImpl(db, provider, NewNotificationServiceImpl(db, realtime.NewMemoryBroker()))
+		var attempts []*models.Payment
+		for range 2 {
+			record, err := payments.CreatePayment(service.UserActor(buyer.ID), buyer.ID, order.ID)
+			if err != nil {
+				t.Fatalf("create payment: %v", err)
+			}
+			attempts = append(attempts, record)
+		}
+		var settled []*models.Payment
+		for _, attempt := range attempts {
+			callback := complete(t, provider, callbacks, attempt.IntentID)
+			record, err := payments.HandleCallback(service.SystemActor("payment:fake"), callback.header, callback.body)
+			if err != nil {
+				t.Fatalf("handle callback: %v", err)
+			}
+			settled = append(settled, record)
+		}
+
+		if settled[0].Status != models.PaymentStatusSucceeded {
+			t.Errorf("first payment is %s, want succeeded", settled[0].Status)
+		}
+		if settled[1].Status != models.PaymentStatusRefundDue {
+			t.Errorf("second payment is %s, want %s", settled[1].Status, models.PaymentStatusRefundDue)
+		}
+		paid, err := gorm.G[models.Order](db).Where("id = ?", order.ID).First(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if paid.Status != models.OrderStatusPaid {
+			t.Errorf("order is %s, want paid", paid.Status)
+		}
+
+		notices, err := gorm.G[models.Notification](db).Where("user_id = ? AND message LIKE ?", buyer.ID, "%refunded%").Count(ctx, "id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if notices != 1 {
+			t.Errorf("buyer got %d refund notifications, want 1", notices)
+		}
+	})
+}
@@ -0,0 +1,214 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+
+	"estore-server/imaging"
+	"estore-server/models"
+	"estore-server/service"
+	"estore-server/storage"
+
+	"gorm.io/gorm"
+)
+
+// thumbnailSize is the longest side, in pixels, of generated thumbnails
+const thumbnailSize = 320
+
+// imageExtensions maps accepted sniffed content types to file extensions
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// ProductImageServiceImpl stores image files in a BlobStore and their metadata in the database
+type ProductImageServiceImpl struct {
+	DB    *gorm.DB
+	Store storage.BlobStore
+}
+
+var _ service.ProductImageService = (*ProductImageServiceImpl)(nil)
+
+func NewProductImageServiceImpl(db *gorm.DB, store storage.BlobStore) *ProductImageServiceImpl {
+	return &ProductImageServiceImpl{DB: db, Store: store}
+}
+
+// AddImages validates the uploads, stores each with a thumbnail and appends them to the gallery.
+// Either every upload is added or none is.
+func (s *ProductImageServiceImpl) AddImages(actor service.Actor, productID uint, uploads []service.ImageUpload) ([]models.ProductImage, error) {
+	ctx := context.Background()
+	if _, err := gorm.G[models.Product](s.DB).Where("id = ?", productID).First(ctx); err != nil {
+		return nil, err
+	}
+
+	existing, err := gorm.G[models.ProductImage](s.DB).Where("product_id = ?", productID).Count(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	if int(existing)+len(uploads) > service.MaxProductImages {
+		return nil, service.ErrTooManyImages
+	}
+
+	// Validate everything before touching storage
+	contentTypes := make([]string, len(uploads))
+	thumbnails := make([][]byte, len(uploads))
+	for i, upload := range uploads {
+		if len(upload.Data) > service.MaxProductImageSize {
+			return nil, service.ErrImageTooLarge
+		}
+		contentTypes[i] = http.DetectContentType(upload.Data)
+		if _, ok := imageExtensions[contentTypes[i]]; !ok {
+			return nil, service.ErrImageUnsupportedType
+		}
+		if thumbnails[i], err = imaging.Thumbnail(upload.Data, thumbnailSize); err != nil {
+			return nil, service.ErrImageUnsupportedType
+		}
+	}
+
+	var stored []string
+	images := make([]models.ProductImage, 0, len(uploads))
+	for i, upload := range uploads {
+		name, err := randomName()
+		if err != nil {
+			deleteBlobs(ctx, s.Store, stored)
+			return nil, err
+		}
+
+		key := fmt.Sprintf("products/%d/%s%s", productID, name, imageExtensions[contentTypes[i]])
+		thumbnailKey := fmt.Sprintf("products/%d/%s_thumb.jpg", productID, name)
+
+		if err := s.Store.Put(ctx, key, bytes.NewReader(upload.Data), contentTypes[i]); err != nil {
+			deleteBlobs(ctx, s.Store, stored)
+			return nil, err
+		}
+		stored = append(stored, key)
+
+		if err := s.Store.Put(ctx, thumbnailKey, bytes.NewReader(thumbnails[i]), "image/jpeg"); err != nil {
+			deleteBlobs(ctx, s.Store, stored)
+			return nil, err
+		}
+		stored = append(stored, thumbnailKey)
+
+		images = append(images, models.ProductImage{
+			ProductID:    productID,
+			Key:          key,
+			ThumbnailKey: thumbnailKey,
+			URL:          s.Store.URL(key),
+			ThumbnailURL: s.Store.URL(thumbnailKey),
+			ContentType:  contentTypes[i],
+			Size:         int64(len(upload.Data)),
+		})
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		last, err := gorm.G[models.ProductImage](tx).Where("product_id = ?", productID).Order("position DESC").Limit(1).Find(ctx)
+		if err != nil {
+			return err
+		}
+
+		position := 0
+		if len(last) > 0 {
+			position = last[0].Position + 1
+		}
+
+		for i := range images {
+			images[i].Position = position + i
+		}
+		if err := gorm.G[models.ProductImage](tx).CreateInBatches(ctx, &images, len(images)); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "product.images.add", "product", productID, nil, images)
+	})
+	if err != nil {
+		deleteBlobs(ctx, s.Store, stored)
+		return nil, err
+	}
+
+	return images, nil
+}
+
+func (s *ProductImageServiceImpl) ListImages(productID uint) ([]models.ProductImage, error) {
+	ctx := context.Background()
+	return gorm.G[models.ProductImage](s.DB).Where("product_id = ?", productID).Order("position, id").Find(ctx)
+}
+
+// ReorderImages sets gallery positions to follow imageIDs, which must name every image of the product
+func (s *ProductImageServiceImpl) ReorderImages(actor service.Actor, productID uint, imageIDs []uint) ([]models.ProductImage, error) {
+	ctx := context.Background()
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		images, err := gorm.G[models.ProductImage](tx).Where("product_id = ?", productID).Find(ctx)
+		if err != nil {
+			return err
+		}
+		if len(images) != len(imageIDs) {
+			return service.ErrImageOrderMismatch
+		}
+
+		owned := make(map[uint]bool, len(images))
+		for _, image := range images {
+			owned[image.ID] = true
+		}
+		for position, imageID := range imageIDs {
+			if !owned[imageID] {
+				return service.ErrImageOrderMismatch
+			}
+			delete(owned, imageID) // Reject duplicates
+			if _, err := gorm.G[models.ProductImage](tx).Where("id = ?", imageID).Update(ctx, "position", position); err != nil {
+				return err
+			}
+		}
+		return recordAudit(ctx, tx, actor, "product.images.reorder", "product", productID, nil, map[string][]uint{"image_ids": imageIDs})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ListImages(productID)
+}
+
+// DeleteImage removes one image from the gallery and its files from the blob store
+func (s *ProductImageServiceImpl) DeleteImage(actor service.Actor, productID, imageID uint) error {
+	ctx := context.Background()
+	image, err := gorm.G[models.ProductImage](s.DB).Where("id = ? AND product_id = ?", imageID, productID).First(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := gorm.G[models.ProductImage](tx).Where("id = ?", image.ID).Delete(ctx); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "product.images.delete", "product", productID, image, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	deleteBlobs(ctx, s.Store, []string{image.Key, image.ThumbnailKey})
+	return nil
+}
+
+// deleteBlobs removes stored files on a best-effort basis; leftovers are logged rather than failing the request
+func deleteBlobs(ctx context.Context, store storage.BlobStore, keys []string) {
+	for _, key := range keys {
+		if err := store.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete blob %s: %v", key, err)
+		}
+	}
+}
+
+// randomName returns a random hex string used to build unguessable blob keys
+func randomName() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
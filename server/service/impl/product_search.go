@@ -0,0 +1,410 @@
+package impl
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"estore-server/models"
+	"estore-server/search"
+	"estore-server/service"
+
+	"gorm.io/gorm"
+)
+
+// searchStateID is the ID of the only models.SearchIndexState row
+const searchStateID = 1
+
+// productCursor records the sort key of the last product on a page. Pages continue strictly
+// after it, so inserts and deletes between requests never cause skipped or repeated rows.
+type productCursor struct {
+	Sort      service.ProductSort `json:"o"`
+	Price     int                 `json:"p,omitempty"`
+	CreatedAt time.Time           `json:"c,omitzero"`
+	Score     float64             `json:"s,omitempty"`
+	ID        uint                `json:"id"`
+}
+
+func encodeProductCursor(cursor productCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeProductCursor(raw string, sort service.ProductSort) (*productCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, service.ErrInvalidCursor
+	}
+
+	var cursor productCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == 0 || cursor.Sort != sort {
+		return nil, service.ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// searchDocument extracts the indexed text of a product
+func searchDocument(product *models.Product) search.Document {
+	return search.Document{
+		ID:          product.ID,
+		Name:        product.Name,
+		Description: product.Description,
+	}
+}
+
+// SearchProducts returns one page of products matching the filter along with the total match count.
+// Keywords are resolved through the full-text index; all other filters run in the database.
+func (s *ProductServiceImpl) SearchProducts(filter service.ProductFilter) (*service.ProductPage, error) {
+	ctx := context.Background()
+	keyword := strings.TrimSpace(filter.Keyword)
+
+	sort := filter.Sort
+	if sort == "" || (sort == service.ProductSortRelevance && keyword == "") {
+		// Relevance is meaningless without a keyword
+		if keyword != "" {
+			sort = service.ProductSortRelevance
+		} else {
+			sort = service.ProductSortCreatedAtDesc
+		}
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = service.DefaultProductPageSize
+	}
+	limit = min(limit, service.MaxProductPageSize)
+
+	var cursor *productCursor
+	if filter.Cursor != "" {
+		var err error
+		if cursor, err = decodeProductCursor(filter.Cursor, sort); err != nil {
+			return nil, err
+		}
+	}
+
+	// Listings of suspended sellers are hidden until the suspension ends
+	query := gorm.G[models.Product](s.DB).
+		Where("user_id NOT IN (SELECT user_id FROM suspensions WHERE "+activeSuspensionCondition+")", time.Now())
+
+	if !filter.IncludeOutOfStock {
+		query = query.Where("stock > 0")
+	}
+	if len(filter.CategoryIDs) > 0 {
+		query = query.Where("category_id IN ?", filter.CategoryIDs)
+	}
+	if filter.SellerID != 0 {
+		query = query.Where("user_id = ?", filter.SellerID)
+	}
+	if filter.MinPrice != nil {
+		query = query.Where("price >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		query = query.Where("price <= ?", *filter.MaxPrice)
+	}
+
+	if keyword == "" {
+		return s.searchSorted(ctx, query, [][]uint{nil}, sort, cursor, limit)
+	}
+
+	// Every match is considered, so narrow filters still find products the index ranks low
+	hits := s.Index.Search(keyword, s.Index.Len())
+	if len(hits) == 0 {
+		return &service.ProductPage{Products: []models.Product{}}, nil
+	}
+	if sort == service.ProductSortRelevance {
+		return s.searchByRelevance(ctx, query, hits, cursor, limit)
+	}
+
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	return s.searchSorted(ctx, query, slices.Collect(slices.Chunk(ids, searchBatchSize)), sort, cursor, limit)
+}
+
+// searchBatchSize bounds how many keyword matches go into a single "id IN ?" condition
+const searchBatchSize = 1000
+
+// searchSorted pages through the products matching query, restricted to each batch of IDs in
+// turn (a nil batch means no restriction), in the order of sort. The best rows of every batch
+// are merged, so the page and the total are exact however many batches there are.
+func (s *ProductServiceImpl) searchSorted(ctx context.Context, query gorm.ChainInterface[models.Product], batches [][]uint, sort service.ProductSort, cursor *productCursor, limit int) (*service.ProductPage, error) {
+	var compare func(a, b models.Product) int
+	after := query
+	switch sort {
+	case service.ProductSortPriceAsc:
+		if cursor != nil {
+			after = after.Where("price > ? OR (price = ? AND id > ?)", cursor.Price, cursor.Price, cursor.ID)
+		}
+		after = after.Order("price ASC, id ASC")
+		compare = func(a, b models.Product) int {
+			return cmp.Or(cmp.Compare(a.Price, b.Price), cmp.Compare(a.ID, b.ID))
+		}
+	case service.ProductSortPriceDesc:
+		if cursor != nil {
+			after = after.Where("price < ? OR (price = ? AND id < ?)", cursor.Price, cursor.Price, cursor.ID)
+		}
+		after = after.Order("price DESC, id DESC")
+		compare = func(a, b models.Product) int {
+			return cmp.Or(cmp.Compare(b.Price, a.Price), cmp.Compare(b.ID, a.ID))
+		}
+	case service.ProductSortCreatedAtAsc:
+		if cursor != nil {
+			after = after.Where("created_at > ? OR (created_at = ? AND id > ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+		}
+		after = after.Order("created_at ASC, id ASC")
+		compare = func(a, b models.Product) int {
+			return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+		}
+	case service.ProductSortCreatedAtDesc:
+		if cursor != nil {
+			after = after.Where("created_at < ? OR (created_at = ? AND id < ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+		}
+		after = after.Order("created_at DESC, id DESC")
+		compare = func(a, b models.Product) int {
+			return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+		}
+	default:
+		return nil, service.ErrInvalidSort
+	}
+
+	var total int64
+	var products []models.Product
+	for _, batch := range batches {
+		matches, pageQuery := query, after
+		if batch != nil {
+			matches = matches.Where("id IN ?", batch)
+			pageQuery = pageQuery.Where("id IN ?", batch)
+		}
+
+		count, err := matches.Count(ctx, "id")
+		if err != nil {
+			return nil, err
+		}
+		total += count
+
+		// Fetch one extra row to learn whether another page exists
+		rows, err := pageQuery.Preload("User", nil).Preload("Images", preloadImages).Limit(limit + 1).Find(ctx)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, rows...)
+	}
+	slices.SortFunc(products, compare)
+	products = products[:min(len(products), limit+1)]
+
+	page := &service.ProductPage{Products: products, Total: total}
+	if len(products) > limit {
+		page.Products = products[:limit]
+		last := &page.Products[limit-1]
+		page.NextCursor = encodeProductCursor(productCursor{
+			Sort:      sort,
+			Price:     last.Price,
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+	}
+
+	return page, nil
+}
+
+// searchByRelevance pages through keyword matches ordered by index score. Scores live in the
+// index rather than the database, so the ranked hits are checked against the other filters a
+// batch at a time until the page is full.
+func (s *ProductServiceImpl) searchByRelevance(ctx context.Context, query gorm.ChainInterface[models.Product], hits []search.Hit, cursor *productCursor, limit int) (*service.ProductPage, error) {
+	slices.SortFunc(hits, func(a, b search.Hit) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(b.ID, a.ID))
+	})
+
+	start := 0
+	if cursor != nil {
+		// Skip everything ranked at or above the cursor position
+		for start < len(hits) {
+			hit := hits[start]
+			if hit.Score < cursor.Score || (hit.Score == cursor.Score && hit.ID < cursor.ID) {
+				break
+			}
+			start++
+		}
+	}
+
+	var ranked []search.Hit
+	for batch := range slices.Chunk(hits[start:], searchBatchSize) {
+		ids := make([]uint, 0, len(batch))
+		for _, hit := range batch {
+			ids = append(ids, hit.ID)
+		}
+		matched, err := query.Select("id").Where("id IN ?", ids).Find(ctx)
+		if err != nil {
+			return nil, err
+		}
+		kept := make(map[uint]bool, len(matched))
+		for _, product := range matched {
+			kept[product.ID] = true
+		}
+
+		for _, hit := range batch {
+			if kept[hit.ID] && len(ranked) <= limit {
+				ranked = append(ranked, hit)
+			}
+		}
+		if len(ranked) > limit {
+			break
+		}
+	}
+
+	var total int64
+	for batch := range slices.Chunk(hits, searchBatchSize) {
+		ids := make([]uint, 0, len(batch))
+		for _, hit := range batch {
+			ids = append(ids, hit.ID)
+		}
+		count, err := query.Where("id IN ?", ids).Count(ctx, "id")
+		if err != nil {
+			return nil, err
+		}
+		total += count
+	}
+
+	page := &service.ProductPage{Products: []models.Product{}, Total: total}
+	pageHits := ranked[:min(len(ranked), limit)]
+	if len(pageHits) == 0 {
+		return page, nil
+	}
+
+	pageIDs := make([]uint, 0, len(pageHits))
+	for _, hit := range pageHits {
+		pageIDs = append(pageIDs, hit.ID)
+	}
+	products, err := gorm.G[models.Product](s.DB).Preload("User", nil).Preload("Images", preloadImages).Where("id IN ?", pageIDs).Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]models.Product, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+	for _, id := range pageIDs {
+		if product, ok := byID[id]; ok {
+			page.Products = append(page.Products, product)
+		}
+	}
+
+	if len(ranked) > limit {
+		last := pageHits[len(pageHits)-1]
+		page.NextCursor = encodeProductCursor(productCursor{
+			Sort:  service.ProductSortRelevance,
+			Score: last.Score,
+			ID:    last.ID,
+		})
+	}
+
+	return page, nil
+}
+
+// RebuildSearchIndex reindexes every product from the database and reports how many were indexed
+func (s *ProductServiceImpl) RebuildSearchIndex(actor service.Actor) (int, error) {
+	ctx := context.Background()
+	count, err := s.rebuildIndex(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := recordAudit(ctx, s.DB, actor, "search.rebuild", "search_index", "products", nil, map[string]int{"indexed": count}); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (s *ProductServiceImpl) RequestSearchRebuild(actor service.Actor) (int64, error) {
+	ctx := context.Background()
+	var generation int64
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		rows, err := gorm.G[models.SearchIndexState](tx).Where("id = ?", searchStateID).
+			Update(ctx, "generation", gorm.Expr("generation + 1"))
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		state, err := gorm.G[models.SearchIndexState](tx).Where("id = ?", searchStateID).First(ctx)
+		if err != nil {
+			return err
+		}
+		generation = state.Generation
+		return recordAudit(ctx, tx, actor, "search.rebuild_request", "search_index", "products", nil, map[string]int64{"generation": generation})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return generation, nil
+}
+
+// SearchGeneration returns the current rebuild generation
+func (s *ProductServiceImpl) SearchGeneration(ctx context.Context) (int64, error) {
+	state, err := gorm.G[models.SearchIndexState](s.DB).Where("id = ?", searchStateID).First(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return state.Generation, nil
+}
+
+// WatchSearchIndex keeps this server's index in step with the other servers until ctx is
+// done. Every poll it rebuilds the index if the generation has moved on from the one the
+// index was built at; with refresh above zero it also rebuilds that often, which picks up
+// products that other servers created, changed or deleted.
+func (s *ProductServiceImpl) WatchSearchIndex(ctx context.Context, generation int64, poll, refresh time.Duration) {
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	builtAt := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current, err := s.SearchGeneration(ctx)
+		if err != nil {
+			log.Printf("search: failed to read the rebuild generation: %v", err)
+			continue
+		}
+		if current == generation && (refresh <= 0 || time.Since(builtAt) < refresh) {
+			continue
+		}
+
+		count, err := s.rebuildIndex(ctx)
+		if err != nil {
+			log.Printf("search: failed to rebuild the index: %v", err)
+			continue
+		}
+		if current != generation {
+			log.Printf("search: rebuilt the index with %d products for generation %d", count, current)
+		}
+		generation, builtAt = current, time.Now()
+	}
+}
+
+// rebuildIndex replaces the index's contents with every product in the database
+func (s *ProductServiceImpl) rebuildIndex(ctx context.Context) (int, error) {
+	var docs []search.Document
+	err := gorm.G[models.Product](s.DB).Select("id", "name", "description").FindInBatches(ctx, 500, func(products []models.Product, batch int) error {
+		for i := range products {
+			docs = append(docs, searchDocument(&products[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.Index.Rebuild(docs)
+	return len(docs), nil
+}
@@ -2,45 +2,72 @@ package impl
 
 import (
 	"context"
-	"strings"
+	"fmt"
 
 	"estore-server/models"
+	"estore-server/search"
 	"estore-server/service"
+	"estore-server/storage"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// ProductServiceImpl provides product persistence operations
+// ProductServiceImpl provides product persistence operations and keeps the search index in sync
 type ProductServiceImpl struct {
-	DB *gorm.DB
+	DB            *gorm.DB
+	Store         storage.BlobStore
+	Index         search.SearchIndex
+	Notifications service.NotificationService
 }
 
 var _ service.ProductService = (*ProductServiceImpl)(nil)
 
-func NewProductServiceImpl(db *gorm.DB) *ProductServiceImpl {
-	return &ProductServiceImpl{DB: db}
+func NewProductServiceImpl(db *gorm.DB, store storage.BlobStore, index search.SearchIndex, notifications service.NotificationService) *ProductServiceImpl {
+	return &ProductServiceImpl{DB: db, Store: store, Index: index, Notifications: notifications}
 }
 
-func (s *ProductServiceImpl) CreateProduct(userID uint, name, description string, price int) (*models.Product, error) {
+// preloadImages loads a product's gallery in display order
+func preloadImages(db gorm.PreloadBuilder) error {
+	db.Order("position, id")
+	return nil
+}
+
+func (s *ProductServiceImpl) CreateProduct(actor service.Actor, userID uint, name, description string, price, stock int, categoryID *uint) (*models.Product, error) {
 	ctx := context.Background()
+	if err := s.checkCategory(ctx, categoryID); err != nil {
+		return nil, err
+	}
 
 	product := &models.Product{
 		UserID:      userID,
 		Name:        name,
 		Description: description,
 		Price:       price,
+		Stock:       stock,
+		CategoryID:  categoryID,
 	}
 
-	if err := gorm.G[models.Product](s.DB).Create(ctx, product); err != nil {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := gorm.G[models.Product](tx).Create(ctx, product); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, actor, "product.create", "product", product.ID, nil, product); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, models.EventProductCreated, newProductEventData(product))
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	s.Index.Index(searchDocument(product))
 	return product, nil
 }
 
 func (s *ProductServiceImpl) GetProduct(productID uint) (*models.Product, error) {
 	ctx := context.Background()
-	product, err := gorm.G[models.Product](s.DB).Preload("User", nil).Where("id = ?", productID).First(ctx)
+	product, err := gorm.G[models.Product](s.DB).Preload("User", nil).Preload("Images", preloadImages).Where("id = ?", productID).First(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -48,41 +75,162 @@ func (s *ProductServiceImpl) GetProduct(productID uint) (*models.Product, error)
 	return &product, nil
 }
 
-func (s *ProductServiceImpl) UpdateProduct(productID uint, name, description string, price int) (*models.Product, error) {
+func (s *ProductServiceImpl) UpdateProduct(actor service.Actor, productID uint, update service.ProductUpdate) (*models.Product, error) {
 	ctx := context.Background()
-	product, err := gorm.G[models.Product](s.DB).Preload("User", nil).Where("id = ?", productID).First(ctx)
-	if err != nil {
+	if err := s.checkCategory(ctx, update.CategoryID); err != nil {
 		return nil, err
 	}
 
-	product.Name = name
-	product.Description = description
-	product.Price = price
-
-	if _, err := gorm.G[models.Product](s.DB).Updates(ctx, product); err != nil {
+	var product models.Product
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock the row so a checkout cannot reserve stock between this read and the write
+		current, err := gorm.G[models.Product](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", productID).First(ctx)
+		if err != nil {
+			return err
+		}
+		before := current
+
+		current.Name = update.Name
+		current.Description = update.Description
+		current.Price = update.Price
+		// Select the edited columns so zero values such as a sold-out stock are persisted
+		columns := []any{"name", "description", "price"}
+		switch {
+		case update.ClearCategory:
+			current.CategoryID = nil
+			columns = append(columns, "category_id")
+		case update.CategoryID != nil:
+			current.CategoryID = update.CategoryID
+			columns = append(columns, "category_id")
+		}
+		if update.Stock != nil {
+			current.Stock = *update.Stock
+			columns = append(columns, "stock")
+		}
+		if _, err := gorm.G[models.Product](tx).Select(columns[0].(string), columns[1:]...).Updates(ctx, current); err != nil {
+			return err
+		}
+
+		product, err = gorm.G[models.Product](tx).Preload("User", nil).Preload("Images", preloadImages).Where("id = ?", productID).First(ctx)
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, actor, "product.update", "product", productID, before, product); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, models.EventProductUpdated, newProductEventData(&product))
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	s.Index.Index(searchDocument(&product))
 	return &product, nil
 }
 
-func (s *ProductServiceImpl) DeleteProduct(productID uint) error {
+// DeleteProduct moves a product to the trash, clears it from every watchlist and drops it
+// from the search index. The gallery is kept so the product can be restored; the trash purge
+// removes images and files for good.
+func (s *ProductServiceImpl) DeleteProduct(actor service.Actor, productID uint) error {
 	ctx := context.Background()
-	if _, err := gorm.G[models.Product](s.DB).Where("id = ?", productID).Delete(ctx); err != nil {
+	var product models.Product
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		product, err = gorm.G[models.Product](tx).Where("id = ?", productID).First(ctx)
+		if err != nil {
+			return err
+		}
+
+		rows, err := gorm.G[models.Product](tx).Where("id = ?", productID).Delete(ctx)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if _, err := gorm.G[models.Favorite](tx).Where("product_id = ?", productID).Delete(ctx); err != nil {
+			return err
+		}
+		// A restored product starts without favorites, so its count has to match
+		if _, err := gorm.G[models.Product](tx).Scopes(withTrashed).Where("id = ?", productID).Update(ctx, "favorite_count", 0); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, actor, "product.delete", "product", productID, product, nil); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, models.EventProductDeleted, deletedEventData{ID: productID})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.Index.Remove(productID)
+
+	if actor.UserID == nil || *actor.UserID != product.UserID {
+		notify(s.Notifications, models.Notification{
+			UserID:     product.UserID,
+			Type:       models.NotificationProductRemoved,
+			Message:    fmt.Sprintf("Your listing %q was removed by a moderator.", product.Name),
+			TargetType: "product",
+			TargetID:   productID,
+		})
+	}
+	return nil
+}
+
+// checkCategory verifies that an optional category reference points to an existing category
+func (s *ProductServiceImpl) checkCategory(ctx context.Context, categoryID *uint) error {
+	if categoryID == nil {
+		return nil
+	}
+
+	count, err := gorm.G[models.Category](s.DB).Where("id = ?", *categoryID).Count(ctx, "id")
+	if err != nil {
 		return err
 	}
+	if count == 0 {
+		return service.ErrCategoryNotFound
+	}
 	return nil
 }
 
-func (s *ProductServiceImpl) SearchProducts(keyword string) ([]models.Product, error) {
+// ReserveStock takes quantity items out of a product's stock
+func (s *ProductServiceImpl) ReserveStock(productID uint, quantity int) error {
 	ctx := context.Background()
-	keyword = strings.TrimSpace(keyword)
-	baseQuery := gorm.G[models.Product](s.DB).Preload("User", nil)
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		return reserveStock(ctx, tx, productID, quantity)
+	})
+}
+
+// ReleaseStock puts quantity items back into a product's stock, e.g. after a cancelled order
+func (s *ProductServiceImpl) ReleaseStock(productID uint, quantity int) error {
+	ctx := context.Background()
+	return releaseStock(ctx, s.DB, productID, quantity)
+}
 
-	if keyword != "" {
-		like := "%" + strings.ToLower(keyword) + "%"
-		return baseQuery.Where("LOWER(name) LIKE ? OR LOWER(description) LIKE ?", like, like).Find(ctx)
+// reserveStock decrements stock inside tx. The product row is locked with SELECT ... FOR UPDATE
+// so concurrent buyers are serialised and stock can never go negative.
+func reserveStock(ctx context.Context, tx *gorm.DB, productID uint, quantity int) error {
+	if quantity <= 0 {
+		return service.ErrInsufficientStock
 	}
 
-	return baseQuery.Find(ctx)
+	product, err := gorm.G[models.Product](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", productID).First(ctx)
+	if err != nil {
+		return err
+	}
+
+	if product.Stock < quantity {
+		return service.ErrInsufficientStock
+	}
+
+	_, err = gorm.G[models.Product](tx).Where("id = ?", productID).Update(ctx, "stock", gorm.Expr("stock - ?", quantity))
+	return err
+}
+
+// releaseStock increments stock; products deleted in the meantime are silently skipped
+func releaseStock(ctx context.Context, tx *gorm.DB, productID uint, quantity int) error {
+	_, err := gorm.G[models.Product](tx).Where("id = ?", productID).Update(ctx, "stock", gorm.Expr("stock + ?", quantity))
+	return err
 }
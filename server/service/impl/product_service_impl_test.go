@@ -0,0 +1,156 @@
+package impl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"estore-server/models"
+	"estore-server/search"
+	"estore-server/service"
+	"estore-server/testdb"
+
+	"gorm.io/gorm"
+)
+
+func TestUpdateProductCategory(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+
+		category := &models.Category{Name: "Lighting", Slug: "lighting"}
+		if err := gorm.G[models.Category](db).Create(ctx, category); err != nil {
+			t.Fatal(err)
+		}
+		seller := createTestUser(t, db, "seller")
+		product := createTestProduct(t, db, seller, 100, 1)
+		products := NewProductServiceImpl(db, nil, search.NewMemoryIndex(), nil)
+
+		updated, err := products.UpdateProduct(service.UserActor(seller.ID), product.ID, service.ProductUpdate{Name: "Lamp", Price: 100, CategoryID: &category.ID})
+		if err != nil {
+			t.Fatalf("set category: %v", err)
+		}
+		if updated.CategoryID == nil || *updated.CategoryID != category.ID {
+			t.Fatalf("category is %v, want %d", updated.CategoryID, category.ID)
+		}
+
+		updated, err = products.UpdateProduct(service.UserActor(seller.ID), product.ID, service.ProductUpdate{Name: "Lamp", Price: 120})
+		if err != nil {
+			t.Fatalf("update without category: %v", err)
+		}
+		if updated.CategoryID == nil || *updated.CategoryID != category.ID {
+			t.Errorf("omitting the category changed it to %v", updated.CategoryID)
+		}
+
+		updated, err = products.UpdateProduct(service.UserActor(seller.ID), product.ID, service.ProductUpdate{Name: "Lamp", Price: 120, ClearCategory: true})
+		if err != nil {
+			t.Fatalf("clear category: %v", err)
+		}
+		if updated.CategoryID != nil {
+			t.Errorf("category is %d, want none", *updated.CategoryID)
+		}
+	})
+}
+
+func TestKeywordSearchFiltersEveryMatch(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		// The niche seller's one product ranks below more than a search batch of others
+		niche := createTestUser(t, db, "niche")
+		seller := createTestUser(t, db, "seller")
+		index := search.NewMemoryIndex()
+		products := []models.Product{{Name: "Lamp", Price: 100, Stock: 1, UserID: niche.ID}}
+		for range searchBatchSize + 100 {
+			products = append(products, models.Product{Name: "Lamp", Price: 100, Stock: 1, UserID: seller.ID})
+		}
+		if err := db.Omit("User").CreateInBatches(&products, 200).Error; err != nil {
+			t.Fatal(err)
+		}
+		for i := range products {
+			index.Index(searchDocument(&products[i]))
+		}
+		productService := NewProductServiceImpl(db, nil, index, nil)
+
+		for _, sort := range []service.ProductSort{service.ProductSortRelevance, service.ProductSortPriceAsc} {
+			page, err := productService.SearchProducts(service.ProductFilter{Keyword: "lamp", SellerID: niche.ID, Sort: sort})
+			if err != nil {
+				t.Fatalf("search by %s: %v", sort, err)
+			}
+			if len(page.Products) != 1 || page.Products[0].ID != products[0].ID || page.Total != 1 {
+				t.Errorf("search by %s found %d products, total %d; want the niche seller's lamp", sort, len(page.Products), page.Total)
+			}
+		}
+
+		// Every match is reachable by paging through relevance order
+		seen := make(map[uint]bool)
+		filter := service.ProductFilter{Keyword: "lamp", Sort: service.ProductSortRelevance, Limit: service.MaxProductPageSize}
+		for {
+			page, err := productService.SearchProducts(filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, product := range page.Products {
+				seen[product.ID] = true
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			filter.Cursor = page.NextCursor
+		}
+		if len(seen) != len(products) {
+			t.Errorf("paged through %d products, want %d", len(seen), len(products))
+		}
+	})
+}
+
+func TestEveryServerPicksUpProductsWrittenElsewhere(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Two servers on one database, each with its own index
+		seller := createTestUser(t, db, "seller")
+		writer := NewProductServiceImpl(db, nil, search.NewMemoryIndex(), nil)
+		requested := search.NewMemoryIndex()
+		refreshed := search.NewMemoryIndex()
+		for _, index := range []*search.MemoryIndex{requested, refreshed} {
+			reader := NewProductServiceImpl(db, nil, index, nil)
+			generation, err := reader.SearchGeneration(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			refresh := time.Duration(0)
+			if index == refreshed {
+				refresh = 50 * time.Millisecond
+			}
+			go reader.WatchSearchIndex(ctx, generation, 10*time.Millisecond, refresh)
+		}
+
+		if _, err := writer.CreateProduct(service.UserActor(seller.ID), seller.ID, "台灯", "", 100, 1, nil); err != nil {
+			t.Fatalf("create product: %v", err)
+		}
+
+		// The periodic refresh finds the product by itself
+		waitForIndex(t, refreshed, "台灯")
+
+		// The other server only rebuilds once asked
+		time.Sleep(100 * time.Millisecond)
+		if requested.Len() != 0 {
+			t.Fatal("server rebuilt its index without a refresh interval or a request")
+		}
+		if _, err := writer.RequestSearchRebuild(service.SystemActor("cli:search")); err != nil {
+			t.Fatalf("request rebuild: %v", err)
+		}
+		waitForIndex(t, requested, "台灯")
+	})
+}
+
+// waitForIndex waits until a search for query finds something in index
+func waitForIndex(t *testing.T, index search.SearchIndex, query string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(index.Search(query, 1)) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("index never found %q", query)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,221 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"estore-server/models"
+	"estore-server/service"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReviewServiceImpl stores reviews and updates the seller's rating_count and rating_sum in
+// the same transaction, so the aggregate on the user row is always consistent
+type ReviewServiceImpl struct {
+	DB            *gorm.DB
+	Notifications service.NotificationService
+}
+
+var _ service.ReviewService = (*ReviewServiceImpl)(nil)
+
+func NewReviewServiceImpl(db *gorm.DB, notifications service.NotificationService) *ReviewServiceImpl {
+	return &ReviewServiceImpl{DB: db, Notifications: notifications}
+}
+
+// CreateReview records the reviewer's rating of a seller; a seller can be reviewed once per user
+func (s *ReviewServiceImpl) CreateReview(actor service.Actor, reviewerID, sellerID uint, rating int, comment string) (*models.Review, error) {
+	if reviewerID == sellerID {
+		return nil, service.ErrSelfReview
+	}
+	if !validRating(rating) {
+		return nil, service.ErrInvalidRating
+	}
+
+	ctx := context.Background()
+	review := &models.Review{
+		SellerID:   sellerID,
+		ReviewerID: reviewerID,
+		Rating:     rating,
+		Comment:    comment,
+	}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		// Locking the seller serialises reviews of the same seller, which guards both the
+		// one-review rule and the aggregate
+		if _, err := gorm.G[models.User](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", sellerID).First(ctx); err != nil {
+			return err
+		}
+
+		count, err := gorm.G[models.Review](tx).Where("seller_id = ? AND reviewer_id = ?", sellerID, reviewerID).Count(ctx, "id")
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return service.ErrReviewExists
+		}
+
+		if err := gorm.G[models.Review](tx).Create(ctx, review); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, actor, "review.create", "review", review.ID, nil, review); err != nil {
+			return err
+		}
+		return adjustSellerRating(ctx, tx, sellerID, 1, rating)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notify(s.Notifications, models.Notification{
+		UserID:     sellerID,
+		Type:       models.NotificationReviewReceived,
+		Message:    fmt.Sprintf("You received a %d-star review.", rating),
+		TargetType: "review",
+		TargetID:   review.ID,
+	})
+	return review, nil
+}
+
+// UpdateReview changes the rating and comment of the reviewer's own review
+func (s *ReviewServiceImpl) UpdateReview(actor service.Actor, reviewerID, reviewID uint, rating int, comment string) (*models.Review, error) {
+	if !validRating(rating) {
+		return nil, service.ErrInvalidRating
+	}
+
+	ctx := context.Background()
+	var review models.Review
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		review, err = lockReview(ctx, tx, reviewID)
+		if err != nil {
+			return err
+		}
+		if review.ReviewerID != reviewerID {
+			return service.ErrReviewNotAllowed
+		}
+
+		before := review
+		delta := rating - review.Rating
+		review.Rating = rating
+		review.Comment = comment
+		if _, err := gorm.G[models.Review](tx).Where("id = ?", reviewID).Select("rating", "comment").Updates(ctx, review); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, actor, "review.update", "review", reviewID, before, review); err != nil {
+			return err
+		}
+		return adjustSellerRating(ctx, tx, review.SellerID, 0, delta)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+// DeleteReview removes the reviewer's own review and takes it out of the seller's rating
+func (s *ReviewServiceImpl) DeleteReview(actor service.Actor, reviewerID, reviewID uint) error {
+	ctx := context.Background()
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		review, err := lockReview(ctx, tx, reviewID)
+		if err != nil {
+			return err
+		}
+		if review.ReviewerID != reviewerID {
+			return service.ErrReviewNotAllowed
+		}
+
+		if _, err := gorm.G[models.Review](tx).Where("id = ?", reviewID).Delete(ctx); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, actor, "review.delete", "review", reviewID, review, nil); err != nil {
+			return err
+		}
+		return adjustSellerRating(ctx, tx, review.SellerID, -1, -review.Rating)
+	})
+}
+
+// ReplyToReview attaches the seller's public reply to a review of them; it cannot be changed afterwards
+func (s *ReviewServiceImpl) ReplyToReview(actor service.Actor, sellerID, reviewID uint, reply string) (*models.Review, error) {
+	ctx := context.Background()
+	review, err := gorm.G[models.Review](s.DB).Where("id = ?", reviewID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if review.SellerID != sellerID {
+		return nil, service.ErrReviewNotAllowed
+	}
+
+	now := time.Now()
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		// The replied_at check makes the first reply win if the seller answers twice at once
+		rows, err := gorm.G[models.Review](tx).Where("id = ? AND replied_at IS NULL", reviewID).
+			Updates(ctx, models.Review{Reply: reply, RepliedAt: &now})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return service.ErrReplyExists
+		}
+
+		review.Reply = reply
+		review.RepliedAt = &now
+		return recordAudit(ctx, tx, actor, "review.reply", "review", reviewID, nil, review)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// ListReviews returns one page of the seller's reviews with their authors, newest first
+func (s *ReviewServiceImpl) ListReviews(sellerID uint, cursor string, limit int) (*service.ReviewPage, error) {
+	ctx := context.Background()
+	if limit <= 0 {
+		limit = service.DefaultReviewPageSize
+	}
+	limit = min(limit, service.MaxReviewPageSize)
+
+	if _, err := gorm.G[models.User](s.DB).Where("id = ?", sellerID).First(ctx); err != nil {
+		return nil, err
+	}
+
+	query := gorm.G[models.Review](s.DB).Where("seller_id = ?", sellerID)
+	total, err := query.Count(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, next, err := paginateByID(ctx, query.Preload("Reviewer", nil), cursor, limit, func(review *models.Review) uint { return review.ID })
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.ReviewPage{Reviews: reviews, Total: total, NextCursor: next}, nil
+}
+
+func validRating(rating int) bool {
+	return rating >= service.MinReviewRating && rating <= service.MaxReviewRating
+}
+
+// lockReview loads a review with SELECT ... FOR UPDATE so concurrent edits apply their
+// rating deltas one after the other
+func lockReview(ctx context.Context, tx *gorm.DB, reviewID uint) (models.Review, error) {
+	return gorm.G[models.Review](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", reviewID).First(ctx)
+}
+
+// adjustSellerRating shifts the seller's review count and rating sum by the given deltas
+func adjustSellerRating(ctx context.Context, tx *gorm.DB, sellerID uint, countDelta, sumDelta int) error {
+	if countDelta == 0 && sumDelta == 0 {
+		return nil
+	}
+
+	// Trashed sellers keep their aggregate, so the update has to reach them too
+	_, err := gorm.G[models.User](tx).Scopes(withTrashed).Where("id = ?", sellerID).Set(clause.Assignments(map[string]any{
+		"rating_count": gorm.Expr("rating_count + ?", countDelta),
+		"rating_sum":   gorm.Expr("rating_sum + ?", sumDelta),
+	})).Update(ctx)
+	return err
+}
@@ -0,0 +1,200 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"estore-server/models"
+	"estore-server/service"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// rolePermissionCache maps role name to its permissions. Role permissions only change
+// when roles are seeded, so every request can be authorised without a query.
+var rolePermissionCache struct {
+	sync.RWMutex
+	roles map[string]map[string]bool
+}
+
+// RoleServiceImpl stores roles and permissions in the database
+type RoleServiceImpl struct {
+	DB *gorm.DB
+}
+
+var _ service.RoleService = (*RoleServiceImpl)(nil)
+
+func NewRoleServiceImpl(db *gorm.DB) *RoleServiceImpl {
+	return &RoleServiceImpl{DB: db}
+}
+
+// SeedDefaultRoles makes the database match models.DefaultRolePermissions
+func (s *RoleServiceImpl) SeedDefaultRoles() error {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		for name, permissionNames := range models.DefaultRolePermissions {
+			role := models.Role{Name: name}
+			if err := tx.Where("name = ?", name).FirstOrCreate(&role).Error; err != nil {
+				return err
+			}
+
+			permissions := make([]models.Permission, 0, len(permissionNames))
+			for _, permissionName := range permissionNames {
+				permission := models.Permission{Name: permissionName}
+				if err := tx.Where("name = ?", permissionName).FirstOrCreate(&permission).Error; err != nil {
+					return err
+				}
+				permissions = append(permissions, permission)
+			}
+
+			if err := tx.Model(&role).Association("Permissions").Replace(permissions); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	rolePermissionCache.Lock()
+	rolePermissionCache.roles = nil
+	rolePermissionCache.Unlock()
+	return nil
+}
+
+// ListRoles returns every role with its permissions
+func (s *RoleServiceImpl) ListRoles() ([]models.Role, error) {
+	return gorm.G[models.Role](s.DB).Preload("Permissions", nil).Order("id").Find(context.Background())
+}
+
+// GetUserRoles returns the roles held by the user
+func (s *RoleServiceImpl) GetUserRoles(userID uint) ([]models.Role, error) {
+	user, err := gorm.G[models.User](s.DB).Preload("Roles", nil).Where("id = ?", userID).First(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return user.Roles, nil
+}
+
+// AssignRole grants the role to the user; assigning a role the user already holds is a no-op.
+// Permissions are resolved from the database on every request, so the role applies to the
+// user's existing sessions straight away.
+func (s *RoleServiceImpl) AssignRole(actor service.Actor, userID uint, roleName string) error {
+	ctx := context.Background()
+	if _, err := gorm.G[models.User](s.DB).Where("id = ?", userID).First(ctx); err != nil {
+		return err
+	}
+	role, err := s.findRole(ctx, roleName)
+	if err != nil {
+		return err
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		assignment := &models.UserRole{UserID: userID, RoleID: role.ID}
+		if err := gorm.G[models.UserRole](tx, clause.OnConflict{DoNothing: true}).Create(ctx, assignment); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "role.assign", "user", userID, nil, map[string]string{"role": role.Name})
+	})
+}
+
+// RemoveRole takes the role away from the user, which applies on their next request, and
+// also logs out their sessions
+func (s *RoleServiceImpl) RemoveRole(actor service.Actor, userID uint, roleName string) error {
+	ctx := context.Background()
+	role, err := s.findRole(ctx, roleName)
+	if err != nil {
+		return err
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if role.Name == models.RoleAdmin {
+			// Lock the admin assignments so two admins cannot demote each other concurrently
+			admins, err := gorm.G[models.UserRole](tx, clause.Locking{Strength: "UPDATE"}).Where("role_id = ?", role.ID).Find(ctx)
+			if err != nil {
+				return err
+			}
+			if len(admins) == 1 && admins[0].UserID == userID {
+				return service.ErrLastAdmin
+			}
+		}
+
+		rows, err := gorm.G[models.UserRole](tx).Where("user_id = ? AND role_id = ?", userID, role.ID).Delete(ctx)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return nil
+		}
+		if err := recordAudit(ctx, tx, actor, "role.remove", "user", userID, map[string]string{"role": role.Name}, nil); err != nil {
+			return err
+		}
+		return revokeUserSessions(ctx, tx, userID)
+	})
+}
+
+// Permissions returns the union of the permissions granted by the named roles
+func (s *RoleServiceImpl) Permissions(roleNames []string) (map[string]bool, error) {
+	rolePermissionCache.RLock()
+	roles := rolePermissionCache.roles
+	rolePermissionCache.RUnlock()
+
+	if roles == nil {
+		loaded, err := s.loadRolePermissions()
+		if err != nil {
+			return nil, err
+		}
+		rolePermissionCache.Lock()
+		rolePermissionCache.roles = loaded
+		rolePermissionCache.Unlock()
+		roles = loaded
+	}
+
+	permissions := make(map[string]bool)
+	for _, name := range roleNames {
+		for permission := range roles[name] {
+			permissions[permission] = true
+		}
+	}
+	return permissions, nil
+}
+
+func (s *RoleServiceImpl) loadRolePermissions() (map[string]map[string]bool, error) {
+	roles, err := s.ListRoles()
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := make(map[string]map[string]bool, len(roles))
+	for _, role := range roles {
+		permissions := make(map[string]bool, len(role.Permissions))
+		for _, permission := range role.Permissions {
+			permissions[permission.Name] = true
+		}
+		loaded[role.Name] = permissions
+	}
+	return loaded, nil
+}
+
+func (s *RoleServiceImpl) findRole(ctx context.Context, name string) (*models.Role, error) {
+	role, err := gorm.G[models.Role](s.DB).Where("name = ?", name).First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, service.ErrRoleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// assignRole grants a role by name inside an existing transaction
+func assignRole(ctx context.Context, tx *gorm.DB, userID uint, roleName string) error {
+	role, err := gorm.G[models.Role](tx).Where("name = ?", roleName).First(ctx)
+	if err != nil {
+		return err
+	}
+	return gorm.G[models.UserRole](tx, clause.OnConflict{DoNothing: true}).
+		Create(ctx, &models.UserRole{UserID: userID, RoleID: role.ID})
+}
@@ -0,0 +1,202 @@
+package impl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"estore-server/models"
+	"estore-server/service"
+
+	"gorm.io/gorm"
+)
+
+// lastSeenInterval limits how often a validated session writes its last-seen time
+const lastSeenInterval = time.Minute
+
+// SessionServiceImpl stores sessions in the database, keyed by a random session ID
+type SessionServiceImpl struct {
+	DB *gorm.DB
+}
+
+var _ service.SessionService = (*SessionServiceImpl)(nil)
+
+func NewSessionServiceImpl(db *gorm.DB) *SessionServiceImpl {
+	return &SessionServiceImpl{DB: db}
+}
+
+// CreateSession records a new login for the user
+func (s *SessionServiceImpl) CreateSession(userID uint, userAgent, ip string, expiresAt time.Time) (*models.Session, error) {
+	id, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := models.Session{
+		ID:         id,
+		UserID:     userID,
+		UserAgent:  truncate(userAgent, 255),
+		IP:         ip,
+		LastSeenAt: now,
+		ExpiresAt:  expiresAt,
+	}
+	if err := gorm.G[models.Session](s.DB).Create(context.Background(), &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// AttachRefreshToken binds a newly issued refresh token to the session, replacing the
+// previous one so a rotated token can no longer be used
+func (s *SessionServiceImpl) AttachRefreshToken(sessionID, refreshToken string, expiresAt time.Time) error {
+	rows, err := gorm.G[models.Session](s.DB).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Select("refresh_token_hash", "expires_at").
+		Updates(context.Background(), models.Session{RefreshTokenHash: hashToken(refreshToken), ExpiresAt: expiresAt})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return service.ErrSessionInvalid
+	}
+	return nil
+}
+
+// GetByRefreshToken returns the active session owning the refresh token, with its user loaded
+func (s *SessionServiceImpl) GetByRefreshToken(refreshToken string) (*models.Session, error) {
+	session, err := gorm.G[models.Session](s.DB).
+		Preload("User.Roles", nil).
+		Where("refresh_token_hash = ? AND revoked_at IS NULL AND expires_at > ?", hashToken(refreshToken), time.Now()).
+		First(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// RevokeByRefreshToken ends the session owning the refresh token
+func (s *SessionServiceImpl) RevokeByRefreshToken(refreshToken string) error {
+	rows, err := gorm.G[models.Session](s.DB).
+		Where("refresh_token_hash = ? AND revoked_at IS NULL", hashToken(refreshToken)).
+		Update(context.Background(), "revoked_at", time.Now())
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ValidateSession checks that the session belongs to the user and is still active,
+// and records the time and address it was last used from
+func (s *SessionServiceImpl) ValidateSession(sessionID string, userID uint, ip string) (*models.Session, error) {
+	ctx := context.Background()
+	session, err := gorm.G[models.Session](s.DB).Where("id = ? AND user_id = ?", sessionID, userID).First(ctx)
+	if err != nil {
+		return nil, service.ErrSessionInvalid
+	}
+
+	now := time.Now()
+	if !session.IsActive(now) {
+		return nil, service.ErrSessionInvalid
+	}
+
+	if now.Sub(session.LastSeenAt) >= lastSeenInterval || session.IP != ip {
+		session.LastSeenAt = now
+		session.IP = ip
+		if _, err := gorm.G[models.Session](s.DB).Where("id = ?", session.ID).
+			Select("last_seen_at", "ip").
+			Updates(ctx, models.Session{LastSeenAt: now, IP: ip}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &session, nil
+}
+
+// ListSessions returns the user's active sessions, most recently used first
+func (s *SessionServiceImpl) ListSessions(userID uint) ([]models.Session, error) {
+	return gorm.G[models.Session](s.DB).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(context.Background())
+}
+
+// RevokeSession ends one of the user's sessions
+func (s *SessionServiceImpl) RevokeSession(actor service.Actor, userID uint, sessionID string) error {
+	ctx := context.Background()
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		rows, err := gorm.G[models.Session](tx).
+			Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+			Update(ctx, "revoked_at", time.Now())
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return recordAudit(ctx, tx, actor, "session.revoke", "session", sessionID, nil, nil)
+	})
+}
+
+// RevokeOtherSessions ends every session of the user except keepSessionID
+func (s *SessionServiceImpl) RevokeOtherSessions(actor service.Actor, userID uint, keepSessionID string) (int, error) {
+	ctx := context.Background()
+	var revoked int
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		revoked, err = gorm.G[models.Session](tx).
+			Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepSessionID).
+			Update(ctx, "revoked_at", time.Now())
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "session.revoke_others", "user", userID, nil, map[string]int{"revoked": revoked})
+	})
+	return revoked, err
+}
+
+// RevokeAllSessions ends every session of the user
+func (s *SessionServiceImpl) RevokeAllSessions(userID uint) error {
+	return revokeUserSessions(context.Background(), s.DB, userID)
+}
+
+// PurgeExpired deletes sessions that can no longer be used
+func (s *SessionServiceImpl) PurgeExpired() (int, error) {
+	return gorm.G[models.Session](s.DB).
+		Where("expires_at <= ? OR revoked_at IS NOT NULL", time.Now()).
+		Delete(context.Background())
+}
+
+// CountActive returns the number of sessions that can still authenticate
+func (s *SessionServiceImpl) CountActive() (int, error) {
+	count, err := gorm.G[models.Session](s.DB).
+		Where("revoked_at IS NULL AND expires_at > ?", time.Now()).
+		Count(context.Background(), "id")
+	return int(count), err
+}
+
+// revokeUserSessions ends every session of the user using db, which may be a transaction
+func revokeUserSessions(ctx context.Context, db *gorm.DB, userID uint) error {
+	_, err := gorm.G[models.Session](db).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update(ctx, "revoked_at", time.Now())
+	return err
+}
+
+// hashToken keeps raw refresh tokens out of the database
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// truncate cuts s to at most n bytes
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,144 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"estore-server/models"
+	"estore-server/service"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// activeSuspensionCondition matches suspensions in force at the bound time
+const activeSuspensionCondition = "lifted_at IS NULL AND (expires_at IS NULL OR expires_at > ?)"
+
+// SuspensionServiceImpl stores suspensions; whether one is in force is decided from its
+// expiry at query time
+type SuspensionServiceImpl struct {
+	DB            *gorm.DB
+	Notifications service.NotificationService
+}
+
+var _ service.SuspensionService = (*SuspensionServiceImpl)(nil)
+
+func NewSuspensionServiceImpl(db *gorm.DB, notifications service.NotificationService) *SuspensionServiceImpl {
+	return &SuspensionServiceImpl{DB: db, Notifications: notifications}
+}
+
+// SuspendUser suspends a user until expiresAt, or until lifted when expiresAt is nil.
+// A user can only have one suspension in force at a time.
+func (s *SuspensionServiceImpl) SuspendUser(actor service.Actor, userID, issuedBy uint, reason string, expiresAt *time.Time) (*models.Suspension, error) {
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, service.ErrSuspensionExpiry
+	}
+
+	ctx := context.Background()
+	suspension := &models.Suspension{
+		UserID:     userID,
+		Reason:     reason,
+		IssuedByID: issuedBy,
+		ExpiresAt:  expiresAt,
+	}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock the user so two moderators cannot suspend them at the same time
+		if _, err := gorm.G[models.User](tx, clause.Locking{Strength: "UPDATE"}).Where("id = ?", userID).First(ctx); err != nil {
+			return err
+		}
+
+		if _, err := activeSuspension(ctx, tx, userID, now); err == nil {
+			return service.ErrAlreadySuspended
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err := gorm.G[models.Suspension](tx).Create(ctx, suspension); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "user.suspend", "user", userID, nil, suspension)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The user cannot sign in while suspended, but will find this once they can again
+	notify(s.Notifications, models.Notification{
+		UserID:     userID,
+		Type:       models.NotificationAccountSuspended,
+		Message:    "Your account was suspended: " + reason,
+		TargetType: "suspension",
+		TargetID:   suspension.ID,
+	})
+	return suspension, nil
+}
+
+// LiftSuspension ends the user's suspension ahead of its expiry
+func (s *SuspensionServiceImpl) LiftSuspension(actor service.Actor, userID, liftedBy uint) (*models.Suspension, error) {
+	ctx := context.Background()
+	now := time.Now()
+	suspension, err := activeSuspension(ctx, s.DB, userID, now)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, service.ErrNotSuspended
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		rows, err := gorm.G[models.Suspension](tx).Where("id = ? AND lifted_at IS NULL", suspension.ID).
+			Updates(ctx, models.Suspension{LiftedAt: &now, LiftedByID: &liftedBy})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return service.ErrNotSuspended
+		}
+
+		suspension.LiftedAt = &now
+		suspension.LiftedByID = &liftedBy
+		return recordAudit(ctx, tx, actor, "user.unsuspend", "user", userID, nil, suspension)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notify(s.Notifications, models.Notification{
+		UserID:     userID,
+		Type:       models.NotificationAccountUnsuspended,
+		Message:    "Your account suspension has been lifted.",
+		TargetType: "suspension",
+		TargetID:   suspension.ID,
+	})
+	return suspension, nil
+}
+
+// ActiveSuspension returns the suspension in force for the user, or nil if there is none
+func (s *SuspensionServiceImpl) ActiveSuspension(userID uint) (*models.Suspension, error) {
+	suspension, err := activeSuspension(context.Background(), s.DB, userID, time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return suspension, err
+}
+
+// ListSuspensions returns every suspension the user has received, newest first
+func (s *SuspensionServiceImpl) ListSuspensions(userID uint) ([]models.Suspension, error) {
+	ctx := context.Background()
+	if _, err := gorm.G[models.User](s.DB).Where("id = ?", userID).First(ctx); err != nil {
+		return nil, err
+	}
+
+	return gorm.G[models.Suspension](s.DB).Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(ctx)
+}
+
+// activeSuspension loads the suspension in force for the user at now
+func activeSuspension(ctx context.Context, db *gorm.DB, userID uint, now time.Time) (*models.Suspension, error) {
+	suspension, err := gorm.G[models.Suspension](db).Where("user_id = ?", userID).Where(activeSuspensionCondition, now).
+		Order("id DESC").First(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &suspension, nil
+}
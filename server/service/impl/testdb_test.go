@@ -0,0 +1,44 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"estore-server/models"
+
+	"gorm.io/gorm"
+)
+
+// createTestUser stores a user with the given username
+func createTestUser(t *testing.T, db *gorm.DB, username string) *models.User {
+	t.Helper()
+
+	user := &models.User{Username: username, Email: username + "@example.com"}
+	if err := gorm.G[models.User](db).Create(context.Background(), user); err != nil {
+		t.Fatalf("create user %s: %v", username, err)
+	}
+	return user
+}
+
+// createTestUsers stores n users named after prefix
+func createTestUsers(t *testing.T, db *gorm.DB, prefix string, n int) []*models.User {
+	t.Helper()
+
+	users := make([]*models.User, n)
+	for i := range users {
+		users[i] = createTestUser(t, db, fmt.Sprintf("%s%d", prefix, i))
+	}
+	return users
+}
+
+// createTestProduct stores a product listed by seller
+func createTestProduct(t *testing.T, db *gorm.DB, seller *models.User, price, stock int) *models.Product {
+	t.Helper()
+
+	product := &models.Product{Name: "Lamp", Price: price, Stock: stock, UserID: seller.ID}
+	if err := db.Omit("User").Create(product).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	return product
+}
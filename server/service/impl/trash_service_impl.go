@@ -0,0 +1,322 @@
+package impl
+
+import (
+	"context"
+	"time"
+
+	"estore-server/models"
+	"estore-server/search"
+	"estore-server/service"
+	"estore-server/storage"
+
+	"gorm.io/gorm"
+)
+
+// TrashServiceImpl manages soft-deleted products and users. Queries on trashed rows go
+// through withTrashed, since GORM hides them otherwise.
+type TrashServiceImpl struct {
+	DB    *gorm.DB
+	Store storage.BlobStore
+	Index search.SearchIndex
+}
+
+var _ service.TrashService = (*TrashServiceImpl)(nil)
+
+func NewTrashServiceImpl(db *gorm.DB, store storage.BlobStore, index search.SearchIndex) *TrashServiceImpl {
+	return &TrashServiceImpl{DB: db, Store: store, Index: index}
+}
+
+// withTrashed lifts GORM's soft-delete filter from a generics query chain. Passing
+// db.Unscoped() to gorm.G is not enough: G starts each statement afresh.
+func withTrashed(stmt *gorm.Statement) {
+	stmt.Unscoped = true
+}
+
+// ListDeletedProducts returns trashed products with their seller, most recently deleted first
+func (s *TrashServiceImpl) ListDeletedProducts() ([]models.Product, error) {
+	ctx := context.Background()
+	// Preloads inherit withTrashed, so sellers that are themselves trashed are loaded too
+	return gorm.G[models.Product](s.DB).Scopes(withTrashed).Preload("User", nil).Preload("Images", preloadImages).
+		Where("deleted_at IS NOT NULL").Order("deleted_at DESC, id DESC").Find(ctx)
+}
+
+// ListDeletedUsers returns trashed users, most recently deleted first
+func (s *TrashServiceImpl) ListDeletedUsers() ([]models.User, error) {
+	ctx := context.Background()
+	return gorm.G[models.User](s.DB).Scopes(withTrashed).Preload("Roles", nil).
+		Where("deleted_at IS NOT NULL").Order("deleted_at DESC, id DESC").Find(ctx)
+}
+
+// RestoreProduct takes a product out of the trash and puts it back into the search index.
+// Products of a trashed seller can only come back with the seller.
+func (s *TrashServiceImpl) RestoreProduct(actor service.Actor, productID uint) (*models.Product, error) {
+	ctx := context.Background()
+	product, err := gorm.G[models.Product](s.DB).Scopes(withTrashed).Preload("User", nil).Preload("Images", preloadImages).
+		Where("id = ? AND deleted_at IS NOT NULL", productID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.User.ID == 0 || product.User.DeletedAt.Valid {
+		return nil, service.ErrOwnerDeleted
+	}
+
+	product.DeletedAt = gorm.DeletedAt{}
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := gorm.G[models.Product](tx).Scopes(withTrashed).Where("id = ?", productID).Update(ctx, "deleted_at", nil); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, actor, "product.restore", "product", productID, nil, product); err != nil {
+			return err
+		}
+		// To integrators a restored product reappears the way a new one does
+		return recordEvent(ctx, tx, models.EventProductCreated, newProductEventData(&product))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.Index.Index(searchDocument(&product))
+	return &product, nil
+}
+
+// RestoreUser takes a user out of the trash along with the listings that were trashed with
+// the account. Listings the user had deleted beforehand stay in the trash.
+func (s *TrashServiceImpl) RestoreUser(actor service.Actor, userID uint) (*models.User, error) {
+	ctx := context.Background()
+	user, err := gorm.G[models.User](s.DB).Scopes(withTrashed).Where("id = ? AND deleted_at IS NOT NULL", userID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var products []models.Product
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := gorm.G[models.User](tx).Scopes(withTrashed).Where("id = ?", userID).Update(ctx, "deleted_at", nil); err != nil {
+			return err
+		}
+
+		// DeleteUser stamps the account and its listings with the same time
+		products, err = gorm.G[models.Product](tx).Scopes(withTrashed).
+			Where("user_id = ? AND deleted_at >= ?", userID, user.DeletedAt.Time).Find(ctx)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]uint, 0, len(products))
+		for _, product := range products {
+			ids = append(ids, product.ID)
+		}
+		if len(ids) > 0 {
+			if _, err := gorm.G[models.Product](tx).Scopes(withTrashed).Where("id IN ?", ids).Update(ctx, "deleted_at", nil); err != nil {
+				return err
+			}
+		}
+		if err := recordAudit(ctx, tx, actor, "user.restore", "user", userID, nil, map[string]any{"products": ids}); err != nil {
+			return err
+		}
+
+		// To integrators the account and its listings reappear the way new ones do
+		if err := recordEvent(ctx, tx, models.EventUserCreated, userEventData{ID: user.ID, Username: user.Username}); err != nil {
+			return err
+		}
+		for i := range products {
+			if err := recordEvent(ctx, tx, models.EventProductCreated, newProductEventData(&products[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range products {
+		s.Index.Index(searchDocument(&products[i]))
+	}
+
+	restored, err := gorm.G[models.User](s.DB).Preload("Roles", nil).Where("id = ?", userID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &restored, nil
+}
+
+// Purge permanently removes users and products trashed before deletedBefore. A purged
+// user takes all of their products, credentials, sessions and tokens along. Each item is
+// removed in its own transaction, so a failure part-way keeps whatever was already purged.
+func (s *TrashServiceImpl) Purge(ctx context.Context, actor service.Actor, deletedBefore time.Time) (*service.PurgeResult, error) {
+	result := &service.PurgeResult{}
+
+	users, err := gorm.G[models.User](s.DB).Scopes(withTrashed).Select("id").Where("deleted_at < ?", deletedBefore).Find(ctx)
+	if err != nil {
+		return result, err
+	}
+	for _, user := range users {
+		products, err := s.purgeUser(ctx, actor, user.ID)
+		if err != nil {
+			return result, err
+		}
+		result.Users++
+		result.Products += products
+	}
+
+	products, err := gorm.G[models.Product](s.DB).Scopes(withTrashed).Select("id").Where("deleted_at < ?", deletedBefore).Find(ctx)
+	if err != nil {
+		return result, err
+	}
+	for _, product := range products {
+		if err := s.purgeProducts(ctx, actor, []uint{product.ID}); err != nil {
+			return result, err
+		}
+		result.Products++
+	}
+
+	return result, nil
+}
+
+// purgeUser hard-deletes a user and everything they own, returning how many products went with them
+func (s *TrashServiceImpl) purgeUser(ctx context.Context, actor service.Actor, userID uint) (int, error) {
+	products, err := gorm.G[models.Product](s.DB).Scopes(withTrashed).Select("id").Where("user_id = ?", userID).Find(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]uint, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+	if err := s.purgeProducts(ctx, actor, ids); err != nil {
+		return 0, err
+	}
+
+	err = s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := gorm.G[models.CartItem](tx).Where("user_id = ?", userID).Delete(ctx); err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.Session](tx).Where("user_id = ?", userID).Delete(ctx); err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.PasswordResetToken](tx).Where("user_id = ?", userID).Delete(ctx); err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.EmailVerificationToken](tx).Where("user_id = ?", userID).Delete(ctx); err != nil {
+			return err
+		}
+		// Products the user favorited lose their bookmark
+		if _, err := gorm.G[models.Product](tx).Scopes(withTrashed).
+			Where("id IN (SELECT product_id FROM favorites WHERE user_id = ?)", userID).
+			Update(ctx, "favorite_count", gorm.Expr("favorite_count - 1")); err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.Favorite](tx).Where("user_id = ?", userID).Delete(ctx); err != nil {
+			return err
+		}
+
+		if err := deleteConversations(ctx, tx, "buyer_id = ? OR seller_id = ?", userID, userID); err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.Offer](tx).Where("buyer_id = ? OR seller_id = ?", userID, userID).Delete(ctx); err != nil {
+			return err
+		}
+
+		// Reviews the user wrote come out of the reviewed sellers' ratings
+		written, err := gorm.G[models.Review](tx).Where("reviewer_id = ?", userID).Find(ctx)
+		if err != nil {
+			return err
+		}
+		for _, review := range written {
+			if err := adjustSellerRating(ctx, tx, review.SellerID, -1, -review.Rating); err != nil {
+				return err
+			}
+		}
+		if _, err := gorm.G[models.Review](tx).Where("reviewer_id = ? OR seller_id = ?", userID, userID).Delete(ctx); err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.Notification](tx).Where("user_id = ?", userID).Delete(ctx); err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.Suspension](tx).Where("user_id = ?", userID).Delete(ctx); err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.UserRole](tx).Where("user_id = ?", userID).Delete(ctx); err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.UserAuth](tx).Where("id = ?", userID).Delete(ctx); err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.User](tx).Scopes(withTrashed).Where("id = ?", userID).Delete(ctx); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "user.purge", "user", userID, nil, nil)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// purgeProducts hard-deletes products together with their galleries and cart entries,
+// then removes the image files and index entries once the rows are gone
+func (s *TrashServiceImpl) purgeProducts(ctx context.Context, actor service.Actor, productIDs []uint) error {
+	if len(productIDs) == 0 {
+		return nil
+	}
+
+	var images []models.ProductImage
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		images, err = gorm.G[models.ProductImage](tx).Where("product_id IN ?", productIDs).Find(ctx)
+		if err != nil {
+			return err
+		}
+
+		if _, err := gorm.G[models.ProductImage](tx).Where("product_id IN ?", productIDs).Delete(ctx); err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.CartItem](tx).Where("product_id IN ?", productIDs).Delete(ctx); err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.Favorite](tx).Where("product_id IN ?", productIDs).Delete(ctx); err != nil {
+			return err
+		}
+		if err := deleteConversations(ctx, tx, "product_id IN ?", productIDs); err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.Offer](tx).Where("product_id IN ?", productIDs).Delete(ctx); err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.Product](tx).Scopes(withTrashed).Where("id IN ?", productIDs).Delete(ctx); err != nil {
+			return err
+		}
+		for _, id := range productIDs {
+			if err := recordAudit(ctx, tx, actor, "product.purge", "product", id, nil, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range productIDs {
+		s.Index.Remove(id)
+	}
+
+	keys := make([]string, 0, len(images)*2)
+	for _, image := range images {
+		keys = append(keys, image.Key, image.ThumbnailKey)
+	}
+	deleteBlobs(ctx, s.Store, keys)
+	return nil
+}
+
+// deleteConversations removes the conversations matching the condition along with their messages
+func deleteConversations(ctx context.Context, tx *gorm.DB, query string, args ...any) error {
+	if _, err := gorm.G[models.Message](tx).
+		Where("conversation_id IN (SELECT id FROM conversations WHERE "+query+")", args...).Delete(ctx); err != nil {
+		return err
+	}
+	_, err := gorm.G[models.Conversation](tx).Where(query, args...).Delete(ctx)
+	return err
+}
@@ -0,0 +1,77 @@
+package impl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"estore-server/models"
+	"estore-server/search"
+	"estore-server/service"
+	"estore-server/testdb"
+
+	"gorm.io/gorm"
+)
+
+func TestPurgeStopsWhenCancelled(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		seller := createTestUser(t, db, "seller")
+		if err := NewUserServiceImpl(db, nil).DeleteUser(service.UserActor(seller.ID), seller.ID); err != nil {
+			t.Fatalf("delete user: %v", err)
+		}
+
+		// A job whose lease ran out must not go on deleting
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		trash := NewTrashServiceImpl(db, nil, search.NewMemoryIndex())
+		if _, err := trash.Purge(ctx, service.SystemActor("job:trash.purge"), time.Now().Add(time.Hour)); err == nil {
+			t.Fatal("purge with a cancelled context succeeded")
+		}
+
+		count, err := gorm.G[models.User](db).Scopes(withTrashed).Where("id = ?", seller.ID).Count(context.Background(), "id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 1 {
+			t.Error("the trashed user was purged after the context was cancelled")
+		}
+	})
+}
+
+func TestTrashingAUserEmitsListingEvents(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+
+		seller := createTestUser(t, db, "seller")
+		createTestProduct(t, db, seller, 100, 1)
+		createTestProduct(t, db, seller, 200, 1)
+
+		if err := NewUserServiceImpl(db, nil).DeleteUser(service.UserActor(seller.ID), seller.ID); err != nil {
+			t.Fatalf("delete user: %v", err)
+		}
+		trash := NewTrashServiceImpl(db, nil, search.NewMemoryIndex())
+		if _, err := trash.RestoreUser(service.UserActor(seller.ID), seller.ID); err != nil {
+			t.Fatalf("restore user: %v", err)
+		}
+
+		events, err := gorm.G[models.OutboxEvent](db).Order("id").Find(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts := map[string]int{}
+		for _, event := range events {
+			counts[event.Type]++
+		}
+		want := map[string]int{
+			models.EventUserCreated:    1,
+			models.EventProductCreated: 2,
+			models.EventProductDeleted: 2,
+			models.EventUserDeleted:    1,
+		}
+		for eventType, n := range want {
+			if counts[eventType] != n {
+				t.Errorf("got %d %s events, want %d", counts[eventType], eventType, n)
+			}
+		}
+	})
+}
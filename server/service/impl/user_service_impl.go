@@ -3,6 +3,7 @@ package impl
 import (
 	"context"
 	"errors"
+	"time"
 
 	"estore-server/models"
 	"estore-server/service"
@@ -12,21 +13,23 @@ import (
 )
 
 type UserServiceImpl struct {
-	DB *gorm.DB
+	DB            *gorm.DB
+	Notifications service.NotificationService
 }
 
 var _ service.UserService = (*UserServiceImpl)(nil) // Ensure UserService implements UserServiceInterface
 
-func NewUserServiceImpl(db *gorm.DB) *UserServiceImpl {
+func NewUserServiceImpl(db *gorm.DB, notifications service.NotificationService) *UserServiceImpl {
 	return &UserServiceImpl{
-		DB: db,
+		DB:            db,
+		Notifications: notifications,
 	}
 }
 
 // GetUser retrieves user by ID
 func (s *UserServiceImpl) GetUser(userID uint) (*models.User, error) {
 	ctx := context.Background()
-	user, err := gorm.G[models.User](s.DB).Where("id = ?", userID).First(ctx)
+	user, err := gorm.G[models.User](s.DB).Preload("Roles", nil).Where("id = ?", userID).First(ctx)
 
 	if err != nil {
 		return nil, err
@@ -38,7 +41,7 @@ func (s *UserServiceImpl) GetUser(userID uint) (*models.User, error) {
 // GetAllUsers retrieves all users (for admins only)
 func (s *UserServiceImpl) GetAllUsers() ([]models.User, error) {
 	ctx := context.Background()
-	users, err := gorm.G[models.User](s.DB).Find(ctx)
+	users, err := gorm.G[models.User](s.DB).Preload("Roles", nil).Find(ctx)
 
 	if err != nil {
 		return nil, err
@@ -48,29 +51,51 @@ func (s *UserServiceImpl) GetAllUsers() ([]models.User, error) {
 }
 
 // UpdateUser updates user information
-func (s *UserServiceImpl) UpdateUser(userID uint, username, email, phone, address string) (*models.User, error) {
+func (s *UserServiceImpl) UpdateUser(actor service.Actor, userID uint, username, email, phone, address string) (*models.User, error) {
 	ctx := context.Background()
 	user, err := gorm.G[models.User](s.DB).Where("id = ?", userID).First(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	// A new address has to be verified again
+	emailChanged := email != "" && email != user.Email
+
+	before := user
+
 	// Update user
 	user.Username = username
 	user.Email = email
 	user.Phone = phone
 	user.Address = address
 
-	_, err = gorm.G[models.User](s.DB).Updates(ctx, user)
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		// The rating aggregate is maintained by reviews and must not be overwritten with the loaded copy
+		if _, err := gorm.G[models.User](tx).Omit("rating_count", "rating_sum").Updates(ctx, user); err != nil {
+			return err
+		}
+
+		if emailChanged && user.EmailVerifiedAt != nil {
+			if _, err := gorm.G[models.User](tx).Where("id = ?", user.ID).Update(ctx, "email_verified_at", nil); err != nil {
+				return err
+			}
+			user.EmailVerifiedAt = nil
+		}
+		if err := recordAudit(ctx, tx, actor, "user.update", "user", user.ID, before, user); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, models.EventUserUpdated, userEventData{ID: user.ID, Username: user.Username})
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &user, nil
+	// Reload so the result carries the user's roles
+	return s.GetUser(userID)
 }
 
 // UpdateUserPassword updates user's password
-func (s *UserServiceImpl) UpdateUserPassword(userID uint, oldPassword, newPassword string) error {
+func (s *UserServiceImpl) UpdateUserPassword(actor service.Actor, userID uint, oldPassword, newPassword string) error {
 	ctx := context.Background()
 	userAuth, err := gorm.G[models.UserAuth](s.DB).Where("id = ?", userID).First(ctx)
 	if err != nil {
@@ -88,21 +113,72 @@ func (s *UserServiceImpl) UpdateUserPassword(userID uint, oldPassword, newPasswo
 		return errors.New("failed to hash password")
 	}
 
-	// Update password
+	// Update password; reset tokens emailed before the change must no longer work
 	userAuth.Password = string(hashedPassword)
-	_, err = gorm.G[models.UserAuth](s.DB).Updates(ctx, userAuth)
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := gorm.G[models.UserAuth](tx).Updates(ctx, userAuth); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, actor, "user.password.update", "user", userID, nil, nil); err != nil {
+			return err
+		}
+		return invalidateResetTokens(ctx, tx, userID)
+	})
 	if err != nil {
 		return err
 	}
 
+	notify(s.Notifications, passwordChangedNotification(userID))
 	return nil
 }
 
-// DeleteUser deletes a user by ID
-func (s *UserServiceImpl) DeleteUser(userID uint) error {
-	ctx := context.Background()
-	if _, err := gorm.G[models.UserAuth](s.DB).Where("id = ?", userID).Delete(ctx); err != nil {
-		return err
+// passwordChangedNotification warns a user whose password was changed or reset
+func passwordChangedNotification(userID uint) models.Notification {
+	return models.Notification{
+		UserID:  userID,
+		Type:    models.NotificationPasswordChanged,
+		Message: "Your password was changed. If this wasn't you, reset your password right away.",
 	}
-	return nil
+}
+
+// DeleteUser moves a user and their listings to the trash and signs them out everywhere.
+// Credentials and roles are kept so an admin can restore the account until it is purged.
+func (s *UserServiceImpl) DeleteUser(actor service.Actor, userID uint) error {
+	ctx := context.Background()
+	// The listings share the account's deletion time so a restore can tell them apart
+	// from listings the user had already deleted themselves
+	now := time.Now().Truncate(time.Millisecond)
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := gorm.G[models.User](tx).Where("id = ?", userID).First(ctx)
+		if err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.User](tx).Where("id = ?", userID).Update(ctx, "deleted_at", now); err != nil {
+			return err
+		}
+
+		// Trashed listings stay in the search index; searches resolve hits through the
+		// database, which no longer returns them
+		listings, err := gorm.G[models.Product](tx).Select("id").Where("user_id = ?", userID).Find(ctx)
+		if err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.Product](tx).Where("user_id = ?", userID).Update(ctx, "deleted_at", now); err != nil {
+			return err
+		}
+		// Integrators see each listing go, just as if the seller had deleted it
+		for _, listing := range listings {
+			if err := recordEvent(ctx, tx, models.EventProductDeleted, deletedEventData{ID: listing.ID}); err != nil {
+				return err
+			}
+		}
+		if err := revokeUserSessions(ctx, tx, userID); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, actor, "user.delete", "user", userID, user, nil); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, models.EventUserDeleted, deletedEventData{ID: userID})
+	})
 }
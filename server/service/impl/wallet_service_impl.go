@@ -0,0 +1,324 @@
+package impl
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"estore-server/models"
+	"estore-server/service"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WalletServiceImpl keeps wallets and their ledger in the database
+type WalletServiceImpl struct {
+	DB            *gorm.DB
+	Notifications service.NotificationService
+}
+
+var _ service.WalletService = (*WalletServiceImpl)(nil)
+
+func NewWalletServiceImpl(db *gorm.DB, notifications service.NotificationService) *WalletServiceImpl {
+	return &WalletServiceImpl{DB: db, Notifications: notifications}
+}
+
+func (s *WalletServiceImpl) GetWallet(userID uint) (*models.Wallet, error) {
+	ctx := context.Background()
+	wallet, err := gorm.G[models.Wallet](s.DB).Where("user_id = ?", userID).First(ctx)
+	if err == nil {
+		return &wallet, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if _, err := gorm.G[models.User](s.DB).Where("id = ?", userID).First(ctx); err != nil {
+		return nil, err
+	}
+	var opened *models.Wallet
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		opened, err = lockUserWallet(ctx, tx, userID)
+		return err
+	})
+	return opened, err
+}
+
+func (s *WalletServiceImpl) Deposit(actor service.Actor, userID uint, amount int, memo string) (*models.LedgerTransaction, error) {
+	if amount <= 0 {
+		return nil, service.ErrInvalidAmount
+	}
+
+	ctx := context.Background()
+	var transaction *models.LedgerTransaction
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := gorm.G[models.User](tx).Where("id = ?", userID).First(ctx); err != nil {
+			return err
+		}
+		wallet, err := lockUserWallet(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		external, err := lockSystemWallet(ctx, tx, models.SystemWalletExternal)
+		if err != nil {
+			return err
+		}
+		transaction, err = postLedger(ctx, tx, models.LedgerDeposit, memo, actor.UserID, external, wallet, amount)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "wallet.deposit", "ledger_transaction", transaction.ID, nil, transaction)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notify(s.Notifications, models.Notification{
+		UserID:     userID,
+		Type:       models.NotificationWalletCredited,
+		Message:    fmt.Sprintf("%d was deposited to your wallet.", amount),
+		TargetType: "ledger_transaction",
+		TargetID:   transaction.ID,
+	})
+	return transaction, nil
+}
+
+func (s *WalletServiceImpl) Withdraw(actor service.Actor, userID uint, amount int, memo string) (*models.LedgerTransaction, error) {
+	if amount <= 0 {
+		return nil, service.ErrInvalidAmount
+	}
+
+	ctx := context.Background()
+	var transaction *models.LedgerTransaction
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		wallet, err := lockUserWallet(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		external, err := lockSystemWallet(ctx, tx, models.SystemWalletExternal)
+		if err != nil {
+			return err
+		}
+		transaction, err = postLedger(ctx, tx, models.LedgerWithdrawal, memo, actor.UserID, wallet, external, amount)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "wallet.withdraw", "ledger_transaction", transaction.ID, nil, transaction)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+func (s *WalletServiceImpl) Transfer(actor service.Actor, fromUserID, toUserID uint, amount int, memo string) (*models.LedgerTransaction, error) {
+	if amount <= 0 {
+		return nil, service.ErrInvalidAmount
+	}
+	if fromUserID == toUserID {
+		return nil, service.ErrSelfTransfer
+	}
+
+	ctx := context.Background()
+	var transaction *models.LedgerTransaction
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := gorm.G[models.User](tx).Where("id = ?", toUserID).First(ctx); err != nil {
+			return err
+		}
+
+		// Lock both wallets in user ID order so opposite transfers cannot deadlock
+		wallets := make(map[uint]*models.Wallet, 2)
+		for _, userID := range []uint{min(fromUserID, toUserID), max(fromUserID, toUserID)} {
+			wallet, err := lockUserWallet(ctx, tx, userID)
+			if err != nil {
+				return err
+			}
+			wallets[userID] = wallet
+		}
+
+		var err error
+		transaction, err = postLedger(ctx, tx, models.LedgerTransfer, memo, &fromUserID, wallets[fromUserID], wallets[toUserID], amount)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "wallet.transfer", "ledger_transaction", transaction.ID, nil, transaction)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notify(s.Notifications, models.Notification{
+		UserID:     toUserID,
+		Type:       models.NotificationWalletCredited,
+		Message:    fmt.Sprintf("You received a transfer of %d.", amount),
+		TargetType: "ledger_transaction",
+		TargetID:   transaction.ID,
+	})
+	return transaction, nil
+}
+
+func (s *WalletServiceImpl) Statement(userID uint, cursor string, limit int) (*service.StatementPage, error) {
+	ctx := context.Background()
+
+	if limit <= 0 {
+		limit = service.DefaultStatementPageSize
+	}
+	limit = min(limit, service.MaxStatementPageSize)
+
+	wallet, err := gorm.G[models.Wallet](s.DB).Where("user_id = ?", userID).First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Nothing has ever moved through a wallet that was never opened
+		return &service.StatementPage{Lines: []service.StatementLine{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	query := gorm.G[models.LedgerEntry](s.DB).Where("wallet_id = ?", wallet.ID)
+	total, err := query.Count(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	entries, next, err := paginateByID(ctx, query.Preload("Transaction", nil), cursor, limit, func(entry *models.LedgerEntry) uint { return entry.ID })
+	if err != nil {
+		return nil, err
+	}
+
+	page := &service.StatementPage{Total: total, NextCursor: next}
+
+	// The other side of each transfer names the counterparty
+	var transferIDs []uint
+	for _, entry := range entries {
+		if entry.Transaction.Kind == models.LedgerTransfer {
+			transferIDs = append(transferIDs, entry.TransactionID)
+		}
+	}
+	counterparties := make(map[uint]*uint, len(transferIDs))
+	if len(transferIDs) > 0 {
+		others, err := gorm.G[models.LedgerEntry](s.DB).Preload("Wallet", nil).
+			Where("transaction_id IN ? AND wallet_id <> ?", transferIDs, wallet.ID).Find(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range others {
+			counterparties[other.TransactionID] = other.Wallet.UserID
+		}
+	}
+
+	page.Lines = make([]service.StatementLine, 0, len(entries))
+	for _, entry := range entries {
+		page.Lines = append(page.Lines, service.StatementLine{
+			LedgerEntry:    entry,
+			Kind:           entry.Transaction.Kind,
+			Memo:           entry.Transaction.Memo,
+			CounterpartyID: counterparties[entry.TransactionID],
+		})
+	}
+	return page, nil
+}
+
+// Reconcile checks the ledger's invariants. The reads share one REPEATABLE READ transaction
+// so they see a single snapshot even while money keeps moving; Postgres' default READ
+// COMMITTED would take a fresh snapshot per statement and report drift that isn't there.
+// SQLite ignores the level, but its transactions are serializable anyway.
+func (s *WalletServiceImpl) Reconcile() (*service.ReconciliationReport, error) {
+	ctx := context.Background()
+	report := &service.ReconciliationReport{
+		UnbalancedTransactions: []uint{},
+		DriftedWallets:         []service.WalletDrift{},
+	}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		report.Transactions, err = gorm.G[models.LedgerTransaction](tx).Count(ctx, "id")
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.LedgerEntry{}).Select("COALESCE(SUM(amount), 0)").
+			Scan(&report.LedgerTotal).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.LedgerEntry{}).Group("transaction_id").Having("SUM(amount) <> 0").
+			Order("transaction_id").Pluck("transaction_id", &report.UnbalancedTransactions).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Wallet{}).
+			Select("wallets.id AS wallet_id, wallets.balance, COALESCE(SUM(ledger_entries.amount), 0) AS ledger_sum").
+			Joins("LEFT JOIN ledger_entries ON ledger_entries.wallet_id = wallets.id").
+			Group("wallets.id, wallets.balance").
+			Having("wallets.balance <> COALESCE(SUM(ledger_entries.amount), 0)").
+			Order("wallets.id").Scan(&report.DriftedWallets).Error
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Balanced = report.LedgerTotal == 0 && len(report.UnbalancedTransactions) == 0 && len(report.DriftedWallets) == 0
+	return report, nil
+}
+
+// postLedger moves amount between two wallets the caller has locked, recording a balanced
+// transaction and updating both balances. User wallets may not go negative; system wallets
+// may.
+func postLedger(ctx context.Context, tx *gorm.DB, kind models.LedgerKind, memo string, actorID *uint, from, to *models.Wallet, amount int) (*models.LedgerTransaction, error) {
+	if from.UserID != nil && from.Balance < amount {
+		return nil, service.ErrInsufficientFunds
+	}
+	from.Balance -= amount
+	to.Balance += amount
+
+	transaction := &models.LedgerTransaction{
+		Kind:    kind,
+		Memo:    memo,
+		ActorID: actorID,
+		Entries: []models.LedgerEntry{
+			{WalletID: from.ID, Amount: -amount, BalanceAfter: from.Balance},
+			{WalletID: to.ID, Amount: amount, BalanceAfter: to.Balance},
+		},
+	}
+	if err := gorm.G[models.LedgerTransaction](tx).Create(ctx, transaction); err != nil {
+		return nil, err
+	}
+
+	for _, wallet := range []*models.Wallet{from, to} {
+		if _, err := gorm.G[models.Wallet](tx).Where("id = ?", wallet.ID).Update(ctx, "balance", wallet.Balance); err != nil {
+			return nil, err
+		}
+	}
+	return transaction, nil
+}
+
+// lockUserWallet and lockSystemWallet lock a wallet for the rest of the transaction,
+// opening it empty first if it does not exist yet
+func lockUserWallet(ctx context.Context, tx *gorm.DB, userID uint) (*models.Wallet, error) {
+	return lockWallet(ctx, tx, models.Wallet{UserID: &userID}, "user_id = ?", userID)
+}
+
+func lockSystemWallet(ctx context.Context, tx *gorm.DB, name string) (*models.Wallet, error) {
+	return lockWallet(ctx, tx, models.Wallet{SystemName: &name}, "system_name = ?", name)
+}
+
+func lockWallet(ctx context.Context, tx *gorm.DB, blank models.Wallet, query string, arg any) (*models.Wallet, error) {
+	wallet, err := gorm.G[models.Wallet](tx, clause.Locking{Strength: "UPDATE"}).Where(query, arg).First(ctx)
+	if err == nil {
+		return &wallet, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// A concurrent transaction may open the same wallet; the unique index keeps one
+	if err := gorm.G[models.Wallet](tx, clause.OnConflict{DoNothing: true}).Create(ctx, &blank); err != nil {
+		return nil, err
+	}
+	wallet, err = gorm.G[models.Wallet](tx, clause.Locking{Strength: "UPDATE"}).Where(query, arg).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
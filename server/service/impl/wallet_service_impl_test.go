@@ -0,0 +1,112 @@
+package impl
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"estore-server/service"
+	"estore-server/testdb"
+
+	"gorm.io/gorm"
+)
+
+func TestConcurrentTransfersKeepLedgerBalanced(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		users := createTestUsers(t, db, "user", 2)
+		wallets := NewWalletServiceImpl(db, nil)
+		for _, user := range users {
+			if _, err := wallets.Deposit(service.UserActor(user.ID), user.ID, 100, "top up"); err != nil {
+				t.Fatalf("deposit: %v", err)
+			}
+		}
+
+		// Transfers in both directions lock the same two wallets
+		var wg sync.WaitGroup
+		errs := make(chan error, 20)
+		for i := range 20 {
+			from, to := users[i%2], users[(i+1)%2]
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := wallets.Transfer(service.UserActor(from.ID), from.ID, to.ID, 30, "split the bill")
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			if err != nil && !errors.Is(err, service.ErrInsufficientFunds) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+
+		total := 0
+		for _, user := range users {
+			wallet, err := wallets.GetWallet(user.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if wallet.Balance < 0 {
+				t.Errorf("user %d is overdrawn at %d", user.ID, wallet.Balance)
+			}
+			total += wallet.Balance
+		}
+		if total != 200 {
+			t.Errorf("balances add up to %d, want 200", total)
+		}
+
+		report, err := wallets.Reconcile()
+		if err != nil {
+			t.Fatalf("reconcile: %v", err)
+		}
+		if !report.Balanced {
+			t.Errorf("ledger is unbalanced: %+v", report)
+		}
+	})
+}
+
+func TestConcurrentWithdrawalsDoNotOverdraw(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		user := createTestUser(t, db, "user")
+		wallets := NewWalletServiceImpl(db, nil)
+		if _, err := wallets.Deposit(service.UserActor(user.ID), user.ID, 50, "top up"); err != nil {
+			t.Fatalf("deposit: %v", err)
+		}
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := wallets.Withdraw(service.UserActor(user.ID), user.ID, 10, "cash out")
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		withdrawn := 0
+		for err := range errs {
+			switch {
+			case err == nil:
+				withdrawn++
+			case !errors.Is(err, service.ErrInsufficientFunds):
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+		if withdrawn != 5 {
+			t.Errorf("%d withdrawals went through, want 5", withdrawn)
+		}
+
+		wallet, err := wallets.GetWallet(user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wallet.Balance != 0 {
+			t.Errorf("balance is %d, want 0", wallet.Balance)
+		}
+	})
+}
@@ -0,0 +1,413 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"estore-server/jobs"
+	"estore-server/models"
+	"estore-server/service"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// webhookTimeout bounds one delivery attempt, including reading the response
+	webhookTimeout = 10 * time.Second
+
+	// webhookResponseLimit is how much of an endpoint's response body the delivery log keeps
+	webhookResponseLimit = 1000
+)
+
+// WebhookServiceImpl stores webhook endpoints and posts outbox events to them
+type WebhookServiceImpl struct {
+	DB     *gorm.DB
+	Client *http.Client
+}
+
+var _ service.WebhookService = (*WebhookServiceImpl)(nil)
+
+func NewWebhookServiceImpl(db *gorm.DB) *WebhookServiceImpl {
+	return &WebhookServiceImpl{DB: db, Client: &http.Client{Timeout: webhookTimeout}}
+}
+
+// webhookEnvelope is the JSON body posted to endpoints
+type webhookEnvelope struct {
+	ID        uint            `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// recordEvent writes an event to the outbox in the caller's transaction and queues its
+// fan-out, so the event is published if and only if the change it describes commits
+func recordEvent(ctx context.Context, tx *gorm.DB, eventType string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	event := models.OutboxEvent{Type: eventType, Payload: payload}
+	if err := gorm.G[models.OutboxEvent](tx).Create(ctx, &event); err != nil {
+		return err
+	}
+	_, err = jobs.Enqueue(ctx, tx, service.JobWebhookFanOut, service.WebhookJob{EventID: event.ID}, jobs.EnqueueOptions{})
+	return err
+}
+
+func (s *WebhookServiceImpl) ListEndpoints() ([]models.WebhookEndpoint, error) {
+	return gorm.G[models.WebhookEndpoint](s.DB).Order("id").Find(context.Background())
+}
+
+func (s *WebhookServiceImpl) CreateEndpoint(actor service.Actor, url, description string, events []string) (*models.WebhookEndpoint, error) {
+	if err := checkWebhookEvents(events); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+
+	endpoint := &models.WebhookEndpoint{
+		URL:         url,
+		Description: description,
+		Events:      events,
+		Secret:      hex.EncodeToString(buf),
+		Active:      true,
+	}
+	ctx := context.Background()
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := gorm.G[models.WebhookEndpoint](tx).Create(ctx, endpoint); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "webhook.create", "webhook", endpoint.ID, nil, endpoint)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+func (s *WebhookServiceImpl) UpdateEndpoint(actor service.Actor, endpointID uint, url, description string, events []string, active bool) (*models.WebhookEndpoint, error) {
+	if err := checkWebhookEvents(events); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	endpoint, err := gorm.G[models.WebhookEndpoint](s.DB).Where("id = ?", endpointID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	before := endpoint
+	endpoint.URL = url
+	endpoint.Description = description
+	endpoint.Events = events
+	endpoint.Active = active
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		// Select the editable columns so deactivating the endpoint is persisted
+		if _, err := gorm.G[models.WebhookEndpoint](tx).Select("url", "description", "events", "active").Updates(ctx, endpoint); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "webhook.update", "webhook", endpointID, before, endpoint)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// DeleteEndpoint removes an endpoint along with its delivery log
+func (s *WebhookServiceImpl) DeleteEndpoint(actor service.Actor, endpointID uint) error {
+	ctx := context.Background()
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		endpoint, err := gorm.G[models.WebhookEndpoint](tx).Where("id = ?", endpointID).First(ctx)
+		if err != nil {
+			return err
+		}
+		if _, err := gorm.G[models.WebhookEndpoint](tx).Where("id = ?", endpointID).Delete(ctx); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "webhook.delete", "webhook", endpointID, endpoint, nil)
+	})
+}
+
+func (s *WebhookServiceImpl) ListDeliveries(endpointID uint, cursor string, limit int) (*service.WebhookDeliveryPage, error) {
+	ctx := context.Background()
+
+	if limit <= 0 {
+		limit = service.DefaultWebhookDeliveryPageSize
+	}
+	limit = min(limit, service.MaxWebhookDeliveryPageSize)
+
+	if _, err := gorm.G[models.WebhookEndpoint](s.DB).Where("id = ?", endpointID).First(ctx); err != nil {
+		return nil, err
+	}
+
+	query := gorm.G[models.WebhookDelivery](s.DB).Where("endpoint_id = ?", endpointID)
+	total, err := query.Count(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, next, err := paginateByID(ctx, query, cursor, limit, func(delivery *models.WebhookDelivery) uint { return delivery.ID })
+	if err != nil {
+		return nil, err
+	}
+
+	return &service.WebhookDeliveryPage{Deliveries: deliveries, Total: total, NextCursor: next}, nil
+}
+
+func (s *WebhookServiceImpl) Redeliver(actor service.Actor, deliveryID uint) (*models.WebhookDelivery, error) {
+	ctx := context.Background()
+	original, err := gorm.G[models.WebhookDelivery](s.DB).Where("id = ?", deliveryID).First(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &models.WebhookDelivery{
+		EndpointID: original.EndpointID,
+		EventID:    original.EventID,
+		EventType:  original.EventType,
+		Status:     models.WebhookDeliveryPending,
+	}
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := queueDelivery(ctx, tx, delivery); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, actor, "webhook.redeliver", "webhook_delivery", deliveryID, nil, delivery)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// FanOut runs at most once per event: marking the event dispatched is conditional, so a
+// retried job finds it already done
+func (s *WebhookServiceImpl) FanOut(ctx context.Context, eventID uint) error {
+	return s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		event, err := gorm.G[models.OutboxEvent](tx).Where("id = ?", eventID).First(ctx)
+		if err != nil {
+			return err
+		}
+
+		rows, err := gorm.G[models.OutboxEvent](tx).Where("id = ? AND dispatched_at IS NULL", eventID).Update(ctx, "dispatched_at", time.Now())
+		if err != nil || rows == 0 {
+			return err
+		}
+
+		endpoints, err := gorm.G[models.WebhookEndpoint](tx).Where("active = ?", true).Find(ctx)
+		if err != nil {
+			return err
+		}
+		for _, endpoint := range endpoints {
+			if !endpoint.Subscribes(event.Type) {
+				continue
+			}
+			delivery := &models.WebhookDelivery{
+				EndpointID: endpoint.ID,
+				EventID:    event.ID,
+				EventType:  event.Type,
+				Status:     models.WebhookDeliveryPending,
+			}
+			if err := queueDelivery(ctx, tx, delivery); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *WebhookServiceImpl) Deliver(ctx context.Context, deliveryID uint) error {
+	delivery, err := gorm.G[models.WebhookDelivery](s.DB).Preload("Endpoint", nil).Preload("Event", nil).
+		Where("id = ?", deliveryID).First(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// The endpoint was deleted along with its deliveries
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if delivery.Status == models.WebhookDeliverySucceeded {
+		return nil
+	}
+	if !delivery.Endpoint.Active {
+		return s.recordAttempt(ctx, deliveryID, map[string]any{
+			"status": models.WebhookDeliveryFailed,
+			"error":  "endpoint is disabled",
+		})
+	}
+
+	body, err := json.Marshal(webhookEnvelope{
+		ID:        delivery.Event.ID,
+		Type:      delivery.Event.Type,
+		CreatedAt: delivery.Event.CreatedAt,
+		Data:      delivery.Event.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	status, response, sendErr := s.send(ctx, delivery.Endpoint, delivery.ID, delivery.EventType, body)
+	outcome := map[string]any{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"response_status": status,
+		"response_body":   response,
+	}
+	if sendErr == nil {
+		outcome["status"] = models.WebhookDeliverySucceeded
+		outcome["error"] = ""
+		outcome["delivered_at"] = time.Now()
+	} else {
+		outcome["status"] = models.WebhookDeliveryFailed
+		outcome["error"] = sendErr.Error()
+	}
+	if err := s.recordAttempt(ctx, deliveryID, outcome); err != nil {
+		return err
+	}
+	return sendErr
+}
+
+// pruneBatchSize bounds how many events go into a single "IN ?" condition when pruning
+const pruneBatchSize = 1000
+
+func (s *WebhookServiceImpl) PruneEvents(ctx context.Context, actor service.Actor, dispatchedBefore time.Time) (int64, error) {
+	var pruned int64
+	err := s.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		events := gorm.G[models.OutboxEvent](tx).Select("id").
+			Where("dispatched_at < ?", dispatchedBefore).
+			Where("id NOT IN (SELECT event_id FROM webhook_deliveries WHERE updated_at >= ?)", dispatchedBefore)
+		stale, err := events.Find(ctx)
+		if err != nil || len(stale) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(stale))
+		for _, event := range stale {
+			ids = append(ids, event.ID)
+		}
+		for batch := range slices.Chunk(ids, pruneBatchSize) {
+			if _, err := gorm.G[models.WebhookDelivery](tx).Where("event_id IN ?", batch).Delete(ctx); err != nil {
+				return err
+			}
+			if _, err := gorm.G[models.OutboxEvent](tx).Where("id IN ?", batch).Delete(ctx); err != nil {
+				return err
+			}
+		}
+		pruned = int64(len(ids))
+		return recordAudit(ctx, tx, actor, "webhook.prune", "outbox_event", "dispatched", nil, map[string]any{"deleted": pruned, "dispatched_before": dispatchedBefore})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return pruned, nil
+}
+
+// send posts a signed body to the endpoint and reports the response. Any status outside
+// 2xx counts as a failure.
+func (s *WebhookServiceImpl) send(ctx context.Context, endpoint *models.WebhookEndpoint, deliveryID uint, eventType string, body []byte) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Estore-Event", eventType)
+	req.Header.Set("X-Estore-Delivery", strconv.FormatUint(uint64(deliveryID), 10))
+	req.Header.Set(service.WebhookSignatureHeader, signWebhook(endpoint.Secret, time.Now(), body))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	response, err := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	if err != nil {
+		return resp.StatusCode, "", err
+	}
+	if resp.StatusCode/100 != 2 {
+		return resp.StatusCode, string(response), fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, string(response), nil
+}
+
+func (s *WebhookServiceImpl) recordAttempt(ctx context.Context, deliveryID uint, outcome map[string]any) error {
+	_, err := gorm.G[models.WebhookDelivery](s.DB).Where("id = ?", deliveryID).Set(clause.Assignments(outcome)).Update(ctx)
+	return err
+}
+
+// queueDelivery stores a pending delivery and the job that will send it
+func queueDelivery(ctx context.Context, tx *gorm.DB, delivery *models.WebhookDelivery) error {
+	if err := gorm.G[models.WebhookDelivery](tx).Create(ctx, delivery); err != nil {
+		return err
+	}
+	_, err := jobs.Enqueue(ctx, tx, service.JobWebhookDeliver, service.WebhookJob{DeliveryID: delivery.ID}, jobs.EnqueueOptions{})
+	return err
+}
+
+// signWebhook returns the signature header value for a body sent at signedAt
+func signWebhook(secret string, signedAt time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func checkWebhookEvents(events []string) error {
+	for _, event := range events {
+		if event != models.WebhookAllEvents && !slices.Contains(models.WebhookEventTypes, event) {
+			return fmt.Errorf("%w: %s", service.ErrUnknownWebhookEvent, event)
+		}
+	}
+	return nil
+}
+
+// productEventData, userEventData and deletedEventData are the data of product and user
+// events. Users are described by public fields only; contact details stay in the store.
+type productEventData struct {
+	ID          uint   `json:"id"`
+	UserID      uint   `json:"user_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Price       int    `json:"price"`
+	Stock       int    `json:"stock"`
+	CategoryID  *uint  `json:"category_id"`
+}
+
+type userEventData struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+type deletedEventData struct {
+	ID uint `json:"id"`
+}
+
+func newProductEventData(product *models.Product) productEventData {
+	return productEventData{
+		ID:          product.ID,
+		UserID:      product.UserID,
+		Name:        product.Name,
+		Description: product.Description,
+		Price:       product.Price,
+		Stock:       product.Stock,
+		CategoryID:  product.CategoryID,
+	}
+}
@@ -0,0 +1,301 @@
+package impl
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"estore-server/models"
+	"estore-server/service"
+	"estore-server/testdb"
+
+	"gorm.io/gorm"
+)
+
+// receivedWebhook is a request an endpoint accepted
+type receivedWebhook struct {
+	eventType string
+	envelope  webhookEnvelope
+}
+
+// newWebhookReceiver starts an endpoint that checks each request's signature against the
+// secret it is given and answers 401 to anything unsigned or forged
+func newWebhookReceiver(t *testing.T) (url string, setSecret func(string), received <-chan receivedWebhook) {
+	t.Helper()
+	secrets := make(chan string, 1)
+	requests := make(chan receivedWebhook, 10)
+	var secret string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case secret = <-secrets:
+		default:
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var timestamp, signature string
+		for _, part := range strings.Split(r.Header.Get(service.WebhookSignatureHeader), ",") {
+			key, value, _ := strings.Cut(part, "=")
+			switch key {
+			case "t":
+				timestamp = value
+			case "v1":
+				signature = value
+			}
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(timestamp + "."))
+		mac.Write(body)
+		if timestamp == "" || !hmac.Equal([]byte(signature), []byte(hex.EncodeToString(mac.Sum(nil)))) {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+
+		var envelope webhookEnvelope
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests <- receivedWebhook{eventType: r.Header.Get("X-Estore-Event"), envelope: envelope}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, func(s string) { secrets <- s }, requests
+}
+
+// publishEvent commits an event to the outbox the way a service does
+func publishEvent(t *testing.T, db *gorm.DB, eventType string, data any) models.OutboxEvent {
+	t.Helper()
+	ctx := context.Background()
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return recordEvent(ctx, tx, eventType, data)
+	}); err != nil {
+		t.Fatalf("record event: %v", err)
+	}
+	event, err := gorm.G[models.OutboxEvent](db).Order("id DESC").First(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+// eventDeliveries returns the deliveries queued for an event, oldest first
+func eventDeliveries(t *testing.T, db *gorm.DB, eventID uint) []models.WebhookDelivery {
+	t.Helper()
+	deliveries, err := gorm.G[models.WebhookDelivery](db).Where("event_id = ?", eventID).Order("id").Find(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return deliveries
+}
+
+func TestWebhookDeliveryIsSignedWithTheEndpointSecret(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		url, setSecret, received := newWebhookReceiver(t)
+		webhooks := NewWebhookServiceImpl(db)
+		endpoint, err := webhooks.CreateEndpoint(service.SystemActor("test"), url, "", []string{models.EventProductCreated})
+		if err != nil {
+			t.Fatalf("create endpoint: %v", err)
+		}
+
+		event := publishEvent(t, db, models.EventProductCreated, deletedEventData{ID: 7})
+		if err := webhooks.FanOut(ctx, event.ID); err != nil {
+			t.Fatalf("fan out: %v", err)
+		}
+		deliveries := eventDeliveries(t, db, event.ID)
+		if len(deliveries) != 1 {
+			t.Fatalf("queued %d deliveries, want 1", len(deliveries))
+		}
+
+		// An endpoint holding a different secret rejects the delivery
+		setSecret("not the secret")
+		if err := webhooks.Deliver(ctx, deliveries[0].ID); err == nil {
+			t.Fatal("delivery signed with another secret was accepted")
+		}
+
+		setSecret(endpoint.Secret)
+		if err := webhooks.Deliver(ctx, deliveries[0].ID); err != nil {
+			t.Fatalf("deliver: %v", err)
+		}
+		got := <-received
+		if got.eventType != models.EventProductCreated || got.envelope.ID != event.ID || string(got.envelope.Data) != `{"id":7}` {
+			t.Errorf("endpoint received %s event %d with data %s", got.eventType, got.envelope.ID, got.envelope.Data)
+		}
+
+		delivered := eventDeliveries(t, db, event.ID)[0]
+		if delivered.Status != models.WebhookDeliverySucceeded || delivered.Attempts != 2 || delivered.ResponseStatus != http.StatusOK {
+			t.Errorf("delivery is %s after %d attempts with status %d", delivered.Status, delivered.Attempts, delivered.ResponseStatus)
+		}
+	})
+}
+
+func TestRolledBackChangePublishesNoEvent(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		errRollback := errors.New("roll back")
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := recordEvent(ctx, tx, models.EventProductCreated, deletedEventData{ID: 1}); err != nil {
+				return err
+			}
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			t.Fatalf("transaction returned %v", err)
+		}
+
+		events, err := gorm.G[models.OutboxEvent](db).Count(ctx, "id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fanOuts, err := gorm.G[models.Job](db).Where("type = ?", service.JobWebhookFanOut).Count(ctx, "id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if events != 0 || fanOuts != 0 {
+			t.Errorf("rolled back change left %d events and %d fan-out jobs", events, fanOuts)
+		}
+	})
+}
+
+func TestFanOutQueuesDeliveriesOnce(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		webhooks := NewWebhookServiceImpl(db)
+		actor := service.SystemActor("test")
+		subscribed, err := webhooks.CreateEndpoint(actor, "http://localhost/all", "", []string{models.WebhookAllEvents})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := webhooks.CreateEndpoint(actor, "http://localhost/users", "", []string{models.EventUserCreated}); err != nil {
+			t.Fatal(err)
+		}
+		disabled, err := webhooks.CreateEndpoint(actor, "http://localhost/off", "", []string{models.WebhookAllEvents})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := webhooks.UpdateEndpoint(actor, disabled.ID, disabled.URL, "", disabled.Events, false); err != nil {
+			t.Fatal(err)
+		}
+
+		event := publishEvent(t, db, models.EventProductDeleted, deletedEventData{ID: 1})
+
+		// A retried fan-out job finds the event already dispatched
+		for range 2 {
+			if err := webhooks.FanOut(ctx, event.ID); err != nil {
+				t.Fatalf("fan out: %v", err)
+			}
+		}
+
+		deliveries := eventDeliveries(t, db, event.ID)
+		if len(deliveries) != 1 || deliveries[0].EndpointID != subscribed.ID {
+			t.Fatalf("queued %d deliveries, want one to endpoint %d", len(deliveries), subscribed.ID)
+		}
+		sends, err := gorm.G[models.Job](db).Where("type = ?", service.JobWebhookDeliver).Count(ctx, "id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sends != 1 {
+			t.Errorf("queued %d delivery jobs, want 1", sends)
+		}
+	})
+}
+
+func TestRedeliverSendsTheEventAgainAsANewDelivery(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		url, setSecret, received := newWebhookReceiver(t)
+		webhooks := NewWebhookServiceImpl(db)
+		endpoint, err := webhooks.CreateEndpoint(service.SystemActor("test"), url, "", []string{models.WebhookAllEvents})
+		if err != nil {
+			t.Fatal(err)
+		}
+		setSecret(endpoint.Secret)
+
+		event := publishEvent(t, db, models.EventUserDeleted, deletedEventData{ID: 3})
+		if err := webhooks.FanOut(ctx, event.ID); err != nil {
+			t.Fatal(err)
+		}
+		original := eventDeliveries(t, db, event.ID)[0]
+		if err := webhooks.Deliver(ctx, original.ID); err != nil {
+			t.Fatalf("deliver: %v", err)
+		}
+		<-received
+
+		redelivery, err := webhooks.Redeliver(service.SystemActor("test"), original.ID)
+		if err != nil {
+			t.Fatalf("redeliver: %v", err)
+		}
+		if redelivery.ID == original.ID || redelivery.EventID != event.ID || redelivery.Status != models.WebhookDeliveryPending {
+			t.Fatalf("redelivery is %+v", redelivery)
+		}
+		if err := webhooks.Deliver(ctx, redelivery.ID); err != nil {
+			t.Fatalf("deliver again: %v", err)
+		}
+		if got := <-received; got.envelope.ID != event.ID {
+			t.Errorf("redelivery carried event %d, want %d", got.envelope.ID, event.ID)
+		}
+
+		deliveries := eventDeliveries(t, db, event.ID)
+		if len(deliveries) != 2 || deliveries[0].Attempts != 1 || deliveries[1].Status != models.WebhookDeliverySucceeded {
+			t.Errorf("delivery log after redelivery: %+v", deliveries)
+		}
+	})
+}
+
+func TestPruneEventsKeepsRecentlyAttemptedEvents(t *testing.T) {
+	testdb.ForEach(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		webhooks := NewWebhookServiceImpl(db)
+		if _, err := webhooks.CreateEndpoint(service.SystemActor("test"), "http://localhost/all", "", []string{models.WebhookAllEvents}); err != nil {
+			t.Fatal(err)
+		}
+
+		var events []models.OutboxEvent
+		for id := range 3 {
+			event := publishEvent(t, db, models.EventUserDeleted, deletedEventData{ID: uint(id)})
+			if err := webhooks.FanOut(ctx, event.ID); err != nil {
+				t.Fatal(err)
+			}
+			events = append(events, event)
+		}
+
+		// The first two were dispatched long ago; the second was redelivered since
+		old := time.Now().Add(-48 * time.Hour)
+		if _, err := gorm.G[models.OutboxEvent](db).Where("id IN ?", []uint{events[0].ID, events[1].ID}).Update(ctx, "dispatched_at", old); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gorm.G[models.WebhookDelivery](db).Where("event_id = ?", events[0].ID).Update(ctx, "updated_at", old); err != nil {
+			t.Fatal(err)
+		}
+
+		pruned, err := webhooks.PruneEvents(ctx, service.SystemActor("job:webhook.prune"), time.Now().Add(-24*time.Hour))
+		if err != nil {
+			t.Fatalf("prune: %v", err)
+		}
+		if pruned != 1 {
+			t.Errorf("pruned %d events, want 1", pruned)
+		}
+		if left := eventDeliveries(t, db, events[0].ID); len(left) != 0 {
+			t.Errorf("pruned event kept %d deliveries", len(left))
+		}
+		remaining, err := gorm.G[models.OutboxEvent](db).Count(ctx, "id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if remaining != 2 {
+			t.Errorf("%d events remain, want 2", remaining)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"estore-server/models"
+)
+
+const (
+	DefaultJobPageSize = 50
+	MaxJobPageSize     = 200
+
+	// DefaultJobRetention is how long succeeded jobs are kept
+	DefaultJobRetention = 7 * 24 * time.Hour
+)
+
+var ErrJobNotDead = errors.New("only dead jobs can be retried")
+
+// JobFilter narrows a job query; zero fields are ignored
+type JobFilter struct {
+	Status models.JobStatus
+	Type   string
+	// Cursor is the NextCursor of the previous page; empty starts from the newest job
+	Cursor string
+	// Limit defaults to DefaultJobPageSize and is capped at MaxJobPageSize
+	Limit int
+}
+
+// JobPage is one page of jobs, newest first
+type JobPage struct {
+	Jobs       []models.Job
+	Total      int64
+	NextCursor string // Empty on the last page
+}
+
+// JobService lets admins inspect the background job queue and revive dead jobs
+type JobService interface {
+	ListJobs(filter JobFilter) (*JobPage, error)
+	GetJob(jobID uint) (*models.Job, error)
+	// CountByStatus returns how many jobs are in each status
+	CountByStatus() (map[models.JobStatus]int64, error)
+	// RetryJob queues a dead job to run again now with a fresh set of attempts
+	RetryJob(actor Actor, jobID uint) (*models.Job, error)
+	ListSchedules() ([]models.JobSchedule, error)
+	// PruneJobs deletes succeeded jobs that finished before finishedBefore and reports how
+	// many it deleted. Dead jobs stay until an admin retries them. It runs as a job and stops
+	// when ctx is cancelled.
+	PruneJobs(ctx context.Context, actor Actor, finishedBefore time.Time) (int64, error)
+}
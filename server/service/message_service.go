@@ -0,0 +1,59 @@
+package service
+
+import (
+	"errors"
+
+	"estore-server/models"
+)
+
+const (
+	DefaultMessagePageSize = 50
+	MaxMessagePageSize     = 200
+)
+
+var (
+	ErrMessageOwnProduct = errors.New("cannot start a conversation about your own product")
+	ErrNotParticipant    = errors.New("you are not part of this conversation")
+)
+
+// Event types pushed to participants' live connections
+const (
+	EventMessageNew  = "message.new"
+	EventMessageRead = "message.read"
+)
+
+// ConversationSummary is a conversation as shown in a user's inbox
+type ConversationSummary struct {
+	Conversation models.Conversation
+	LastMessage  *models.Message // nil before the first message
+	UnreadCount  int64           // Messages from the other participant the user has not read
+}
+
+// MessagePage is one page of a conversation's history
+type MessagePage struct {
+	Messages   []models.Message
+	NextCursor string // Empty on the last page
+}
+
+// ReadReceipt tells a sender that the recipient has read their messages up to UpToID
+type ReadReceipt struct {
+	ConversationID uint `json:"conversation_id"`
+	ReaderID       uint `json:"reader_id"`
+	UpToID         uint `json:"up_to_id"`
+	Count          int  `json:"count"`
+}
+
+// MessageService manages buyer–seller conversations and delivers new messages and read
+// receipts to the participants' live connections
+type MessageService interface {
+	// StartConversation opens the buyer's conversation about a product, or returns the existing one
+	StartConversation(actor Actor, buyerID, productID uint) (*models.Conversation, error)
+	GetConversation(userID, conversationID uint) (*models.Conversation, error)
+	ListConversations(userID uint) ([]ConversationSummary, error)
+	SendMessage(actor Actor, senderID, conversationID uint, body string) (*models.Message, error)
+	// ListMessages returns a page of history, newest first
+	ListMessages(userID, conversationID uint, cursor string, limit int) (*MessagePage, error)
+	// MarkRead marks the other participant's messages up to upToID as read; 0 marks all of them
+	MarkRead(actor Actor, userID, conversationID, upToID uint) (*ReadReceipt, error)
+	UnreadCount(userID uint) (int64, error)
+}
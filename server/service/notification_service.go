@@ -0,0 +1,30 @@
+package service
+
+import "estore-server/models"
+
+const (
+	DefaultNotificationPageSize = 20
+	MaxNotificationPageSize     = 100
+)
+
+// EventNotificationNew is pushed to the user's live connections for each new notification
+const EventNotificationNew = "notification.new"
+
+// NotificationPage is one page of a user's notifications
+type NotificationPage struct {
+	Notifications []models.Notification
+	Total         int64
+	NextCursor    string // Empty on the last page
+}
+
+// NotificationService stores in-app notifications and pushes them to the recipient live.
+// Other services call Notify once the change it reports has been committed.
+type NotificationService interface {
+	Notify(notification *models.Notification) error
+	// ListNotifications returns a page of the user's notifications, newest first
+	ListNotifications(userID uint, unreadOnly bool, cursor string, limit int) (*NotificationPage, error)
+	MarkRead(userID, notificationID uint) (*models.Notification, error)
+	// MarkAllRead marks every unread notification of the user as read and returns how many changed
+	MarkAllRead(userID uint) (int64, error)
+	UnreadCount(userID uint) (int64, error)
+}
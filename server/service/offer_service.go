@@ -0,0 +1,35 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"estore-server/models"
+)
+
+// DefaultOfferTTL is how long an offer or counter-offer stays open without a response
+const DefaultOfferTTL = 48 * time.Hour
+
+var (
+	ErrOfferOwnProduct   = errors.New("cannot make an offer on your own product")
+	ErrOfferInvalidPrice = errors.New("offer price must be greater than zero")
+	ErrOfferExists       = errors.New("you already have an open offer on this product")
+	ErrOfferNotAllowed   = errors.New("you cannot respond to this offer")
+	ErrOfferClosed       = errors.New("offer is no longer open")
+	ErrOfferExpired      = errors.New("offer has expired")
+)
+
+// OfferService negotiates prices between buyers and sellers. A buyer proposes a price and the
+// seller accepts, rejects or counters; after a counter the buyer decides. Each step restarts
+// the expiry clock.
+type OfferService interface {
+	MakeOffer(actor Actor, buyerID, productID uint, price int) (*models.Offer, error)
+	// AcceptOffer, RejectOffer and CounterOffer are taken by the party the offer is waiting on
+	AcceptOffer(actor Actor, userID, offerID uint) (*models.Offer, error)
+	RejectOffer(actor Actor, userID, offerID uint) (*models.Offer, error)
+	CounterOffer(actor Actor, userID, offerID uint, price int) (*models.Offer, error)
+	// WithdrawOffer lets the buyer take back an offer that is still open
+	WithdrawOffer(actor Actor, buyerID, offerID uint) (*models.Offer, error)
+	ListBuyerOffers(buyerID uint, status models.OfferStatus) ([]models.Offer, error)
+	ListSellerOffers(sellerID uint, status models.OfferStatus) ([]models.Offer, error)
+}
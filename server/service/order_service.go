@@ -0,0 +1,36 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"estore-server/models"
+)
+
+var (
+	ErrOrderEmpty              = errors.New("order has no items")
+	ErrOrderOwnProduct         = errors.New("cannot order your own product")
+	ErrOrderProductUnavailable = errors.New("product is no longer available")
+	ErrOrderInvalidQuantity    = errors.New("invalid quantity")
+)
+
+// OrderTransitionError is returned when an order cannot move between two statuses
+type OrderTransitionError struct {
+	From models.OrderStatus
+	To   models.OrderStatus
+}
+
+func (e *OrderTransitionError) Error() string {
+	return fmt.Sprintf("cannot change order status from %s to %s", e.From, e.To)
+}
+
+// OrderService manages orders and enforces their lifecycle
+type OrderService interface {
+	CreateOrder(actor Actor, buyerID, productID uint, quantity int) (*models.Order, error)
+	CheckoutCart(actor Actor, buyerID uint) ([]models.Order, error)
+	GetOrder(orderID uint) (*models.Order, error)
+	ListBuyerOrders(buyerID uint, status models.OrderStatus) ([]models.Order, error)
+	ListSellerOrders(sellerID uint, status models.OrderStatus) ([]models.Order, error)
+	ListAllOrders(status models.OrderStatus) ([]models.Order, error)
+	TransitionOrder(actor Actor, orderID uint, to models.OrderStatus) (*models.Order, error)
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// PasswordResetTokenTTL is how long an emailed reset token stays usable
+	PasswordResetTokenTTL = 30 * time.Minute
+
+	// MaxPasswordResetsPerWindow caps how many reset emails one account receives per
+	// PasswordResetWindow; further requests are dropped silently
+	MaxPasswordResetsPerWindow = 3
+	PasswordResetWindow        = time.Hour
+)
+
+// JobPasswordResetEmail is the background job that mails reset tokens for an address
+const JobPasswordResetEmail = "password_reset.email"
+
+var ErrResetTokenInvalid = errors.New("reset token is invalid or has expired")
+
+// PasswordResetJob is the payload of JobPasswordResetEmail
+type PasswordResetJob struct {
+	Email string `json:"email"`
+}
+
+// PasswordResetService lets users who forgot their password set a new one through email
+type PasswordResetService interface {
+	// RequestReset queues a reset email to every account registered with the address.
+	// It succeeds whether or not such an account exists, and mail is sent in the
+	// background so a failed delivery cannot tell the two apart.
+	RequestReset(email string) error
+	// SendResets issues and mails a reset token to each account using the address, skipping
+	// accounts that already got MaxPasswordResetsPerWindow emails. It runs as a job.
+	SendResets(ctx context.Context, email string) error
+	ResetPassword(actor Actor, token, newPassword string) error
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+
+	"estore-server/models"
+)
+
+var (
+	ErrPaymentNotAllowed = errors.New("you cannot pay for this order")
+	ErrOrderNotPayable   = errors.New("order is not awaiting payment")
+	ErrDuplicateCallback = errors.New("payment callback was already processed")
+	ErrCallbackMismatch  = errors.New("payment callback does not match the payment")
+)
+
+// PaymentService collects payment for orders through the configured payment provider
+type PaymentService interface {
+	// CreatePayment starts paying for one of the buyer's pending orders and returns where
+	// to complete it
+	CreatePayment(actor Actor, buyerID, orderID uint) (*models.Payment, error)
+	GetPayment(paymentID uint) (*models.Payment, error)
+	// HandleCallback verifies a provider webhook and settles the payment it reports on.
+	// A transaction ID is only ever applied once; repeats get ErrDuplicateCallback.
+	HandleCallback(actor Actor, header http.Header, body []byte) (*models.Payment, error)
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"errors"
+
+	"estore-server/models"
+)
+
+const (
+	MaxProductImageSize = 5 << 20 // 5 MiB per uploaded file
+	MaxProductImages    = 9       // Gallery size limit per product
+)
+
+var (
+	ErrImageTooLarge        = errors.New("invalid image: file exceeds 5 MiB")
+	ErrImageUnsupportedType = errors.New("invalid image: only JPEG, PNG, GIF and WebP are supported")
+	ErrTooManyImages        = errors.New("invalid upload: a product can have at most 9 images")
+	ErrImageOrderMismatch   = errors.New("invalid image order: must list every image of the product exactly once")
+)
+
+// ImageUpload is a raw uploaded file awaiting validation
+type ImageUpload struct {
+	Filename string
+	Data     []byte
+}
+
+// ProductImageService manages the ordered image gallery of a product
+type ProductImageService interface {
+	AddImages(actor Actor, productID uint, uploads []ImageUpload) ([]models.ProductImage, error)
+	ListImages(productID uint) ([]models.ProductImage, error)
+	ReorderImages(actor Actor, productID uint, imageIDs []uint) ([]models.ProductImage, error)
+	DeleteImage(actor Actor, productID, imageID uint) error
+}
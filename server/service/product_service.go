@@ -1,12 +1,86 @@
 package service
 
-import "estore-server/models"
+import (
+	"errors"
+
+	"estore-server/models"
+)
+
+var (
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrCategoryNotFound  = errors.New("invalid category: category not found")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrInvalidSort       = errors.New("invalid sort order")
+)
+
+// ProductSort orders search results
+type ProductSort string
+
+const (
+	ProductSortRelevance     ProductSort = "relevance"
+	ProductSortPriceAsc      ProductSort = "price_asc"
+	ProductSortPriceDesc     ProductSort = "price_desc"
+	ProductSortCreatedAtAsc  ProductSort = "created_at_asc"
+	ProductSortCreatedAtDesc ProductSort = "created_at_desc"
+)
+
+const (
+	DefaultProductPageSize = 20
+	MaxProductPageSize     = 100
+)
+
+// ProductFilter narrows a product search; zero values apply no filtering
+type ProductFilter struct {
+	Keyword           string
+	IncludeOutOfStock bool
+	CategoryIDs       []uint // Products in any of these categories
+	SellerID          uint
+	MinPrice          *int
+	MaxPrice          *int
+
+	// Sort defaults to relevance for keyword searches and newest first otherwise
+	Sort ProductSort
+	// Cursor is the NextCursor of the previous page; empty starts from the beginning
+	Cursor string
+	// Limit defaults to DefaultProductPageSize and is capped at MaxProductPageSize
+	Limit int
+}
+
+// ProductUpdate holds the editable fields of a product
+type ProductUpdate struct {
+	Name        string
+	Description string
+	Price       int
+	// Stock is only written when set, so an edit never undoes a concurrent reservation
+	Stock *int
+	// CategoryID moves the product into a category; nil keeps the current one unless
+	// ClearCategory is set
+	CategoryID    *uint
+	ClearCategory bool
+}
+
+// ProductPage is one page of search results
+type ProductPage struct {
+	Products   []models.Product
+	Total      int64  // Matches across all pages
+	NextCursor string // Empty on the last page
+}
 
 // ProductService exposes product CRUD operations
 type ProductService interface {
-	CreateProduct(userID uint, name, description string, price int) (*models.Product, error)
+	CreateProduct(actor Actor, userID uint, name, description string, price, stock int, categoryID *uint) (*models.Product, error)
 	GetProduct(productID uint) (*models.Product, error)
-	UpdateProduct(productID uint, name, description string, price int) (*models.Product, error)
-	DeleteProduct(productID uint) error
-	SearchProducts(keyword string) ([]models.Product, error)
+	UpdateProduct(actor Actor, productID uint, update ProductUpdate) (*models.Product, error)
+	// DeleteProduct trashes a product on behalf of actor; the seller is notified when
+	// someone else, such as a moderator, removes it
+	DeleteProduct(actor Actor, productID uint) error
+	SearchProducts(filter ProductFilter) (*ProductPage, error)
+	ReserveStock(productID uint, quantity int) error
+	ReleaseStock(productID uint, quantity int) error
+	// RebuildSearchIndex reindexes every product from the database into this server's index
+	// and reports how many were indexed
+	RebuildSearchIndex(actor Actor) (int, error)
+	// RequestSearchRebuild bumps the rebuild generation, so every server rebuilds its index
+	// the next time it polls, and returns the new generation
+	RequestSearchRebuild(actor Actor) (int64, error)
 }
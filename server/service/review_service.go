@@ -0,0 +1,40 @@
+package service
+
+import (
+	"errors"
+
+	"estore-server/models"
+)
+
+const (
+	MinReviewRating = 1
+	MaxReviewRating = 5
+
+	DefaultReviewPageSize = 20
+	MaxReviewPageSize     = 100
+)
+
+var (
+	ErrSelfReview       = errors.New("you cannot review yourself")
+	ErrReviewExists     = errors.New("you have already reviewed this seller")
+	ErrInvalidRating    = errors.New("rating must be between 1 and 5")
+	ErrReplyExists      = errors.New("this review already has a reply")
+	ErrReviewNotAllowed = errors.New("you cannot change this review")
+)
+
+// ReviewPage is one page of a seller's reviews
+type ReviewPage struct {
+	Reviews    []models.Review
+	Total      int64
+	NextCursor string // Empty on the last page
+}
+
+// ReviewService manages seller reviews and keeps each seller's rating aggregate in step
+type ReviewService interface {
+	CreateReview(actor Actor, reviewerID, sellerID uint, rating int, comment string) (*models.Review, error)
+	UpdateReview(actor Actor, reviewerID, reviewID uint, rating int, comment string) (*models.Review, error)
+	DeleteReview(actor Actor, reviewerID, reviewID uint) error
+	ReplyToReview(actor Actor, sellerID, reviewID uint, reply string) (*models.Review, error)
+	// ListReviews returns a page of the seller's reviews, newest first
+	ListReviews(sellerID uint, cursor string, limit int) (*ReviewPage, error)
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"errors"
+
+	"estore-server/models"
+)
+
+var (
+	ErrRoleNotFound = errors.New("role not found")
+	ErrLastAdmin    = errors.New("cannot remove the admin role from the last admin")
+)
+
+// RoleService manages roles, their permissions and which users hold them
+type RoleService interface {
+	// SeedDefaultRoles creates the built-in roles and resets their permissions to the defaults
+	SeedDefaultRoles() error
+	ListRoles() ([]models.Role, error)
+	GetUserRoles(userID uint) ([]models.Role, error)
+	AssignRole(actor Actor, userID uint, roleName string) error
+	RemoveRole(actor Actor, userID uint, roleName string) error
+	// Permissions returns the union of the permissions granted by the named roles
+	Permissions(roleNames []string) (map[string]bool, error)
+}
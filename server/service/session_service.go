@@ -0,0 +1,25 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"estore-server/models"
+)
+
+var ErrSessionInvalid = errors.New("session has expired or been revoked")
+
+// SessionService persists login sessions so tokens can be revoked before they expire
+type SessionService interface {
+	CreateSession(userID uint, userAgent, ip string, expiresAt time.Time) (*models.Session, error)
+	AttachRefreshToken(sessionID, refreshToken string, expiresAt time.Time) error
+	GetByRefreshToken(refreshToken string) (*models.Session, error)
+	RevokeByRefreshToken(refreshToken string) error
+	ValidateSession(sessionID string, userID uint, ip string) (*models.Session, error)
+	ListSessions(userID uint) ([]models.Session, error)
+	RevokeSession(actor Actor, userID uint, sessionID string) error
+	RevokeOtherSessions(actor Actor, userID uint, keepSessionID string) (int, error)
+	RevokeAllSessions(userID uint) error
+	PurgeExpired() (int, error)
+	CountActive() (int, error)
+}
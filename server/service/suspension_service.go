@@ -0,0 +1,38 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"estore-server/models"
+)
+
+var (
+	ErrAlreadySuspended = errors.New("user is already suspended")
+	ErrNotSuspended     = errors.New("user is not suspended")
+	ErrSuspensionExpiry = errors.New("suspension expiry must be in the future")
+)
+
+// SuspendedError is returned when a suspended user logs in or presents a token.
+// It carries the suspension so the client can be told why and until when.
+type SuspendedError struct {
+	Suspension *models.Suspension
+}
+
+func (e *SuspendedError) Error() string {
+	if e.Suspension.ExpiresAt != nil {
+		return fmt.Sprintf("account suspended until %s: %s", e.Suspension.ExpiresAt.Format(time.RFC3339), e.Suspension.Reason)
+	}
+	return "account suspended: " + e.Suspension.Reason
+}
+
+// SuspensionService suspends users and reports whether a suspension is in force.
+// Suspensions with an expiry lift themselves; no job has to clear them.
+type SuspensionService interface {
+	SuspendUser(actor Actor, userID, issuedBy uint, reason string, expiresAt *time.Time) (*models.Suspension, error)
+	LiftSuspension(actor Actor, userID, liftedBy uint) (*models.Suspension, error)
+	// ActiveSuspension returns the suspension in force for the user, or nil if there is none
+	ActiveSuspension(userID uint) (*models.Suspension, error)
+	ListSuspensions(userID uint) ([]models.Suspension, error)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"estore-server/models"
+)
+
+// DefaultTrashRetention is how long deleted products and users stay restorable
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+var ErrOwnerDeleted = errors.New("the product's seller is deleted; restore the seller instead")
+
+// PurgeResult counts the rows a trash purge removed for good
+type PurgeResult struct {
+	Users    int
+	Products int
+}
+
+// TrashService lists, restores and purges soft-deleted products and users
+type TrashService interface {
+	ListDeletedProducts() ([]models.Product, error)
+	ListDeletedUsers() ([]models.User, error)
+	RestoreProduct(actor Actor, productID uint) (*models.Product, error)
+	RestoreUser(actor Actor, userID uint) (*models.User, error)
+	// Purge permanently removes everything deleted before deletedBefore. It stops between
+	// items once ctx is cancelled, keeping what was already purged.
+	Purge(ctx context.Context, actor Actor, deletedBefore time.Time) (*PurgeResult, error)
+}
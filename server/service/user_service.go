@@ -8,7 +8,7 @@ import (
 type UserService interface {
 	GetUser(userID uint) (*models.User, error)
 	GetAllUsers() ([]models.User, error)
-	UpdateUser(userID uint, username, email, phone, address string) (*models.User, error)
-	UpdateUserPassword(userID uint, oldPassword, newPassword string) error
-	DeleteUser(userID uint) error
+	UpdateUser(actor Actor, userID uint, username, email, phone, address string) (*models.User, error)
+	UpdateUserPassword(actor Actor, userID uint, oldPassword, newPassword string) error
+	DeleteUser(actor Actor, userID uint) error
 }
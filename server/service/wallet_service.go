@@ -0,0 +1,67 @@
+package service
+
+import (
+	"errors"
+
+	"estore-server/models"
+)
+
+const (
+	DefaultStatementPageSize = 20
+	MaxStatementPageSize     = 100
+)
+
+var (
+	ErrInvalidAmount     = errors.New("amount must be positive")
+	ErrInsufficientFunds = errors.New("insufficient wallet balance")
+	ErrSelfTransfer      = errors.New("you cannot transfer to yourself")
+)
+
+// StatementLine is one change to a wallet's balance with the transaction behind it
+type StatementLine struct {
+	models.LedgerEntry
+	Kind models.LedgerKind `json:"kind"`
+	Memo string            `json:"memo"`
+	// CounterpartyID is the other user of a transfer; nil for deposits and withdrawals
+	CounterpartyID *uint `json:"counterparty_id"`
+}
+
+// StatementPage is one page of a wallet statement, newest first
+type StatementPage struct {
+	Lines      []StatementLine
+	Total      int64
+	NextCursor string // Empty on the last page
+}
+
+// WalletDrift is a wallet whose stored balance disagrees with the sum of its entries
+type WalletDrift struct {
+	WalletID  uint `json:"wallet_id"`
+	Balance   int  `json:"balance"`
+	LedgerSum int  `json:"ledger_sum"`
+}
+
+// ReconciliationReport is the result of checking the ledger. It is balanced when every
+// transaction sums to zero, the whole ledger sums to zero and every wallet balance matches
+// its entries.
+type ReconciliationReport struct {
+	Balanced               bool          `json:"balanced"`
+	Transactions           int64         `json:"transactions"`
+	LedgerTotal            int           `json:"ledger_total"`
+	UnbalancedTransactions []uint        `json:"unbalanced_transactions"`
+	DriftedWallets         []WalletDrift `json:"drifted_wallets"`
+}
+
+// WalletService moves money between user wallets through the double-entry ledger. Each
+// operation runs in one database transaction and never leaves a user balance negative.
+type WalletService interface {
+	// GetWallet returns the user's wallet, opening an empty one on first use
+	GetWallet(userID uint) (*models.Wallet, error)
+	// Deposit credits the user with money from outside the app
+	Deposit(actor Actor, userID uint, amount int, memo string) (*models.LedgerTransaction, error)
+	// Withdraw pays money out of the user's wallet to outside the app
+	Withdraw(actor Actor, userID uint, amount int, memo string) (*models.LedgerTransaction, error)
+	Transfer(actor Actor, fromUserID, toUserID uint, amount int, memo string) (*models.LedgerTransaction, error)
+	// Statement returns a page of the user's ledger entries, newest first
+	Statement(userID uint, cursor string, limit int) (*StatementPage, error)
+	Reconcile() (*ReconciliationReport, error)
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"estore-server/models"
+)
+
+const (
+	DefaultWebhookDeliveryPageSize = 50
+	MaxWebhookDeliveryPageSize     = 200
+
+	// DefaultWebhookRetention is how long dispatched events and their delivery logs are kept
+	DefaultWebhookRetention = 30 * 24 * time.Hour
+)
+
+// Background jobs that move events from the outbox to webhook endpoints
+const (
+	JobWebhookFanOut  = "webhook.fanout"
+	JobWebhookDeliver = "webhook.deliver"
+)
+
+// WebhookSignatureHeader carries "t=<unix time>,v1=<hex HMAC-SHA256 of "<t>.<body>">",
+// keyed with the endpoint's secret
+const WebhookSignatureHeader = "X-Estore-Signature"
+
+var ErrUnknownWebhookEvent = errors.New("unknown webhook event type")
+
+// WebhookJob is the payload of the webhook jobs; each job sets the ID it works on
+type WebhookJob struct {
+	EventID    uint `json:"event_id,omitempty"`
+	DeliveryID uint `json:"delivery_id,omitempty"`
+}
+
+// WebhookDeliveryPage is one page of an endpoint's delivery log, newest first
+type WebhookDeliveryPage struct {
+	Deliveries []models.WebhookDelivery
+	Total      int64
+	NextCursor string // Empty on the last page
+}
+
+// WebhookService manages webhook endpoints and delivers outbox events to them
+type WebhookService interface {
+	ListEndpoints() ([]models.WebhookEndpoint, error)
+	// CreateEndpoint registers an endpoint with a freshly generated signing secret
+	CreateEndpoint(actor Actor, url, description string, events []string) (*models.WebhookEndpoint, error)
+	UpdateEndpoint(actor Actor, endpointID uint, url, description string, events []string, active bool) (*models.WebhookEndpoint, error)
+	DeleteEndpoint(actor Actor, endpointID uint) error
+	ListDeliveries(endpointID uint, cursor string, limit int) (*WebhookDeliveryPage, error)
+	// Redeliver sends a delivery's event to its endpoint again as a new delivery
+	Redeliver(actor Actor, deliveryID uint) (*models.WebhookDelivery, error)
+
+	// FanOut queues a delivery of the event to every active endpoint subscribed to it. It
+	// runs as a job and stops when ctx is cancelled.
+	FanOut(ctx context.Context, eventID uint) error
+	// Deliver makes one attempt to send a delivery, returning an error if it failed. It runs
+	// as a job; cancelling ctx aborts both the database work and the HTTP request.
+	Deliver(ctx context.Context, deliveryID uint) error
+	// PruneEvents deletes outbox events dispatched before dispatchedBefore, along with their
+	// delivery logs, unless a delivery of the event has been attempted since. It reports how
+	// many events it deleted. It runs as a job and stops when ctx is cancelled.
+	PruneEvents(ctx context.Context, actor Actor, dispatchedBefore time.Time) (int64, error)
+}
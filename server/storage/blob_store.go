@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore stores opaque binary objects such as uploaded images under slash-separated keys.
+// Implementations must be safe for concurrent use.
+type BlobStore interface {
+	// Put writes the object, replacing any existing object with the same key
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Open returns a reader for the object, or ErrBlobNotFound
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	// URL returns the address clients use to fetch the object
+	URL(key string) string
+}
@@ -0,0 +1,98 @@
+// Package testdb opens the databases that tests run against. SQLite always runs in memory;
+// MySQL and PostgreSQL run when TEST_MYSQL_DSN or TEST_POSTGRES_DSN points at a database
+// the tests may wipe, e.g. "root:secret@tcp(localhost:3306)/estore_test?parseTime=True&loc=Local"
+// or "host=localhost user=postgres password=secret dbname=estore_test sslmode=disable".
+package testdb
+
+import (
+	"os"
+	"testing"
+
+	"estore-server/migrations"
+	"estore-server/models"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Driver is a database tests can run against
+type Driver struct {
+	Name string
+	// DSNEnv names the environment variable holding the DSN; SQLite needs none
+	DSNEnv string
+	open   func(dsn string) gorm.Dialector
+}
+
+// Drivers lists every supported database
+var Drivers = []Driver{
+	{Name: "sqlite", open: func(string) gorm.Dialector { return sqlite.Open("file::memory:?_foreign_keys=on") }},
+	{Name: "mysql", DSNEnv: "TEST_MYSQL_DSN", open: mysql.Open},
+	{Name: "postgres", DSNEnv: "TEST_POSTGRES_DSN", open: postgres.Open},
+}
+
+// ForEach runs test as a subtest against a freshly migrated database on every driver
+func ForEach(t *testing.T, test func(t *testing.T, db *gorm.DB)) {
+	for _, driver := range Drivers {
+		t.Run(driver.Name, func(t *testing.T) {
+			test(t, Migrated(t, driver))
+		})
+	}
+}
+
+// Open connects to driver's database, skipping the test when it is not configured, and
+// drops every table the migrations created, leaving an empty database
+func Open(t *testing.T, driver Driver) *gorm.DB {
+	t.Helper()
+
+	var dsn string
+	if driver.DSNEnv != "" {
+		dsn = os.Getenv(driver.DSNEnv)
+		if dsn == "" {
+			t.Skipf("%s is not set", driver.DSNEnv)
+		}
+	}
+
+	db, err := gorm.Open(driver.open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if driver.DSNEnv == "" {
+		// Every connection to ":memory:" would get its own empty database
+		sqlDB.SetMaxOpenConns(1)
+		return db
+	}
+
+	// A shared database still holds whatever the previous test left behind
+	if _, err := migrations.Down(db, len(migrations.All)); err != nil {
+		t.Fatalf("reset database: %v", err)
+	}
+	if err := db.Migrator().DropTable(&migrations.SchemaMigration{}); err != nil {
+		t.Fatalf("reset database: %v", err)
+	}
+	return db
+}
+
+// Migrated opens driver's database like Open and applies every migration
+func Migrated(t *testing.T, driver Driver) *gorm.DB {
+	t.Helper()
+
+	db := Open(t, driver)
+	if err := db.SetupJoinTable(&models.User{}, "Roles", &models.UserRole{}); err != nil {
+		t.Fatalf("set up user roles: %v", err)
+	}
+	if _, err := migrations.Up(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
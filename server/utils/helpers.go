@@ -38,3 +38,18 @@ func GetUserFromCtx(c *gin.Context) (*models.User, error) {
 
 	return user, nil
 }
+
+// GetSessionIDFromCtx returns the ID of the session the request's token belongs to.
+func GetSessionIDFromCtx(c *gin.Context) string {
+	return c.GetString(middleware.SessionIDKey)
+}
+
+// HasPermission reports whether the authenticated user's roles grant permission.
+func HasPermission(c *gin.Context, permission string) bool {
+	return middleware.HasPermission(c, permission)
+}
+
+// GetRequestID returns the ID assigned to the request by RequestIDMiddleware.
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(middleware.RequestIDKey)
+}